@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -27,7 +29,26 @@ type Config struct {
 	CloudflareAccessKeySecret string
 	CloudflareBucketName      string
 	CloudflarePublicURL       string
-
+	StorageBackend            string
+	S3Endpoint                string
+	S3Region                  string
+	S3AccessKeyID             string
+	S3AccessKeySecret         string
+	S3BucketName              string
+	S3PublicURL               string
+	S3UsePathStyle            bool
+	LocalStorageDir           string
+	LocalStorageURL           string
+	LocalStorageSecret        string
+	OrphanMediaRetainDays     int
+	OrphanMediaSweepDelete    bool
+	AppURL                    string
+	TrustedProxies            []string
+	ShareLinkSecret           string
+	CalendarFeedSecret        string
+	AadharEncryptionKeys      string
+	AadharKeyVersion          int
+	AadharLookupKey           string
 }
 
 func LoadConfig() Config {
@@ -51,5 +72,40 @@ func LoadConfig() Config {
 		CloudflareAccessKeySecret: os.Getenv("CLOUDFARE_ACCESS_KEY_SECRET"),
 		CloudflareBucketName:      os.Getenv("CLOUDFARE_BUCKET_NAME"),
 		CloudflarePublicURL:       os.Getenv("CLOUDFARE_PUBLIC_URL"),
+		StorageBackend:            getEnvDefault("STORAGE_BACKEND", "r2"),
+		S3Endpoint:                os.Getenv("S3_ENDPOINT"),
+		S3Region:                  os.Getenv("S3_REGION"),
+		S3AccessKeyID:             os.Getenv("S3_ACCESS_KEY_ID"),
+		S3AccessKeySecret:         os.Getenv("S3_ACCESS_KEY_SECRET"),
+		S3BucketName:              os.Getenv("S3_BUCKET_NAME"),
+		S3PublicURL:               os.Getenv("S3_PUBLIC_URL"),
+		S3UsePathStyle:            os.Getenv("S3_USE_PATH_STYLE") == "true",
+		LocalStorageDir:           getEnvDefault("LOCAL_STORAGE_DIR", "./storage"),
+		LocalStorageURL:           getEnvDefault("LOCAL_STORAGE_URL", "http://localhost:"+os.Getenv("PORT")+"/storage"),
+		LocalStorageSecret:        os.Getenv("LOCAL_STORAGE_SECRET"),
+		OrphanMediaRetainDays:     getEnvInt("ORPHAN_MEDIA_RETAIN_DAYS", 30),
+		OrphanMediaSweepDelete:    os.Getenv("ORPHAN_MEDIA_SWEEP_DELETE") == "true",
+		AppURL:                    getEnvDefault("APP_URL", "http://localhost:"+os.Getenv("PORT")),
+		TrustedProxies:            strings.Split(os.Getenv("TRUSTED_PROXIES"), ","),
+		ShareLinkSecret:           os.Getenv("SHARE_LINK_SECRET"),
+		CalendarFeedSecret:        os.Getenv("CALENDAR_FEED_SECRET"),
+		AadharEncryptionKeys:      os.Getenv("AADHAR_ENCRYPTION_KEYS"),
+		AadharKeyVersion:          getEnvInt("AADHAR_KEY_VERSION", 0),
+		AadharLookupKey:           os.Getenv("AADHAR_LOOKUP_KEY"),
+	}
+}
+
+func getEnvDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
 	}
+	return value
 }
@@ -0,0 +1,48 @@
+package constants
+
+const (
+	Furnished     = "furnished"
+	SemiFurnished = "semi_furnished"
+	Unfurnished   = "unfurnished"
+)
+
+var FurnishingStatuses = []string{Furnished, SemiFurnished, Unfurnished}
+
+var Facings = []string{
+	"north", "south", "east", "west",
+	"north_east", "north_west", "south_east", "south_west",
+}
+
+var Amenities = []string{
+	"lift", "parking", "power_backup", "security", "cctv", "gym",
+	"swimming_pool", "park", "club_house", "water_supply", "gas_pipeline",
+	"intercom", "fire_safety", "rain_water_harvesting", "visitor_parking",
+	"children_play_area", "balcony", "modular_kitchen", "air_conditioning",
+}
+
+func IsValidFurnishing(furnishing string) bool {
+	for _, allowed := range FurnishingStatuses {
+		if furnishing == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidFacing(facing string) bool {
+	for _, allowed := range Facings {
+		if facing == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidAmenity(amenity string) bool {
+	for _, allowed := range Amenities {
+		if amenity == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package constants
+
+const (
+	ListingTypeSale = "sale"
+	ListingTypeRent = "rent"
+)
+
+var ListingTypes = []string{ListingTypeSale, ListingTypeRent}
+
+func IsValidListingType(listingType string) bool {
+	for _, allowed := range ListingTypes {
+		if listingType == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package constants
+
+import "strings"
+
+const (
+	MediaPurposePropertyPhoto = "property_photo"
+	MediaPurposePropertyVideo = "property_video"
+	MediaPurposeClientDoc     = "client_doc"
+	MediaPurposeKYC           = "kyc"
+	MediaPurposeDealerLogo    = "dealer_logo"
+)
+
+// PrivateMediaPrefix holds objects that are only reachable through short-lived signed URLs.
+// The bucket's public domain must deny this prefix (a WAF rule on the R2 custom domain).
+const PrivateMediaPrefix = "private/"
+
+// MediaRule limits what may be uploaded for a purpose and how much of it each owner may store
+type MediaRule struct {
+	KeyPrefix     string
+	MaxBytes      int64
+	ContentTypes  []string
+	MaxFiles      int64
+	MaxTotalBytes int64
+}
+
+var MediaRules = map[string]MediaRule{
+	MediaPurposePropertyPhoto: {
+		KeyPrefix:     "properties",
+		MaxBytes:      15 << 20,
+		ContentTypes:  []string{"image/jpeg", "image/png", "image/webp", "image/heic"},
+		MaxFiles:      5000,
+		MaxTotalBytes: 10 << 30,
+	},
+	MediaPurposePropertyVideo: {
+		KeyPrefix:     "properties",
+		MaxBytes:      500 << 20,
+		ContentTypes:  []string{"video/mp4", "video/quicktime", "video/webm"},
+		MaxFiles:      200,
+		MaxTotalBytes: 50 << 30,
+	},
+	MediaPurposeClientDoc: {
+		KeyPrefix:     PrivateMediaPrefix + "clients",
+		MaxBytes:      10 << 20,
+		ContentTypes:  []string{"application/pdf", "image/jpeg", "image/png"},
+		MaxFiles:      5000,
+		MaxTotalBytes: 5 << 30,
+	},
+	MediaPurposeKYC: {
+		KeyPrefix:     PrivateMediaPrefix + "kyc",
+		MaxBytes:      5 << 20,
+		ContentTypes:  []string{"application/pdf", "image/jpeg", "image/png"},
+		MaxFiles:      2000,
+		MaxTotalBytes: 2 << 30,
+	},
+	MediaPurposeDealerLogo: {
+		KeyPrefix:     "dealers",
+		MaxBytes:      2 << 20,
+		ContentTypes:  []string{"image/png", "image/jpeg"},
+		MaxFiles:      20,
+		MaxTotalBytes: 40 << 20,
+	},
+}
+
+var MediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/heic":      ".heic",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+	"application/pdf": ".pdf",
+}
+
+func IsValidMediaPurpose(purpose string) bool {
+	_, ok := MediaRules[purpose]
+	return ok
+}
+
+func IsAllowedMediaType(purpose string, contentType string) bool {
+	for _, allowed := range MediaRules[purpose].ContentTypes {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func IsPrivateMediaKey(key string) bool {
+	return strings.HasPrefix(key, PrivateMediaPrefix)
+}
@@ -0,0 +1,27 @@
+package constants
+
+const (
+	WatermarkTypeText = "text"
+	WatermarkTypeLogo = "logo"
+)
+
+const (
+	WatermarkTopLeft     = "top_left"
+	WatermarkTopRight    = "top_right"
+	WatermarkBottomLeft  = "bottom_left"
+	WatermarkBottomRight = "bottom_right"
+	WatermarkCenter      = "center"
+)
+
+var WatermarkPositions = []string{
+	WatermarkTopLeft, WatermarkTopRight, WatermarkBottomLeft, WatermarkBottomRight, WatermarkCenter,
+}
+
+func IsValidWatermarkPosition(position string) bool {
+	for _, allowed := range WatermarkPositions {
+		if position == allowed {
+			return true
+		}
+	}
+	return false
+}
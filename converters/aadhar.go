@@ -0,0 +1,84 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToDomainStoredAadhar(mongoLead mongoModels.Lead) models.StoredAadhar {
+	return models.StoredAadhar{
+		Ciphertext: mongoLead.AadharNumber,
+		KeyVersion: mongoLead.AadharKeyVersion,
+		Last4:      mongoLead.AadharLast4,
+		Hash:       mongoLead.AadharHash,
+		HashKeyID:  mongoLead.AadharHashKey,
+		Photo:      mongoLead.AadharPhoto,
+	}
+}
+
+// SetLeadAadhar puts the lead's stored Aadhaar on the domain lead. Responses only ever carry the
+// masked number and whether there is a photo.
+func SetLeadAadhar(lead *models.Lead, mongoLead mongoModels.Lead) {
+	stored := ToDomainStoredAadhar(mongoLead)
+	last4 := stored.Last4
+	if last4 == "" && stored.KeyVersion == 0 {
+		// Saved before encryption; the plain number is still there to mask
+		if number := utils.NormalizeAadhar(stored.Ciphertext); number != "" {
+			last4 = number[8:]
+		}
+	}
+	lead.StoredAadhar = stored
+	lead.AadharNumber = utils.MaskAadhar(last4)
+	lead.AadharPhoto = ""
+	lead.HasAadharPhoto = stored.Photo != ""
+}
+
+// ToMongoAadharNumberUpdate lists the lead fields that hold the Aadhaar number, for $set
+func ToMongoAadharNumberUpdate(stored models.StoredAadhar) map[string]interface{} {
+	return map[string]interface{}{
+		"aadhar_number":      stored.Ciphertext,
+		"aadhar_key_version": stored.KeyVersion,
+		"aadhar_last4":       stored.Last4,
+		"aadhar_hash":        stored.Hash,
+		"aadhar_hash_key":    stored.HashKeyID,
+	}
+}
+
+func ToMongoAadharAccessLog(entry models.AadharAccessLog) (mongoModels.AadharAccessLog, error) {
+	leadObjectID, err := primitive.ObjectIDFromHex(entry.LeadID)
+	if err != nil {
+		return mongoModels.AadharAccessLog{}, err
+	}
+
+	return mongoModels.AadharAccessLog{
+		LeadID:     leadObjectID,
+		RevealedBy: entry.RevealedBy,
+		Reason:     entry.Reason,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RevealedAt: entry.RevealedAt,
+	}, nil
+}
+
+func ToDomainAadharAccessLog(entry mongoModels.AadharAccessLog) models.AadharAccessLog {
+	return models.AadharAccessLog{
+		ID:         entry.ID.Hex(),
+		LeadID:     entry.LeadID.Hex(),
+		RevealedBy: entry.RevealedBy,
+		Reason:     entry.Reason,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RevealedAt: entry.RevealedAt,
+	}
+}
+
+func ToDomainAadharAccessLogSlice(entries []mongoModels.AadharAccessLog) []models.AadharAccessLog {
+	result := make([]models.AadharAccessLog, len(entries))
+	for i, entry := range entries {
+		result[i] = ToDomainAadharAccessLog(entry)
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoActivity(activity models.Activity) (mongoModels.Activity, error) {
+	subjectID, err := primitive.ObjectIDFromHex(activity.SubjectID)
+	if err != nil {
+		return mongoModels.Activity{}, err
+	}
+	propertyID, err := optionalObjectID(activity.PropertyID)
+	if err != nil {
+		return mongoModels.Activity{}, err
+	}
+
+	return mongoModels.Activity{
+		SubjectType: activity.SubjectType,
+		SubjectID:   subjectID,
+		Type:        activity.Type,
+		Body:        activity.Body,
+		PropertyID:  propertyID,
+		AuthorID:    activity.AuthorID,
+		System:      activity.System,
+		OccurredAt:  activity.OccurredAt,
+		CreatedAt:   activity.CreatedAt,
+	}, nil
+}
+
+func ToDomainActivity(activity mongoModels.Activity) models.Activity {
+	domain := models.Activity{
+		ID:          activity.ID.Hex(),
+		SubjectType: activity.SubjectType,
+		SubjectID:   activity.SubjectID.Hex(),
+		Type:        activity.Type,
+		Body:        activity.Body,
+		AuthorID:    activity.AuthorID,
+		System:      activity.System,
+		OccurredAt:  activity.OccurredAt,
+		CreatedAt:   activity.CreatedAt,
+	}
+	if activity.PropertyID != nil {
+		domain.PropertyID = activity.PropertyID.Hex()
+	}
+	return domain
+}
+
+func ToDomainActivitySlice(activities []mongoModels.Activity) []models.Activity {
+	result := make([]models.Activity, len(activities))
+	for i, activity := range activities {
+		result[i] = ToDomainActivity(activity)
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoCoBrokeAgreement(agreement models.CoBrokeAgreement) (mongoModels.CoBrokeAgreement, error) {
+	propertyObjectID, err := primitive.ObjectIDFromHex(agreement.PropertyID)
+	if err != nil {
+		return mongoModels.CoBrokeAgreement{}, err
+	}
+	listingDealerObjectID, err := primitive.ObjectIDFromHex(agreement.ListingDealerID)
+	if err != nil {
+		return mongoModels.CoBrokeAgreement{}, err
+	}
+	partnerDealerObjectID, err := primitive.ObjectIDFromHex(agreement.PartnerDealerID)
+	if err != nil {
+		return mongoModels.CoBrokeAgreement{}, err
+	}
+
+	return mongoModels.CoBrokeAgreement{
+		PropertyID:      propertyObjectID,
+		PropertyNumber:  agreement.PropertyNumber,
+		PropertyTitle:   agreement.PropertyTitle,
+		ListingDealerID: listingDealerObjectID,
+		PartnerDealerID: partnerDealerObjectID,
+		CommissionSplit: agreement.CommissionSplit,
+		Terms:           agreement.Terms,
+		Message:         agreement.Message,
+		Status:          agreement.Status,
+		ResponseNote:    agreement.ResponseNote,
+		RespondedAt:     agreement.RespondedAt,
+		ClosingNote:     agreement.ClosingNote,
+		ClosedAt:        agreement.ClosedAt,
+		CreatedAt:       agreement.CreatedAt,
+		UpdatedAt:       agreement.UpdatedAt,
+	}, nil
+}
+
+func ToDomainCoBrokeAgreement(agreement mongoModels.CoBrokeAgreement) models.CoBrokeAgreement {
+	return models.CoBrokeAgreement{
+		ID:              agreement.ID.Hex(),
+		PropertyID:      agreement.PropertyID.Hex(),
+		PropertyNumber:  agreement.PropertyNumber,
+		PropertyTitle:   agreement.PropertyTitle,
+		ListingDealerID: agreement.ListingDealerID.Hex(),
+		PartnerDealerID: agreement.PartnerDealerID.Hex(),
+		CommissionSplit: agreement.CommissionSplit,
+		Terms:           agreement.Terms,
+		Message:         agreement.Message,
+		Status:          agreement.Status,
+		ResponseNote:    agreement.ResponseNote,
+		RespondedAt:     agreement.RespondedAt,
+		ClosingNote:     agreement.ClosingNote,
+		ClosedAt:        agreement.ClosedAt,
+		CreatedAt:       agreement.CreatedAt,
+		UpdatedAt:       agreement.UpdatedAt,
+	}
+}
+
+func ToDomainCoBrokeAgreementSlice(agreements []mongoModels.CoBrokeAgreement) []models.CoBrokeAgreement {
+	result := make([]models.CoBrokeAgreement, len(agreements))
+	for i, agreement := range agreements {
+		result[i] = ToDomainCoBrokeAgreement(agreement)
+	}
+	return result
+}
+
+// ToCoBrokeListing strips a pooled listing down to what other dealers may see
+func ToCoBrokeListing(property models.Property, dealer models.SharedDealer) models.CoBrokeListing {
+	listing := models.CoBrokeListing{
+		PropertyID: property.ID,
+		Dealer:     dealer,
+		Property:   ToSharedProperty(property),
+	}
+	if property.CoBroke != nil {
+		listing.CommissionSplit = property.CoBroke.CommissionSplit
+		listing.Terms = property.CoBroke.Terms
+	}
+	return listing
+}
+
+func ToMongoCoBrokeTerms(terms *models.CoBrokeTerms) *mongoModels.CoBrokeTerms {
+	if terms == nil {
+		return nil
+	}
+	return &mongoModels.CoBrokeTerms{
+		Enabled:         terms.Enabled,
+		CommissionSplit: terms.CommissionSplit,
+		Terms:           terms.Terms,
+		UpdatedAt:       terms.UpdatedAt,
+	}
+}
+
+func ToDomainCoBrokeTerms(terms *mongoModels.CoBrokeTerms) *models.CoBrokeTerms {
+	if terms == nil {
+		return nil
+	}
+	return &models.CoBrokeTerms{
+		Enabled:         terms.Enabled,
+		CommissionSplit: terms.CommissionSplit,
+		Terms:           terms.Terms,
+		UpdatedAt:       terms.UpdatedAt,
+	}
+}
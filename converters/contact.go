@@ -0,0 +1,68 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoLeadMerge(merge models.LeadMerge) (mongoModels.LeadMerge, error) {
+	leadID, err := primitive.ObjectIDFromHex(merge.LeadID)
+	if err != nil {
+		return mongoModels.LeadMerge{}, err
+	}
+	mergedLeadID, err := primitive.ObjectIDFromHex(merge.MergedLeadID)
+	if err != nil {
+		return mongoModels.LeadMerge{}, err
+	}
+
+	return mongoModels.LeadMerge{
+		LeadID:            leadID,
+		MergedLeadID:      mergedLeadID,
+		MergedName:        merge.MergedName,
+		MergedPhone:       merge.MergedPhone,
+		MergedRequirement: merge.MergedRequirement,
+		InterestsAdded:    merge.InterestsAdded,
+		InterestsCombined: merge.InterestsCombined,
+		MergedBy:          merge.MergedBy,
+		CreatedAt:         merge.CreatedAt,
+	}, nil
+}
+
+func ToDomainLeadMerge(merge mongoModels.LeadMerge) models.LeadMerge {
+	return models.LeadMerge{
+		ID:                merge.ID.Hex(),
+		LeadID:            merge.LeadID.Hex(),
+		MergedLeadID:      merge.MergedLeadID.Hex(),
+		MergedName:        merge.MergedName,
+		MergedPhone:       merge.MergedPhone,
+		MergedRequirement: merge.MergedRequirement,
+		InterestsAdded:    merge.InterestsAdded,
+		InterestsCombined: merge.InterestsCombined,
+		MergedBy:          merge.MergedBy,
+		CreatedAt:         merge.CreatedAt,
+	}
+}
+
+func ToDomainLeadMergeSlice(merges []mongoModels.LeadMerge) []models.LeadMerge {
+	result := make([]models.LeadMerge, len(merges))
+	for i, merge := range merges {
+		result[i] = ToDomainLeadMerge(merge)
+	}
+	return result
+}
+
+func ToDomainContactSummarySlice(summaries []mongoModels.ContactSummary) []models.ContactSummary {
+	result := make([]models.ContactSummary, len(summaries))
+	for i, summary := range summaries {
+		result[i] = models.ContactSummary{
+			Key:           summary.Key,
+			Names:         summary.Names,
+			Leads:         summary.Leads,
+			Inquiries:     summary.Inquiries,
+			DealerClients: summary.DealerClients,
+		}
+	}
+	return result
+}
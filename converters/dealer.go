@@ -17,6 +17,8 @@ func ToMongoDealer(dealer models.Dealer) (mongoModels.Dealer, error) {
 		ShopName:      dealer.ShopName,
 		Location:      dealer.Location,
 		SubLocation:   dealer.SubLocation,
+		Watermark:     ToMongoWatermark(dealer.Watermark),
+		DeactivatedAt: dealer.DeactivatedAt,
 		CreatedAt:     dealer.CreatedAt,
 		UpdatedAt:     dealer.UpdatedAt,
 	}
@@ -29,11 +31,19 @@ func ToMongoDealer(dealer models.Dealer) (mongoModels.Dealer, error) {
 		mongoDealer.ID = objectID
 	}
 
+	if dealer.SuccessorDealerID != "" {
+		successorID, err := primitive.ObjectIDFromHex(dealer.SuccessorDealerID)
+		if err != nil {
+			return mongoModels.Dealer{}, err
+		}
+		mongoDealer.SuccessorDealerID = &successorID
+	}
+
 	return mongoDealer, nil
 }
 
 func ToDomainDealer(mongoDealer mongoModels.Dealer) models.Dealer {
-	return models.Dealer{
+	dealer := models.Dealer{
 		ID:            mongoDealer.ID.Hex(),
 		Name:          mongoDealer.Name,
 		Phone:         mongoDealer.Phone,
@@ -43,9 +53,15 @@ func ToDomainDealer(mongoDealer mongoModels.Dealer) models.Dealer {
 		ShopName:      mongoDealer.ShopName,
 		Location:      mongoDealer.Location,
 		SubLocation:   mongoDealer.SubLocation,
+		Watermark:     toDomainWatermark(mongoDealer.Watermark),
+		DeactivatedAt: mongoDealer.DeactivatedAt,
 		CreatedAt:     mongoDealer.CreatedAt,
 		UpdatedAt:     mongoDealer.UpdatedAt,
 	}
+	if mongoDealer.SuccessorDealerID != nil {
+		dealer.SuccessorDealerID = mongoDealer.SuccessorDealerID.Hex()
+	}
+	return dealer
 }
 
 
@@ -56,4 +72,30 @@ func ToDomainDealerSlice(mongoDealers []mongoModels.Dealer) []models.Dealer {
 		dealers[i] = ToDomainDealer(mongoDealer)
 	}
 	return dealers
-}
\ No newline at end of file
+}
+
+func ToMongoWatermark(watermark *models.WatermarkSettings) *mongoModels.WatermarkSettings {
+	if watermark == nil {
+		return nil
+	}
+	return &mongoModels.WatermarkSettings{
+		Enabled:  watermark.Enabled,
+		Type:     watermark.Type,
+		LogoKey:  watermark.LogoKey,
+		Position: watermark.Position,
+		Opacity:  watermark.Opacity,
+	}
+}
+
+func toDomainWatermark(watermark *mongoModels.WatermarkSettings) *models.WatermarkSettings {
+	if watermark == nil {
+		return nil
+	}
+	return &models.WatermarkSettings{
+		Enabled:  watermark.Enabled,
+		Type:     watermark.Type,
+		LogoKey:  watermark.LogoKey,
+		Position: watermark.Position,
+		Opacity:  watermark.Opacity,
+	}
+}
@@ -8,22 +8,29 @@ import (
 )
 
 func ToDomainDealerClient(mongoDealerClient mongoModels.DealerClient) models.DealerClient {
-	return models.DealerClient{
+	dealerClient := models.DealerClient{
 		ID:                mongoDealerClient.ID.Hex(),
 		DealerID:          mongoDealerClient.DealerID.Hex(),
 		Name:              mongoDealerClient.Name,
 		Phone:             mongoDealerClient.Phone,
+		ContactKey:        mongoDealerClient.ContactKey,
 		Note:              mongoDealerClient.Note,
+		Criteria:          ToDomainRequirementCriteria(mongoDealerClient.Criteria),
 		Docs:              ToDomainDealerClientDocs(mongoDealerClient.Docs),
 		PropertyInterests: ToDomainDealerClientPropertyInterestSlice(mongoDealerClient.PropertyInterests),
 		CreatedAt:         mongoDealerClient.CreatedAt,
 		UpdatedAt:         mongoDealerClient.UpdatedAt,
 	}
+	if mongoDealerClient.SourceInquiryID != nil {
+		dealerClient.SourceInquiryID = mongoDealerClient.SourceInquiryID.Hex()
+	}
+	return dealerClient
 }
 func ToDomainDealerClientDocs(mongoDocs []mongoModels.Document) []models.Document {
 	docs := make([]models.Document, len(mongoDocs))
 	for i, doc := range mongoDocs {
 		docs[i] = models.Document{
+			Key:  doc.Key,
 			URL:  doc.URL,
 			Type: doc.Type,
 			Size: doc.Size,
@@ -84,10 +91,11 @@ func ToMongoDealerClientPropertyInterestSlice(dealerClientPropertyInterests []mo
 
 func ToMongoDealerClientUpdate(update models.DealerClientUpdate) mongoModels.DealerClientUpdate {
 	return mongoModels.DealerClientUpdate{
-		Name:  update.Name,
-		Phone: update.Phone,
-		Note:  update.Note,
-		Docs:  convertDomainDocsToMongoDocs(update.Docs),
+		Name:     update.Name,
+		Phone:    update.Phone,
+		Note:     update.Note,
+		Criteria: ToMongoRequirementCriteria(update.Criteria),
+		Docs:     convertDomainDocsToMongoDocs(update.Docs),
 	}
 }
 func convertDomainDocsToMongoDocs(domainDocs *[]models.Document) *[]mongoModels.DocumentUpdate {
@@ -97,6 +105,7 @@ func convertDomainDocsToMongoDocs(domainDocs *[]models.Document) *[]mongoModels.
 	mongoDocs := make([]mongoModels.DocumentUpdate, len(*domainDocs))
 	for i, doc := range *domainDocs {
 		mongoDocs[i] = mongoModels.DocumentUpdate{
+			Key:  &doc.Key,
 			URL:  &doc.URL,
 			Type: &doc.Type,
 			Size: &doc.Size,
@@ -109,6 +118,7 @@ func ToMongoDealerClientDocs(domainDocs []models.Document) []mongoModels.Documen
 	mongoDocs := make([]mongoModels.Document, len(domainDocs))
 	for i, doc := range domainDocs {
 		mongoDocs[i] = mongoModels.Document{
+			Key:  doc.Key,
 			URL:  doc.URL,
 			Type: doc.Type,
 			Size: doc.Size,
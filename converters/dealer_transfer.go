@@ -0,0 +1,66 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoDealerTransfer(transfer models.DealerTransfer) (mongoModels.DealerTransfer, error) {
+	fromDealerID, err := primitive.ObjectIDFromHex(transfer.FromDealerID)
+	if err != nil {
+		return mongoModels.DealerTransfer{}, err
+	}
+	toDealerID, err := primitive.ObjectIDFromHex(transfer.ToDealerID)
+	if err != nil {
+		return mongoModels.DealerTransfer{}, err
+	}
+
+	return mongoModels.DealerTransfer{
+		FromDealerID:      fromDealerID,
+		ToDealerID:        toDealerID,
+		Reason:            transfer.Reason,
+		PerformedBy:       transfer.PerformedBy,
+		Properties:        transfer.Properties,
+		DealerClients:     transfer.DealerClients,
+		Inquiries:         transfer.Inquiries,
+		SavedSearches:     transfer.SavedSearches,
+		Leads:             transfer.Leads,
+		AssignedLeads:     transfer.AssignedLeads,
+		Tasks:             transfer.Tasks,
+		Visits:            transfer.Visits,
+		CoBrokeAgreements: transfer.CoBrokeAgreements,
+		RevokedShareLinks: transfer.RevokedShareLinks,
+		CreatedAt:         transfer.CreatedAt,
+	}, nil
+}
+
+func ToDomainDealerTransfer(transfer mongoModels.DealerTransfer) models.DealerTransfer {
+	return models.DealerTransfer{
+		ID:                transfer.ID.Hex(),
+		FromDealerID:      transfer.FromDealerID.Hex(),
+		ToDealerID:        transfer.ToDealerID.Hex(),
+		Reason:            transfer.Reason,
+		PerformedBy:       transfer.PerformedBy,
+		Properties:        transfer.Properties,
+		DealerClients:     transfer.DealerClients,
+		Inquiries:         transfer.Inquiries,
+		SavedSearches:     transfer.SavedSearches,
+		Leads:             transfer.Leads,
+		AssignedLeads:     transfer.AssignedLeads,
+		Tasks:             transfer.Tasks,
+		Visits:            transfer.Visits,
+		CoBrokeAgreements: transfer.CoBrokeAgreements,
+		RevokedShareLinks: transfer.RevokedShareLinks,
+		CreatedAt:         transfer.CreatedAt,
+	}
+}
+
+func ToDomainDealerTransferSlice(transfers []mongoModels.DealerTransfer) []models.DealerTransfer {
+	result := make([]models.DealerTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		result = append(result, ToDomainDealerTransfer(transfer))
+	}
+	return result
+}
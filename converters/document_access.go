@@ -0,0 +1,48 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoDocumentAccessLog(entry models.DocumentAccessLog) (mongoModels.DocumentAccessLog, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(entry.DealerID)
+	if err != nil {
+		return mongoModels.DocumentAccessLog{}, err
+	}
+	clientObjectID, err := primitive.ObjectIDFromHex(entry.ClientID)
+	if err != nil {
+		return mongoModels.DocumentAccessLog{}, err
+	}
+
+	return mongoModels.DocumentAccessLog{
+		DealerID:   dealerObjectID,
+		ClientID:   clientObjectID,
+		Key:        entry.Key,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		AccessedAt: entry.AccessedAt,
+	}, nil
+}
+
+func ToDomainDocumentAccessLog(entry mongoModels.DocumentAccessLog) models.DocumentAccessLog {
+	return models.DocumentAccessLog{
+		ID:         entry.ID.Hex(),
+		DealerID:   entry.DealerID.Hex(),
+		ClientID:   entry.ClientID.Hex(),
+		Key:        entry.Key,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		AccessedAt: entry.AccessedAt,
+	}
+}
+
+func ToDomainDocumentAccessLogSlice(entries []mongoModels.DocumentAccessLog) []models.DocumentAccessLog {
+	logs := make([]models.DocumentAccessLog, len(entries))
+	for i, entry := range entries {
+		logs[i] = ToDomainDocumentAccessLog(entry)
+	}
+	return logs
+}
@@ -3,6 +3,7 @@ package converters
 import (
 	"myapp/models"
 	mongoModels "myapp/mongo_models"
+	"myapp/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,7 +14,13 @@ func ToDomainInquiry(mongoInquiry mongoModels.Inquiry) models.Inquiry {
 		Source:      mongoInquiry.Source,
 		Name:        mongoInquiry.Name,
 		Phone:       mongoInquiry.Phone,
+		ContactKey:  mongoInquiry.ContactKey,
 		Requirement: mongoInquiry.Requirement,
+		Criteria:    ToDomainRequirementCriteria(mongoInquiry.Criteria),
+		Converted:   mongoInquiry.Converted,
+		ConvertedTo: mongoInquiry.ConvertedTo,
+		ConvertedBy: mongoInquiry.ConvertedBy,
+		ConvertedAt: mongoInquiry.ConvertedAt,
 		CreatedAt:   mongoInquiry.CreatedAt,
 		UpdatedAt:   mongoInquiry.UpdatedAt,
 	}
@@ -22,6 +29,9 @@ func ToDomainInquiry(mongoInquiry mongoModels.Inquiry) models.Inquiry {
 		dealerID := mongoInquiry.DealerID.Hex()
 		inquiry.DealerID = &dealerID
 	}
+	if mongoInquiry.ConvertedID != nil {
+		inquiry.ConvertedID = mongoInquiry.ConvertedID.Hex()
+	}
 
 	return inquiry
 }
@@ -53,7 +63,9 @@ func ToMongoInquiry(inquiry models.Inquiry) mongoModels.Inquiry {
 		Source:      inquiry.Source,
 		Name:        inquiry.Name,
 		Phone:       inquiry.Phone,
+		ContactKey:  utils.NormalizePhone(inquiry.Phone),
 		Requirement: inquiry.Requirement,
+		Criteria:    ToMongoRequirementCriteria(inquiry.Criteria),
 		CreatedAt:   inquiry.CreatedAt,
 		UpdatedAt:   inquiry.UpdatedAt,
 	}
@@ -73,6 +85,7 @@ func ToMongoInquiryUpdate(update models.InquiryUpdate) mongoModels.InquiryUpdate
 		Name:        update.Name,
 		Phone:       update.Phone,
 		Requirement: update.Requirement,
+		Criteria:    ToMongoRequirementCriteria(update.Criteria),
 		UpdatedAt:   update.UpdatedAt,
 	}
 }
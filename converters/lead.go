@@ -4,6 +4,8 @@ import "myapp/models"
 
 import mongoModels "myapp/mongo_models"
 
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
 
 
 func ToDomainLeadSlice(mongoLeads []mongoModels.Lead) []models.Lead {
@@ -18,28 +20,108 @@ func ToDomainLead(mongoLead mongoModels.Lead) models.Lead {
 	
 	properties := make([]models.PropertyInterest, len(mongoLead.Properties))
 	for i, prop := range mongoLead.Properties {
-		properties[i] = models.PropertyInterest{
-			ID:         prop.PropertyID.Hex(),
-			LeadID:     mongoLead.ID.Hex(),
-			PropertyID: prop.PropertyID.Hex(),
-			PropertyNumber: prop.PropertyNumber,
-			DealerID:   prop.DealerID.Hex(),
-			Status:     prop.Status,
-			Note:       prop.Note,
-			CreatedAt:  prop.CreatedAt,
-			UpdatedAt:  prop.CreatedAt, 
-		}
+		properties[i] = ToDomainPropertyInterest(mongoLead.ID, prop)
 	}
 
-	return models.Lead{
+	lead := models.Lead{
 		ID:           mongoLead.ID.Hex(),
 		Name:         mongoLead.Name,
 		Phone:        mongoLead.Phone,
+		ContactKey:   mongoLead.ContactKey,
 		Requirement:  mongoLead.Requirement,
-		AadharNumber: mongoLead.AadharNumber,
-		AadharPhoto:  mongoLead.AadharPhoto,
+		Criteria:     ToDomainRequirementCriteria(mongoLead.Criteria),
 		Properties:   properties,       
-		     
+		AssignedAt:   mongoLead.AssignedAt,
+	}
+	SetLeadAadhar(&lead, mongoLead)
+	if mongoLead.AssignedDealerID != nil {
+		lead.AssignedDealerID = mongoLead.AssignedDealerID.Hex()
+	}
+	if mongoLead.SourceInquiryID != nil {
+		lead.SourceInquiryID = mongoLead.SourceInquiryID.Hex()
+	}
+	if mongoLead.UpdatedAt != nil {
+		lead.UpdatedAt = *mongoLead.UpdatedAt
+	}
+	return lead
+}
+
+// ToDomainPropertyInterest converts one of the lead's property interests. Interests added before
+// updates were timestamped report their creation time as the last update.
+func ToDomainPropertyInterest(leadID primitive.ObjectID, interest mongoModels.PropertyInterest) models.PropertyInterest {
+	history := make([]models.PropertyInterestStatusChange, len(interest.History))
+	for i, change := range interest.History {
+		history[i] = models.PropertyInterestStatusChange{
+			From:      change.From,
+			To:        change.To,
+			Note:      change.Note,
+			Reason:    change.Reason,
+			SoldPrice: change.SoldPrice,
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		}
+	}
+
+	updatedAt := interest.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = interest.CreatedAt
+	}
+
+	return models.PropertyInterest{
+		ID:              interest.PropertyID.Hex(),
+		LeadID:          leadID.Hex(),
+		PropertyID:      interest.PropertyID.Hex(),
+		PropertyNumber:  interest.PropertyNumber,
+		DealerID:        interest.DealerID.Hex(),
+		Status:          interest.Status,
+		Note:            interest.Note,
+		SoldPrice:       interest.SoldPrice,
+		LostReason:      interest.LostReason,
+		StatusChangedAt: interest.StatusChangedAt,
+		History:         history,
+		CreatedAt:       interest.CreatedAt,
+		UpdatedAt:       updatedAt,
+	}
+}
+
+func ToMongoPropertyInterestStatusChange(change models.PropertyInterestStatusChange) mongoModels.PropertyInterestStatusChange {
+	return mongoModels.PropertyInterestStatusChange{
+		From:      change.From,
+		To:        change.To,
+		Note:      change.Note,
+		Reason:    change.Reason,
+		SoldPrice: change.SoldPrice,
+		ChangedBy: change.ChangedBy,
+		ChangedAt: change.ChangedAt,
 	}
 }
 
+func ToMongoPropertyInterest(interest models.PropertyInterest) (mongoModels.PropertyInterest, error) {
+	propertyID, err := primitive.ObjectIDFromHex(interest.PropertyID)
+	if err != nil {
+		return mongoModels.PropertyInterest{}, err
+	}
+	dealerID, err := primitive.ObjectIDFromHex(interest.DealerID)
+	if err != nil {
+		return mongoModels.PropertyInterest{}, err
+	}
+
+	history := make([]mongoModels.PropertyInterestStatusChange, len(interest.History))
+	for i, change := range interest.History {
+		history[i] = ToMongoPropertyInterestStatusChange(change)
+	}
+
+	return mongoModels.PropertyInterest{
+		PropertyID:      propertyID,
+		PropertyNumber:  interest.PropertyNumber,
+		DealerID:        dealerID,
+		Status:          interest.Status,
+		Note:            interest.Note,
+		SoldPrice:       interest.SoldPrice,
+		LostReason:      interest.LostReason,
+		StatusChangedAt: interest.StatusChangedAt,
+		History:         history,
+		CreatedAt:       interest.CreatedAt,
+		UpdatedAt:       interest.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoLeadRoutingRule(rule models.LeadRoutingRule) (mongoModels.LeadRoutingRule, error) {
+	dealerIDs := make([]primitive.ObjectID, 0, len(rule.DealerIDs))
+	for _, id := range rule.DealerIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return mongoModels.LeadRoutingRule{}, err
+		}
+		dealerIDs = append(dealerIDs, objectID)
+	}
+
+	return mongoModels.LeadRoutingRule{
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		Active:         rule.Active,
+		Locations:      rule.Locations,
+		SubLocations:   rule.SubLocations,
+		MinBudget:      rule.MinBudget,
+		MaxBudget:      rule.MaxBudget,
+		DealerIDs:      dealerIDs,
+		Strategy:       rule.Strategy,
+		DealerCapacity: rule.DealerCapacity,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}, nil
+}
+
+func ToDomainLeadRoutingRule(rule mongoModels.LeadRoutingRule) models.LeadRoutingRule {
+	dealerIDs := make([]string, len(rule.DealerIDs))
+	for i, id := range rule.DealerIDs {
+		dealerIDs[i] = id.Hex()
+	}
+
+	return models.LeadRoutingRule{
+		ID:             rule.ID.Hex(),
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		Active:         rule.Active,
+		Locations:      rule.Locations,
+		SubLocations:   rule.SubLocations,
+		MinBudget:      rule.MinBudget,
+		MaxBudget:      rule.MaxBudget,
+		DealerIDs:      dealerIDs,
+		Strategy:       rule.Strategy,
+		DealerCapacity: rule.DealerCapacity,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+}
+
+func ToDomainLeadRoutingRuleSlice(rules []mongoModels.LeadRoutingRule) []models.LeadRoutingRule {
+	result := make([]models.LeadRoutingRule, len(rules))
+	for i, rule := range rules {
+		result[i] = ToDomainLeadRoutingRule(rule)
+	}
+	return result
+}
+
+func ToMongoLeadAssignment(assignment models.LeadAssignment) (mongoModels.LeadAssignment, error) {
+	leadID, err := primitive.ObjectIDFromHex(assignment.LeadID)
+	if err != nil {
+		return mongoModels.LeadAssignment{}, err
+	}
+	fromDealerID, err := optionalObjectID(assignment.FromDealerID)
+	if err != nil {
+		return mongoModels.LeadAssignment{}, err
+	}
+	toDealerID, err := optionalObjectID(assignment.ToDealerID)
+	if err != nil {
+		return mongoModels.LeadAssignment{}, err
+	}
+	ruleID, err := optionalObjectID(assignment.RuleID)
+	if err != nil {
+		return mongoModels.LeadAssignment{}, err
+	}
+
+	return mongoModels.LeadAssignment{
+		LeadID:       leadID,
+		FromDealerID: fromDealerID,
+		ToDealerID:   toDealerID,
+		Method:       assignment.Method,
+		RuleID:       ruleID,
+		AssignedBy:   assignment.AssignedBy,
+		Note:         assignment.Note,
+		CreatedAt:    assignment.CreatedAt,
+	}, nil
+}
+
+func ToDomainLeadAssignment(assignment mongoModels.LeadAssignment) models.LeadAssignment {
+	result := models.LeadAssignment{
+		ID:         assignment.ID.Hex(),
+		LeadID:     assignment.LeadID.Hex(),
+		Method:     assignment.Method,
+		AssignedBy: assignment.AssignedBy,
+		Note:       assignment.Note,
+		CreatedAt:  assignment.CreatedAt,
+	}
+	if assignment.FromDealerID != nil {
+		result.FromDealerID = assignment.FromDealerID.Hex()
+	}
+	if assignment.ToDealerID != nil {
+		result.ToDealerID = assignment.ToDealerID.Hex()
+	}
+	if assignment.RuleID != nil {
+		result.RuleID = assignment.RuleID.Hex()
+	}
+	return result
+}
+
+func ToDomainLeadAssignmentSlice(assignments []mongoModels.LeadAssignment) []models.LeadAssignment {
+	result := make([]models.LeadAssignment, len(assignments))
+	for i, assignment := range assignments {
+		result[i] = ToDomainLeadAssignment(assignment)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoMedia(media models.Media) (mongoModels.Media, error) {
+	mongoMedia := mongoModels.Media{
+		Key:         media.Key,
+		OwnerID:     media.OwnerID,
+		Purpose:     media.Purpose,
+		ContentType: media.ContentType,
+		Size:        media.Size,
+		Status:      media.Status,
+		UploadID:    media.UploadID,
+		CreatedAt:   media.CreatedAt,
+		UpdatedAt:   media.UpdatedAt,
+	}
+
+	if media.ID != "" {
+		objectID, err := primitive.ObjectIDFromHex(media.ID)
+		if err != nil {
+			return mongoModels.Media{}, err
+		}
+		mongoMedia.ID = objectID
+	}
+
+	return mongoMedia, nil
+}
+
+func ToDomainMedia(mongoMedia mongoModels.Media) models.Media {
+	return models.Media{
+		ID:          mongoMedia.ID.Hex(),
+		Key:         mongoMedia.Key,
+		OwnerID:     mongoMedia.OwnerID,
+		Purpose:     mongoMedia.Purpose,
+		ContentType: mongoMedia.ContentType,
+		Size:        mongoMedia.Size,
+		Status:      mongoMedia.Status,
+		UploadID:    mongoMedia.UploadID,
+		CreatedAt:   mongoMedia.CreatedAt,
+		UpdatedAt:   mongoMedia.UpdatedAt,
+	}
+}
+
+func ToDomainMediaSlice(mongoMedia []mongoModels.Media) []models.Media {
+	media := make([]models.Media, len(mongoMedia))
+	for i, item := range mongoMedia {
+		media[i] = ToDomainMedia(item)
+	}
+	return media
+}
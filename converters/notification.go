@@ -0,0 +1,39 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+)
+
+func ToMongoNotification(notification models.Notification) mongoModels.Notification {
+	return mongoModels.Notification{
+		RecipientID: notification.RecipientID,
+		Type:        notification.Type,
+		Title:       notification.Title,
+		Body:        notification.Body,
+		Data:        notification.Data,
+		ReadAt:      notification.ReadAt,
+		CreatedAt:   notification.CreatedAt,
+	}
+}
+
+func ToDomainNotification(notification mongoModels.Notification) models.Notification {
+	return models.Notification{
+		ID:          notification.ID.Hex(),
+		RecipientID: notification.RecipientID,
+		Type:        notification.Type,
+		Title:       notification.Title,
+		Body:        notification.Body,
+		Data:        notification.Data,
+		ReadAt:      notification.ReadAt,
+		CreatedAt:   notification.CreatedAt,
+	}
+}
+
+func ToDomainNotificationSlice(notifications []mongoModels.Notification) []models.Notification {
+	result := make([]models.Notification, len(notifications))
+	for i, notification := range notifications {
+		result[i] = ToDomainNotification(notification)
+	}
+	return result
+}
@@ -17,7 +17,7 @@ func ToMongoProperty(property models.Property) (mongoModels.Property, error) {
 		Address:         property.Address,
 		MinPrice:        property.MinPrice,
 		MaxPrice:        property.MaxPrice,
-		Photos:          property.Photos,
+		Photos:          ToMongoPhotos(property.Photos),
 		Videos:          property.Videos,
 		OwnerName:       property.OwnerName,
 		OwnerPhone:      property.OwnerPhone,
@@ -30,6 +30,15 @@ func ToMongoProperty(property models.Property) (mongoModels.Property, error) {
 		Bedrooms:        property.Bedrooms,
 		Bathrooms:       property.Bathrooms,
 		PropertyType:    property.PropertyType,
+		Furnishing:      property.Furnishing,
+		ListingType:     property.ListingType,
+		Floor:           property.Floor,
+		TotalFloors:     property.TotalFloors,
+		Facing:          property.Facing,
+		Parking:         property.Parking,
+		PropertyAge:     property.PropertyAge,
+		Amenities:       property.Amenities,
+		CoBroke:         ToMongoCoBrokeTerms(property.CoBroke),
 		CreatedAt:       property.CreatedAt,
 		UpdatedAt:       property.UpdatedAt,
 	}
@@ -65,7 +74,7 @@ func ToDomainProperty(mongoProperty mongoModels.Property) models.Property {
 		Address:         mongoProperty.Address,
 		MinPrice:        mongoProperty.MinPrice,
 		MaxPrice:        mongoProperty.MaxPrice,
-		Photos:          mongoProperty.Photos,
+		Photos:          ToDomainPhotos(mongoProperty.Photos),
 		Videos:          mongoProperty.Videos,
 		OwnerName:       mongoProperty.OwnerName,
 		OwnerPhone:      mongoProperty.OwnerPhone,
@@ -78,6 +87,15 @@ func ToDomainProperty(mongoProperty mongoModels.Property) models.Property {
 		Bedrooms:        mongoProperty.Bedrooms,
 		Bathrooms:       mongoProperty.Bathrooms,
 		PropertyType:    mongoProperty.PropertyType,
+		Furnishing:      mongoProperty.Furnishing,
+		ListingType:     mongoProperty.ListingType,
+		Floor:           mongoProperty.Floor,
+		TotalFloors:     mongoProperty.TotalFloors,
+		Facing:          mongoProperty.Facing,
+		Parking:         mongoProperty.Parking,
+		PropertyAge:     mongoProperty.PropertyAge,
+		Amenities:       mongoProperty.Amenities,
+		CoBroke:         ToDomainCoBrokeTerms(mongoProperty.CoBroke),
 		CreatedAt:       mongoProperty.CreatedAt,
 		UpdatedAt:       mongoProperty.UpdatedAt,
 	}
@@ -102,7 +120,7 @@ func ToMongoPropertyUpdate(update models.PropertyUpdate) mongoModels.PropertyUpd
         MinPrice:        update.MinPrice,
         MaxPrice:        update.MaxPrice,
         Description:     update.Description,
-        Photos:          update.Photos,
+        Photos:          toMongoPhotosUpdate(update.Photos),
         Videos:          update.Videos,
         OwnerName:       update.OwnerName,
         OwnerPhone:      update.OwnerPhone,
@@ -114,6 +132,50 @@ func ToMongoPropertyUpdate(update models.PropertyUpdate) mongoModels.PropertyUpd
         Bedrooms:        update.Bedrooms,
         Bathrooms:       update.Bathrooms,
         PropertyType:    update.PropertyType,
+        Furnishing:      update.Furnishing,
+        ListingType:     update.ListingType,
+        Floor:           update.Floor,
+        TotalFloors:     update.TotalFloors,
+        Facing:          update.Facing,
+        Parking:         update.Parking,
+        PropertyAge:     update.PropertyAge,
+        Amenities:       update.Amenities,
         UpdatedAt:       update.UpdatedAt,
     }
 }
+
+
+func ToMongoPhotos(photos []models.Photo) []mongoModels.Photo {
+	if photos == nil {
+		return nil
+	}
+	mongoPhotos := make([]mongoModels.Photo, len(photos))
+	for i, photo := range photos {
+		mongoPhotos[i] = mongoModels.Photo{
+			Key:      photo.Key,
+			URL:      photo.URL,
+			Variants: photo.Variants,
+		}
+	}
+	return mongoPhotos
+}
+
+func ToDomainPhotos(mongoPhotos []mongoModels.Photo) []models.Photo {
+	photos := make([]models.Photo, len(mongoPhotos))
+	for i, photo := range mongoPhotos {
+		photos[i] = models.Photo{
+			Key:      photo.Key,
+			URL:      photo.URL,
+			Variants: photo.Variants,
+		}
+	}
+	return photos
+}
+
+func toMongoPhotosUpdate(photos *[]models.Photo) *[]mongoModels.Photo {
+	if photos == nil {
+		return nil
+	}
+	mongoPhotos := ToMongoPhotos(*photos)
+	return &mongoPhotos
+}
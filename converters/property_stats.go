@@ -0,0 +1,27 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+)
+
+func ToDomainPropertyDayStats(stats mongoModels.PropertyDayStats) models.PropertyDayStats {
+	return models.PropertyDayStats{
+		PropertyID: stats.PropertyID.Hex(),
+		Date:       stats.Date,
+		EngagementCounts: models.EngagementCounts{
+			PublicViews:       stats.PublicViews,
+			DealerViews:       stats.DealerViews,
+			BrochureDownloads: stats.BrochureDownloads,
+			Interests:         stats.Interests,
+		},
+	}
+}
+
+func ToDomainPropertyDayStatsSlice(stats []mongoModels.PropertyDayStats) []models.PropertyDayStats {
+	result := make([]models.PropertyDayStats, 0, len(stats))
+	for _, day := range stats {
+		result = append(result, ToDomainPropertyDayStats(day))
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+)
+
+func ToMongoRequirementCriteria(criteria *models.RequirementCriteria) *mongoModels.RequirementCriteria {
+	if criteria == nil {
+		return nil
+	}
+	return &mongoModels.RequirementCriteria{
+		MinBudget:     criteria.MinBudget,
+		MaxBudget:     criteria.MaxBudget,
+		MinBedrooms:   criteria.MinBedrooms,
+		MaxBedrooms:   criteria.MaxBedrooms,
+		PropertyTypes: criteria.PropertyTypes,
+		Locations:     criteria.Locations,
+		SubLocations:  criteria.SubLocations,
+		ListingType:   criteria.ListingType,
+		Furnishing:    criteria.Furnishing,
+	}
+}
+
+func ToDomainRequirementCriteria(criteria *mongoModels.RequirementCriteria) *models.RequirementCriteria {
+	if criteria == nil {
+		return nil
+	}
+	return &models.RequirementCriteria{
+		MinBudget:     criteria.MinBudget,
+		MaxBudget:     criteria.MaxBudget,
+		MinBedrooms:   criteria.MinBedrooms,
+		MaxBedrooms:   criteria.MaxBedrooms,
+		PropertyTypes: criteria.PropertyTypes,
+		Locations:     criteria.Locations,
+		SubLocations:  criteria.SubLocations,
+		ListingType:   criteria.ListingType,
+		Furnishing:    criteria.Furnishing,
+	}
+}
@@ -0,0 +1,121 @@
+package converters
+
+import (
+	"net/url"
+
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoSavedSearch(search models.SavedSearch) (mongoModels.SavedSearch, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(search.DealerID)
+	if err != nil {
+		return mongoModels.SavedSearch{}, err
+	}
+	dealerClientObjectID, err := optionalObjectID(search.DealerClientID)
+	if err != nil {
+		return mongoModels.SavedSearch{}, err
+	}
+
+	query := url.Values{}
+	for key, value := range search.Query {
+		query.Set(key, value)
+	}
+
+	return mongoModels.SavedSearch{
+		DealerID:       dealerObjectID,
+		DealerClientID: dealerClientObjectID,
+		Name:           search.Name,
+		Query:          query.Encode(),
+		Active:         search.Active,
+		MatchCount:     search.MatchCount,
+		LastMatchedAt:  search.LastMatchedAt,
+		CreatedAt:      search.CreatedAt,
+		UpdatedAt:      search.UpdatedAt,
+	}, nil
+}
+
+func ToDomainSavedSearch(search mongoModels.SavedSearch) models.SavedSearch {
+	query := map[string]string{}
+	if values, err := url.ParseQuery(search.Query); err == nil {
+		for key := range values {
+			query[key] = values.Get(key)
+		}
+	}
+
+	domain := models.SavedSearch{
+		ID:            search.ID.Hex(),
+		DealerID:      search.DealerID.Hex(),
+		Name:          search.Name,
+		Query:         query,
+		Active:        search.Active,
+		MatchCount:    search.MatchCount,
+		LastMatchedAt: search.LastMatchedAt,
+		CreatedAt:     search.CreatedAt,
+		UpdatedAt:     search.UpdatedAt,
+	}
+	if search.DealerClientID != nil {
+		domain.DealerClientID = search.DealerClientID.Hex()
+	}
+	return domain
+}
+
+func ToDomainSavedSearchSlice(searches []mongoModels.SavedSearch) []models.SavedSearch {
+	result := make([]models.SavedSearch, len(searches))
+	for i, search := range searches {
+		result[i] = ToDomainSavedSearch(search)
+	}
+	return result
+}
+
+func ToMongoSavedSearchMatch(match models.SavedSearchMatch) (mongoModels.SavedSearchMatch, error) {
+	savedSearchObjectID, err := primitive.ObjectIDFromHex(match.SavedSearchID)
+	if err != nil {
+		return mongoModels.SavedSearchMatch{}, err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(match.DealerID)
+	if err != nil {
+		return mongoModels.SavedSearchMatch{}, err
+	}
+	propertyObjectID, err := primitive.ObjectIDFromHex(match.PropertyID)
+	if err != nil {
+		return mongoModels.SavedSearchMatch{}, err
+	}
+
+	return mongoModels.SavedSearchMatch{
+		SavedSearchID:  savedSearchObjectID,
+		DealerID:       dealerObjectID,
+		PropertyID:     propertyObjectID,
+		PropertyNumber: match.PropertyNumber,
+		PropertyTitle:  match.PropertyTitle,
+		MinPrice:       match.MinPrice,
+		MaxPrice:       match.MaxPrice,
+		Trigger:        match.Trigger,
+		MatchedAt:      match.MatchedAt,
+	}, nil
+}
+
+func ToDomainSavedSearchMatch(match mongoModels.SavedSearchMatch) models.SavedSearchMatch {
+	return models.SavedSearchMatch{
+		ID:             match.ID.Hex(),
+		SavedSearchID:  match.SavedSearchID.Hex(),
+		DealerID:       match.DealerID.Hex(),
+		PropertyID:     match.PropertyID.Hex(),
+		PropertyNumber: match.PropertyNumber,
+		PropertyTitle:  match.PropertyTitle,
+		MinPrice:       match.MinPrice,
+		MaxPrice:       match.MaxPrice,
+		Trigger:        match.Trigger,
+		MatchedAt:      match.MatchedAt,
+	}
+}
+
+func ToDomainSavedSearchMatchSlice(matches []mongoModels.SavedSearchMatch) []models.SavedSearchMatch {
+	result := make([]models.SavedSearchMatch, len(matches))
+	for i, match := range matches {
+		result[i] = ToDomainSavedSearchMatch(match)
+	}
+	return result
+}
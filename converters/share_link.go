@@ -0,0 +1,91 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoShareLink(link models.ShareLink) (mongoModels.ShareLink, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(link.DealerID)
+	if err != nil {
+		return mongoModels.ShareLink{}, err
+	}
+
+	propertyObjectIDs := make([]primitive.ObjectID, len(link.PropertyIDs))
+	for i, id := range link.PropertyIDs {
+		propertyObjectIDs[i], err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return mongoModels.ShareLink{}, err
+		}
+	}
+
+	return mongoModels.ShareLink{
+		DealerID:     dealerObjectID,
+		PropertyIDs:  propertyObjectIDs,
+		Title:        link.Title,
+		ExpiresAt:    link.ExpiresAt,
+		Revoked:      link.Revoked,
+		RevokedAt:    link.RevokedAt,
+		Opens:        link.Opens,
+		LastOpenedAt: link.LastOpenedAt,
+		CreatedAt:    link.CreatedAt,
+		UpdatedAt:    link.UpdatedAt,
+	}, nil
+}
+
+func ToDomainShareLink(link mongoModels.ShareLink) models.ShareLink {
+	propertyIDs := make([]string, len(link.PropertyIDs))
+	for i, id := range link.PropertyIDs {
+		propertyIDs[i] = id.Hex()
+	}
+
+	return models.ShareLink{
+		ID:           link.ID.Hex(),
+		DealerID:     link.DealerID.Hex(),
+		PropertyIDs:  propertyIDs,
+		Title:        link.Title,
+		ExpiresAt:    link.ExpiresAt,
+		Revoked:      link.Revoked,
+		RevokedAt:    link.RevokedAt,
+		Opens:        link.Opens,
+		LastOpenedAt: link.LastOpenedAt,
+		CreatedAt:    link.CreatedAt,
+		UpdatedAt:    link.UpdatedAt,
+	}
+}
+
+func ToDomainShareLinkSlice(links []mongoModels.ShareLink) []models.ShareLink {
+	result := make([]models.ShareLink, len(links))
+	for i, link := range links {
+		result[i] = ToDomainShareLink(link)
+	}
+	return result
+}
+
+// ToSharedProperty strips a listing down to what a share link recipient may see
+func ToSharedProperty(property models.Property) models.SharedProperty {
+	return models.SharedProperty{
+		PropertyNumber:  property.PropertyNumber,
+		Title:           property.Title,
+		Description:     property.Description,
+		NearestLandmark: property.NearestLandmark,
+		MinPrice:        property.MinPrice,
+		MaxPrice:        property.MaxPrice,
+		Photos:          property.Photos,
+		Videos:          property.Videos,
+		Area:            property.Area,
+		Bedrooms:        property.Bedrooms,
+		Bathrooms:       property.Bathrooms,
+		PropertyType:    property.PropertyType,
+		Furnishing:      property.Furnishing,
+		Floor:           property.Floor,
+		TotalFloors:     property.TotalFloors,
+		Facing:          property.Facing,
+		Parking:         property.Parking,
+		PropertyAge:     property.PropertyAge,
+		Amenities:       property.Amenities,
+		Sold:            property.Sold,
+	}
+}
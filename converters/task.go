@@ -0,0 +1,66 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoTask(task models.Task) (mongoModels.Task, error) {
+	subjectID, err := primitive.ObjectIDFromHex(task.SubjectID)
+	if err != nil {
+		return mongoModels.Task{}, err
+	}
+
+	return mongoModels.Task{
+		Title:       task.Title,
+		Notes:       task.Notes,
+		SubjectType: task.SubjectType,
+		SubjectID:   subjectID,
+		SubjectName: task.SubjectName,
+		AssigneeID:  task.AssigneeID,
+		CreatedBy:   task.CreatedBy,
+		Status:      task.Status,
+		DueAt:       task.DueAt,
+		RemindAt:    task.RemindAt,
+		RemindedAt:  task.RemindedAt,
+		SnoozeCount: task.SnoozeCount,
+		CompletedAt: task.CompletedAt,
+		CompletedBy: task.CompletedBy,
+		Outcome:     task.Outcome,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}, nil
+}
+
+func ToDomainTask(task mongoModels.Task) models.Task {
+	return models.Task{
+		ID:          task.ID.Hex(),
+		Title:       task.Title,
+		Notes:       task.Notes,
+		SubjectType: task.SubjectType,
+		SubjectID:   task.SubjectID.Hex(),
+		SubjectName: task.SubjectName,
+		AssigneeID:  task.AssigneeID,
+		CreatedBy:   task.CreatedBy,
+		Status:      task.Status,
+		DueAt:       task.DueAt,
+		RemindAt:    task.RemindAt,
+		RemindedAt:  task.RemindedAt,
+		SnoozeCount: task.SnoozeCount,
+		CompletedAt: task.CompletedAt,
+		CompletedBy: task.CompletedBy,
+		Outcome:     task.Outcome,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+}
+
+func ToDomainTaskSlice(tasks []mongoModels.Task) []models.Task {
+	result := make([]models.Task, len(tasks))
+	for i, task := range tasks {
+		result[i] = ToDomainTask(task)
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package converters
+
+import (
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ToMongoVisit(visit models.Visit) (mongoModels.Visit, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(visit.DealerID)
+	if err != nil {
+		return mongoModels.Visit{}, err
+	}
+	propertyObjectID, err := primitive.ObjectIDFromHex(visit.PropertyID)
+	if err != nil {
+		return mongoModels.Visit{}, err
+	}
+	leadObjectID, err := optionalObjectID(visit.LeadID)
+	if err != nil {
+		return mongoModels.Visit{}, err
+	}
+	dealerClientObjectID, err := optionalObjectID(visit.DealerClientID)
+	if err != nil {
+		return mongoModels.Visit{}, err
+	}
+
+	history := make([]mongoModels.VisitEvent, len(visit.History))
+	for i, event := range visit.History {
+		history[i] = ToMongoVisitEvent(event)
+	}
+
+	return mongoModels.Visit{
+		DealerID:       dealerObjectID,
+		PropertyID:     propertyObjectID,
+		PropertyNumber: visit.PropertyNumber,
+		LeadID:         leadObjectID,
+		DealerClientID: dealerClientObjectID,
+		ClientName:     visit.ClientName,
+		ClientPhone:    visit.ClientPhone,
+		AgentName:      visit.AgentName,
+		StartsAt:       visit.StartsAt,
+		EndsAt:         visit.EndsAt,
+		Status:         visit.Status,
+		Note:           visit.Note,
+		CancelReason:   visit.CancelReason,
+		OutcomeNote:    visit.OutcomeNote,
+		History:        history,
+		CreatedBy:      visit.CreatedBy,
+		CreatedAt:      visit.CreatedAt,
+		UpdatedAt:      visit.UpdatedAt,
+	}, nil
+}
+
+func ToMongoVisitEvent(event models.VisitEvent) mongoModels.VisitEvent {
+	return mongoModels.VisitEvent{
+		Status:    event.Status,
+		StartsAt:  event.StartsAt,
+		EndsAt:    event.EndsAt,
+		Note:      event.Note,
+		ChangedBy: event.ChangedBy,
+		ChangedAt: event.ChangedAt,
+	}
+}
+
+func ToDomainVisit(visit mongoModels.Visit) models.Visit {
+	history := make([]models.VisitEvent, len(visit.History))
+	for i, event := range visit.History {
+		history[i] = models.VisitEvent{
+			Status:    event.Status,
+			StartsAt:  event.StartsAt,
+			EndsAt:    event.EndsAt,
+			Note:      event.Note,
+			ChangedBy: event.ChangedBy,
+			ChangedAt: event.ChangedAt,
+		}
+	}
+
+	result := models.Visit{
+		ID:             visit.ID.Hex(),
+		DealerID:       visit.DealerID.Hex(),
+		PropertyID:     visit.PropertyID.Hex(),
+		PropertyNumber: visit.PropertyNumber,
+		ClientName:     visit.ClientName,
+		ClientPhone:    visit.ClientPhone,
+		AgentName:      visit.AgentName,
+		StartsAt:       visit.StartsAt,
+		EndsAt:         visit.EndsAt,
+		Status:         visit.Status,
+		Note:           visit.Note,
+		CancelReason:   visit.CancelReason,
+		OutcomeNote:    visit.OutcomeNote,
+		History:        history,
+		CreatedBy:      visit.CreatedBy,
+		CreatedAt:      visit.CreatedAt,
+		UpdatedAt:      visit.UpdatedAt,
+	}
+	if visit.LeadID != nil {
+		result.LeadID = visit.LeadID.Hex()
+	}
+	if visit.DealerClientID != nil {
+		result.DealerClientID = visit.DealerClientID.Hex()
+	}
+	return result
+}
+
+func ToDomainVisitSlice(visits []mongoModels.Visit) []models.Visit {
+	result := make([]models.Visit, len(visits))
+	for i, visit := range visits {
+		result[i] = ToDomainVisit(visit)
+	}
+	return result
+}
+
+func optionalObjectID(id string) (*primitive.ObjectID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return &objectID, nil
+}
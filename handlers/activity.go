@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+)
+
+// Lead and dealer client timelines are served from their own routes; these do the shared work
+
+func getActivities(w http.ResponseWriter, r *http.Request, service *services.ActivityService, subjectType string, subjectID string) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var params models.ActivityQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	activities, err := service.GetActivities(r.Context(), subjectType, subjectID, userID, role, params)
+	if err != nil {
+		writeActivityError(w, r, err, "Failed to fetch activities")
+		return
+	}
+
+	response.WithPayload(w, r, activities)
+}
+
+func logActivity(w http.ResponseWriter, r *http.Request, service *services.ActivityService, subjectType string, subjectID string) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.ActivityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	activity, err := service.LogActivity(r.Context(), subjectType, subjectID, userID, role, req)
+	if err != nil {
+		writeActivityError(w, r, err, "Failed to log activity")
+		return
+	}
+
+	response.WithPayload(w, r, activity)
+}
+
+func writeActivityError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrActivityInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrActivitySubjectNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, services.ErrActivityLeadNotVisible), errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
@@ -2,20 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"myapp/middlewares"
 	"myapp/response"
 	"myapp/services"
 	"net/http"
-	"time"
 )
 
 type CloudfareHandler struct {
-	Service *services.CloudflareR2Service
+	Service      services.ObjectStorage
+	MediaService *services.MediaService
 }
 
 func (h *CloudfareHandler) GeneratePresignedURL(w http.ResponseWriter, r *http.Request) {
-	if h.Service == nil {
+	if h.Service == nil || h.MediaService == nil {
 		response.WithInternalError(w, r, "Service not initialized")
 		return
 	}
@@ -27,7 +27,8 @@ func (h *CloudfareHandler) GeneratePresignedURL(w http.ResponseWriter, r *http.R
 	}
 
 	var req struct {
-		Count int `json:"count"`
+		Purpose string                `json:"purpose"`
+		Files   []services.UploadFile `json:"files"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -35,36 +36,245 @@ func (h *CloudfareHandler) GeneratePresignedURL(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	if req.Count <= 0 {
-		response.WithValidationError(w, r, "Count must be greater than 0")
+	if req.Purpose == "" || len(req.Files) == 0 {
+		response.WithValidationError(w, r, "purpose and files are required")
 		return
 	}
 
-	var urls []map[string]interface{}
+	uploads, err := h.MediaService.PrepareUploads(r.Context(), userID, req.Purpose, req.Files)
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to generate upload URL")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"presignedUrls": uploads,
+	})
+}
 
-	for i := 0; i < req.Count; i++ {
-		// Generate unique filename with timestamp
-		timestamp := time.Now().UnixNano()
-		fileName := fmt.Sprintf("file_%d_%d", timestamp, i)
+func (h *CloudfareHandler) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil || h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
 
-		// Create organized key structure
-		key := fmt.Sprintf("users/%s/%s", userID, fileName)
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
 
-		// Generate presigned URL (15 minutes expiry)
-		url, err := h.Service.GeneratePresignedURL(r.Context(), key, 15*time.Minute)
-		if err != nil {
-			response.WithInternalError(w, r, "Failed to generate upload URL")
-			return
-		}
+	var req struct {
+		FileKey string `json:"fileKey"`
+		Purpose string `json:"purpose"`
+	}
 
-		urls = append(urls, map[string]interface{}{
-			"presignedUrl": url, // ← CHANGED: "upload_url" → "presignedUrl"
-			"fileKey":      key, // ← CHANGED: "key" → "fileKey"
-		})
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	if req.FileKey == "" || req.Purpose == "" {
+		response.WithValidationError(w, r, "fileKey and purpose are required")
+		return
+	}
+
+	media, err := h.MediaService.ConfirmUpload(r.Context(), userID, req.FileKey, req.Purpose)
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to confirm upload")
+		return
+	}
+
+	response.WithPayload(w, r, media)
+}
+
+func (h *CloudfareHandler) StartMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	if h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Purpose     string `json:"purpose"`
+		ContentType string `json:"contentType"`
+		Size        int64  `json:"size"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	if req.Purpose == "" || req.ContentType == "" || req.Size <= 0 {
+		response.WithValidationError(w, r, "purpose, contentType and size are required")
+		return
+	}
+
+	upload, err := h.MediaService.StartMultipartUpload(r.Context(), userID, req.Purpose, services.UploadFile{
+		ContentType: req.ContentType,
+		Size:        req.Size,
+	})
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to start upload")
+		return
+	}
+
+	response.WithPayload(w, r, upload)
+}
+
+func (h *CloudfareHandler) PresignUploadParts(w http.ResponseWriter, r *http.Request) {
+	if h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		FileKey     string  `json:"fileKey"`
+		PartNumbers []int32 `json:"partNumbers"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	if req.FileKey == "" || len(req.PartNumbers) == 0 {
+		response.WithValidationError(w, r, "fileKey and partNumbers are required")
+		return
+	}
+
+	parts, err := h.MediaService.PresignParts(r.Context(), userID, req.FileKey, req.PartNumbers)
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to generate part URLs")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"parts": parts,
+	})
+}
+
+func (h *CloudfareHandler) GetUploadedParts(w http.ResponseWriter, r *http.Request) {
+	if h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
+
+	fileKey := r.URL.Query().Get("fileKey")
+	if fileKey == "" {
+		response.WithValidationError(w, r, "fileKey is required")
+		return
+	}
+
+	parts, err := h.MediaService.GetUploadedParts(r.Context(), userID, fileKey)
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to list uploaded parts")
+		return
 	}
 
-	// ← CHANGED: Return proper response structure
 	response.WithPayload(w, r, map[string]interface{}{
-		"presignedUrls": urls,
+		"parts": parts,
 	})
 }
+
+func (h *CloudfareHandler) CompleteMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	if h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		FileKey string `json:"fileKey"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	if req.FileKey == "" {
+		response.WithValidationError(w, r, "fileKey is required")
+		return
+	}
+
+	media, err := h.MediaService.CompleteMultipartUpload(r.Context(), userID, req.FileKey)
+	if err != nil {
+		writeMediaError(w, r, err, "Failed to complete upload")
+		return
+	}
+
+	response.WithPayload(w, r, media)
+}
+
+func (h *CloudfareHandler) AbortMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	if h.MediaService == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if userID == "" {
+		response.WithUnauthorized(w, r, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		FileKey string `json:"fileKey"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	if req.FileKey == "" {
+		response.WithValidationError(w, r, "fileKey is required")
+		return
+	}
+
+	if err := h.MediaService.AbortMultipartUpload(r.Context(), userID, req.FileKey); err != nil {
+		writeMediaError(w, r, err, "Failed to abort upload")
+		return
+	}
+
+	response.WithMessage(w, r, "Upload aborted")
+}
+
+func writeMediaError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrMediaNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrMediaNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, services.ErrMediaQuotaExceeded):
+		response.WithStatusCode(w, r, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, services.ErrMediaInvalid):
+		response.WithValidationError(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
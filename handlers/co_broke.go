@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CoBrokeHandler struct {
+	Service *services.CoBrokeService
+}
+
+func NewCoBrokeHandler(service *services.CoBrokeService) *CoBrokeHandler {
+	return &CoBrokeHandler{Service: service}
+}
+
+// SetListingTerms opts a listing in or out of the co-broke pool
+func (h *CoBrokeHandler) SetListingTerms(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var terms models.CoBrokeTerms
+	if err := json.NewDecoder(r.Body).Decode(&terms); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	saved, err := h.Service.SetListingTerms(r.Context(), dealerID, mux.Vars(r)["id"], terms)
+	if err != nil {
+		writeCoBrokeError(w, r, err, "Failed to update co-broke terms")
+		return
+	}
+
+	response.WithPayload(w, r, saved)
+}
+
+func (h *CoBrokeHandler) GetPool(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var params models.PropertyQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	listings, err := h.Service.GetPool(r.Context(), dealerID, params)
+	if err != nil {
+		if errors.Is(err, services.ErrCoBrokeInvalid) {
+			response.WithValidationError(w, r, err.Error())
+		} else {
+			response.WithInternalError(w, r, "Failed to fetch co-broke pool")
+		}
+		return
+	}
+
+	response.WithPayload(w, r, listings)
+}
+
+func (h *CoBrokeHandler) RequestCollaboration(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req struct {
+		PropertyID string `json:"property_id"`
+		Message    string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+	if req.PropertyID == "" {
+		response.WithValidationError(w, r, "property_id is required")
+		return
+	}
+
+	agreement, err := h.Service.RequestCollaboration(r.Context(), dealerID, req.PropertyID, req.Message)
+	if err != nil {
+		writeCoBrokeError(w, r, err, "Failed to request collaboration")
+		return
+	}
+
+	response.WithPayload(w, r, agreement)
+}
+
+func (h *CoBrokeHandler) GetAgreements(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	query := r.URL.Query()
+
+	agreements, err := h.Service.GetAgreements(r.Context(), dealerID, query.Get("side"), query.Get("status"))
+	if err != nil {
+		writeCoBrokeError(w, r, err, "Failed to fetch co-broke agreements")
+		return
+	}
+
+	response.WithPayload(w, r, agreements)
+}
+
+func (h *CoBrokeHandler) GetAgreement(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	agreement, err := h.Service.GetAgreement(r.Context(), mux.Vars(r)["id"], dealerID)
+	if err != nil {
+		writeCoBrokeError(w, r, err, "Failed to fetch co-broke agreement")
+		return
+	}
+
+	response.WithPayload(w, r, agreement)
+}
+
+// UpdateAgreement handles accept, decline, withdraw, complete and terminate
+func (h *CoBrokeHandler) UpdateAgreement(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	vars := mux.Vars(r)
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WithError(w, r, "Invalid request body")
+			return
+		}
+	}
+
+	agreement, err := h.Service.UpdateAgreement(r.Context(), vars["id"], dealerID, vars["action"], req.Note)
+	if err != nil {
+		writeCoBrokeError(w, r, err, "Failed to update co-broke agreement")
+		return
+	}
+
+	response.WithPayload(w, r, agreement)
+}
+
+func writeCoBrokeError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCoBrokeInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrCoBrokeNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		response.WithNotFound(w, r, "Property not found")
+	case errors.Is(err, services.ErrCoBrokeNotParty):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrCoBrokeUnavailable), errors.Is(err, services.ErrCoBrokeDuplicate), errors.Is(err, services.ErrCoBrokeInvalidAction):
+		response.WithConflict(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type ContactHandler struct {
+	Service *services.ContactService
+}
+
+func NewContactHandler(service *services.ContactService) *ContactHandler {
+	return &ContactHandler{
+		Service: service,
+	}
+}
+
+// GetContact returns a person's leads, inquiries, dealer clients, visits and tasks by phone number
+func (h *ContactHandler) GetContact(w http.ResponseWriter, r *http.Request) {
+	contact, err := h.Service.GetContact(r.Context(), mux.Vars(r)["phone"])
+	if err != nil {
+		writeContactError(w, r, err, "Failed to fetch contact")
+		return
+	}
+
+	response.WithPayload(w, r, contact)
+}
+
+// GetDuplicates lists phone numbers shared by several records; leads_only=true narrows it to
+// leads that can be merged
+func (h *ContactHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	var paging models.BaseQueryParams
+	if err := utils.ParseQueryParams(r, &paging); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	duplicates, err := h.Service.GetDuplicates(r.Context(), r.URL.Query().Get("leads_only") == "true", paging)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch duplicate contacts")
+		return
+	}
+
+	response.WithPayload(w, r, duplicates)
+}
+
+func writeContactError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrContactInvalid), errors.Is(err, services.ErrLeadMergeInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrContactNotFound), errors.Is(err, services.ErrLeadNotFound):
+		response.WithNotFound(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"myapp/constants"
+	"myapp/middlewares"
 	"myapp/models"
 	"myapp/response"
 	"myapp/services"
@@ -11,10 +14,14 @@ import (
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type DealerHandler struct {
-	Service *services.DealerService
+	Service         *services.DealerService
+	PropertyService *services.PropertyService
+	MediaService    *services.MediaService
+	Offboarding     *services.DealerOffboardingService
 }
 
 func (h *DealerHandler) CreateDealer(w http.ResponseWriter, r *http.Request) {
@@ -143,6 +150,12 @@ func (h *DealerHandler) UpdateDealer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.Service.GetDealerByID(r.Context(), dealerObjID.Hex())
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Dealer not found")
+		return
+	}
+
 	// Convert dealer to map for update
 	updateData := map[string]interface{}{
 		"name":           dealer.Name,
@@ -159,6 +172,12 @@ func (h *DealerHandler) UpdateDealer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A text watermark shows the shop name, so renaming the shop changes the branding
+	if existing.ShopName != dealer.ShopName && existing.Watermark != nil &&
+		existing.Watermark.Enabled && existing.Watermark.Type == constants.WatermarkTypeText {
+		h.PropertyService.RerenderDealerPhotosAsync(dealerObjID.Hex())
+	}
+
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Dealer updated"})
 
 }
@@ -178,7 +197,11 @@ func (h *DealerHandler) DeleteDealer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.Service.DeleteDealer(r.Context(), dealerObjID.Hex())
+	err = h.Offboarding.DeleteDealer(r.Context(), dealerObjID.Hex())
+	if errors.Is(err, services.ErrDealerHasRecords) {
+		response.WithConflict(w, r, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to delete dealer: "+err.Error())
 		return
@@ -231,3 +254,100 @@ func (h *DealerHandler) ResetPasswordDealer(w http.ResponseWriter, r *http.Reque
 
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Password reset successfully"})
 }
+
+// OffboardDealer moves a departing dealer's listings, clients and leads to a successor and
+// disables the account
+func (h *DealerHandler) OffboardDealer(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	dealerObjID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.WithValidationError(w, r, "Invalid dealer ID")
+		return
+	}
+
+	var request models.DealerOffboardRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	transfer, err := h.Offboarding.Offboard(r.Context(), dealerObjID.Hex(), request, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOffboardInvalid), errors.Is(err, services.ErrSuccessorNotAvailable):
+			response.WithValidationError(w, r, err.Error())
+		case errors.Is(err, mongo.ErrNoDocuments):
+			response.WithNotFound(w, r, "Dealer not found")
+		case errors.Is(err, services.ErrDealerDeactivated):
+			response.WithConflict(w, r, err.Error())
+		default:
+			response.WithInternalError(w, r, "Failed to offboard dealer")
+		}
+		return
+	}
+	response.WithPayload(w, r, transfer)
+}
+
+// GetDealerTransfers lists the offboarding transfers a dealer was on either side of
+func (h *DealerHandler) GetDealerTransfers(w http.ResponseWriter, r *http.Request) {
+	dealerObjID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.WithValidationError(w, r, "Invalid dealer ID")
+		return
+	}
+
+	transfers, err := h.Offboarding.GetTransfers(r.Context(), dealerObjID.Hex())
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch transfers")
+		return
+	}
+	response.WithPayload(w, r, transfers)
+}
+
+func (h *DealerHandler) UpdateWatermark(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	dealerObjID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		response.WithUnauthorized(w, r, "Invalid dealer ID")
+		return
+	}
+
+	var watermark models.WatermarkSettings
+	if err := json.NewDecoder(r.Body).Decode(&watermark); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+	if err := validate.ValidateWatermark(watermark); err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+	// Logos must come from the dealer's own confirmed uploads
+	if watermark.Type == constants.WatermarkTypeLogo {
+		if h.MediaService == nil {
+			response.WithInternalError(w, r, "Media service not initialized")
+			return
+		}
+		if err := h.MediaService.VerifyOwnedMedia(r.Context(), dealerObjID.Hex(), []string{watermark.LogoKey}, constants.MediaPurposeDealerLogo); err != nil {
+			if services.IsMediaError(err) {
+				response.WithForbidden(w, r, err.Error())
+			} else {
+				response.WithInternalError(w, r, "Failed to verify logo")
+			}
+			return
+		}
+	}
+
+	if err := h.Service.UpdateWatermark(r.Context(), dealerObjID.Hex(), watermark); err != nil {
+		if err == mongo.ErrNoDocuments {
+			response.WithNotFound(w, r, "Dealer not found")
+		} else {
+			response.WithInternalError(w, r, "Failed to update watermark: "+err.Error())
+		}
+		return
+	}
+
+	h.PropertyService.RerenderDealerPhotosAsync(dealerObjID.Hex())
+
+	response.WithMessage(w, r, "Watermark updated, listing photos are being re-rendered")
+}
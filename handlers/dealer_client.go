@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"myapp/middlewares"
 	"myapp/models"
 	"myapp/response"
@@ -11,11 +12,14 @@ import (
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Documents are stored privately and never given a public URL; they are fetched through DownloadDocument
 type DealerClientHandler struct {
-	Service             *services.DealerClientService
-	CloudflarePublicURL string
+	Service    *services.DealerClientService
+	Matching   *services.MatchingService
+	Activities *services.ActivityService
 }
 
 func (h *DealerClientHandler) CreateDealerClient(w http.ResponseWriter, r *http.Request) {
@@ -32,13 +36,14 @@ func (h *DealerClientHandler) CreateDealerClient(w http.ResponseWriter, r *http.
 	}
 
 	dealerClient.DealerID = dealerIDObj.Hex()
+	dealerClient.SourceInquiryID = ""
 
-	
-
-	id, err := h.Service.CreateDealerClient(r.Context(), dealerClient)
+	id, err := h.Service.CreateDealerClient(r.Context(), dealerClient, dealerID)
 	if err != nil {
-		if err.Error() == "phone number already exists" {
+		if errors.Is(err, services.ErrDealerClientPhoneExists) {
 			response.WithConflict(w, r, "Phone number already exists")
+		} else if services.IsMediaError(err) || errors.Is(err, services.ErrRequirementInvalid) {
+			response.WithValidationError(w, r, err.Error())
 		} else {
 			response.WithInternalError(w, r, "Failed to create dealer client: "+err.Error())
 		}
@@ -96,10 +101,13 @@ func (h *DealerClientHandler) UpdateDealerClient(w http.ResponseWriter, r *http.
 		return
 	}
 
-	
-
-	err = h.Service.UpdateDealerClient(r.Context(), objID.Hex(), dealerClientUpdate)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	err = h.Service.UpdateDealerClient(r.Context(), objID.Hex(), dealerClientUpdate, userID)
 	if err != nil {
+		if services.IsMediaError(err) || errors.Is(err, services.ErrRequirementInvalid) {
+			response.WithValidationError(w, r, err.Error())
+			return
+		}
 		response.WithInternalError(w, r, "Failed to update client. Please try again later.")
 		return
 	}
@@ -143,7 +151,8 @@ func (h *DealerClientHandler) CreateDealerClientPropertyInterest(w http.Response
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	err = h.Service.CreateDealerClientPropertyInterest(r.Context(), objID.Hex(), dealerClientPropertyInterest)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	err = h.Service.CreateDealerClientPropertyInterest(r.Context(), objID.Hex(), dealerClientPropertyInterest, userID)
 	if err != nil {
 		if err.Error() == "client is already added to this property" {
 			response.WithConflict(w, r, "Client is already added to this property")
@@ -171,7 +180,8 @@ func (h *DealerClientHandler) UpdateDealerClientPropertyInterest(w http.Response
 		return
 	}
 
-	err := h.Service.UpdateDealerClientPropertyInterest(r.Context(), dealerClientID, propertyInterestID, update)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	err := h.Service.UpdateDealerClientPropertyInterest(r.Context(), dealerClientID, propertyInterestID, update, userID)
 	if err != nil {
 		response.WithInternalError(w, r, "Failed to update property interest")
 		return
@@ -195,3 +205,88 @@ func (h *DealerClientHandler) DeleteDealerClientPropertyInterest(w http.Response
 	}
 	response.WithMessage(w, r, "Property interest deleted successfully")
 }
+
+func (h *DealerClientHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	dealerClientID := mux.Vars(r)["dealerClientID"]
+	if _, err := primitive.ObjectIDFromHex(dealerClientID); err != nil {
+		response.WithValidationError(w, r, "Invalid dealer client ID")
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		response.WithValidationError(w, r, "key is required")
+		return
+	}
+
+	url, expiresAt, err := h.Service.GetDocumentDownloadURL(r.Context(), dealerID, dealerClientID, key, utils.ClientIP(r), r.UserAgent())
+	if err != nil {
+		writeDocumentError(w, r, err, "Failed to generate download link")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+
+func (h *DealerClientHandler) GetDocumentAccessLog(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	dealerClientID := mux.Vars(r)["dealerClientID"]
+	if _, err := primitive.ObjectIDFromHex(dealerClientID); err != nil {
+		response.WithValidationError(w, r, "Invalid dealer client ID")
+		return
+	}
+
+	entries, err := h.Service.GetDocumentAccessLog(r.Context(), dealerID, dealerClientID)
+	if err != nil {
+		writeDocumentError(w, r, err, "Failed to fetch access log")
+		return
+	}
+
+	response.WithPayload(w, r, entries)
+}
+
+func writeDocumentError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		response.WithNotFound(w, r, "Dealer client not found")
+	case errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrDocumentNotFound):
+		response.WithNotFound(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
+
+// GetMatches ranks the dealer's listings against the client's structured requirement
+func (h *DealerClientHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	limit, err := matchLimit(r)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+
+	matches, err := h.Matching.MatchesForDealerClient(r.Context(), mux.Vars(r)["dealerClientID"], dealerID, role, limit)
+	if err != nil {
+		writeMatchingError(w, r, err, "Dealer client not found")
+		return
+	}
+	response.WithPayload(w, r, matches)
+}
+
+// GetActivities returns the client's timeline, newest first
+func (h *DealerClientHandler) GetActivities(w http.ResponseWriter, r *http.Request) {
+	getActivities(w, r, h.Activities, models.ActivitySubjectDealerClient, mux.Vars(r)["dealerClientID"])
+}
+
+// LogActivity adds a call, WhatsApp message, visit or note to the client's timeline
+func (h *DealerClientHandler) LogActivity(w http.ResponseWriter, r *http.Request) {
+	logActivity(w, r, h.Activities, models.ActivitySubjectDealerClient, mux.Vars(r)["dealerClientID"])
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"myapp/middlewares"
@@ -41,6 +42,10 @@ func (h *InquiryHandler) CreateInquiry(w http.ResponseWriter, r *http.Request) {
 	}
 
 	createdInquiry, err := h.Service.CreateInquiry(r.Context(), inquiry)
+	if errors.Is(err, services.ErrRequirementInvalid) {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
 	if err != nil {
 		response.WithInternalError(w, r, "Failed to create inquiry")
 		return
@@ -89,6 +94,10 @@ func (h *InquiryHandler) UpdateInquiry(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err := h.Service.UpdateInquiry(r.Context(), id, updates)
+	if errors.Is(err, services.ErrRequirementInvalid) {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
 	if err != nil {
 		response.WithInternalError(w, r, "Failed to update inquiry")
 		return
@@ -109,3 +118,36 @@ func (h *InquiryHandler) DeleteInquiry(w http.ResponseWriter, r *http.Request) {
 
 	response.WithMessage(w, r, "Inquiry deleted successfully")
 }
+
+func (h *InquiryHandler) ConvertInquiry(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.InquiryConvertRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WithError(w, r, "Invalid request body")
+			return
+		}
+	}
+
+	inquiry, err := h.Service.ConvertInquiry(r.Context(), mux.Vars(r)["id"], req.ConvertTo, userID, role)
+	switch {
+	case errors.Is(err, services.ErrInquiryNotFound):
+		response.WithNotFound(w, r, "Inquiry not found")
+	case errors.Is(err, services.ErrInquiryNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrInquiryAlreadyConverted):
+		response.WithConflict(w, r, err.Error())
+	case errors.Is(err, services.ErrLeadPhoneExists):
+		response.WithConflict(w, r, "A lead with this phone number already exists")
+	case errors.Is(err, services.ErrDealerClientPhoneExists):
+		response.WithConflict(w, r, "The dealer already has a client with this phone number")
+	case errors.Is(err, services.ErrInquiryConversionInvalid), errors.Is(err, services.ErrRequirementInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case err != nil:
+		response.WithInternalError(w, r, "Failed to convert inquiry")
+	default:
+		response.WithPayload(w, r, inquiry)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"myapp/constants"
 	"myapp/middlewares"
 	"myapp/models"
@@ -20,6 +21,11 @@ import (
 type LeadHandler struct {
 	Service         *services.LeadService
 	PropertyService *services.PropertyService
+	Matching        *services.MatchingService
+	Routing         *services.LeadRoutingService
+	Contacts        *services.ContactService
+	Activities      *services.ActivityService
+	Aadhar          *services.AadharService
 }
 
 func (h *LeadHandler) CreateLead(w http.ResponseWriter, r *http.Request) {
@@ -32,7 +38,17 @@ func (h *LeadHandler) CreateLead(w http.ResponseWriter, r *http.Request) {
 		response.WithValidationError(w, r, "Name and phone are required")
 		return
 	}
-	id, err := h.Service.CreateLead(r.Context(), lead)
+	lead.SourceInquiryID = ""
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	id, err := h.Service.CreateLead(r.Context(), lead, userID)
+	if errors.Is(err, services.ErrRequirementInvalid) || errors.Is(err, services.ErrAadharInvalid) {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrAadharNotConfigured) {
+		response.WithStatusCode(w, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
 	if err != nil {
 		response.WithInternalError(w, r, "Failed to create lead: "+err.Error())
 		return
@@ -72,6 +88,25 @@ func (h *LeadHandler) GetLead(w http.ResponseWriter, r *http.Request) {
 
 
 
+// GetMatches ranks listings against the lead's structured requirement
+func (h *LeadHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	limit, err := matchLimit(r)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+
+	matches, err := h.Matching.MatchesForLead(r.Context(), mux.Vars(r)["id"], userID, role, limit)
+	if err != nil {
+		writeMatchingError(w, r, err, "Lead not found")
+		return
+	}
+	response.WithPayload(w, r, matches)
+}
+
 func (h *LeadHandler) GetAllLeadsByDealerID(w http.ResponseWriter, r *http.Request) {
 	dealerID := r.URL.Query().Get("dealer_id")
 	if dealerID == "" {
@@ -120,10 +155,15 @@ func (h *LeadHandler) UpdateLead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.Service.UpdateLead(r.Context(), objID.Hex(), updateData)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	err = h.Service.UpdateLead(r.Context(), objID.Hex(), updateData, userID)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			response.WithNotFound(w, r, "Lead not found")
+		} else if errors.Is(err, services.ErrRequirementInvalid) || errors.Is(err, services.ErrLeadAssignInvalid) || errors.Is(err, services.ErrAadharInvalid) {
+			response.WithValidationError(w, r, err.Error())
+		} else if errors.Is(err, services.ErrAadharNotConfigured) {
+			response.WithStatusCode(w, r, http.StatusServiceUnavailable, err.Error())
 		} else {
 			response.WithInternalError(w, r, "Failed to update lead: "+err.Error())
 		}
@@ -159,7 +199,8 @@ func (h *LeadHandler) AddPropertyInterest(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = h.Service.AddPropertyInterest(r.Context(), objID.Hex(), propertyInterest)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	err = h.Service.AddPropertyInterest(r.Context(), objID.Hex(), propertyInterest, userID)
 	if err != nil {
 		if err.Error() == "property already added to this lead" {
 			json.NewEncoder(w).Encode(map[string]string{
@@ -200,6 +241,7 @@ func (h *LeadHandler) GetLeads(w http.ResponseWriter, r *http.Request) {
 
 	if userRole == constants.Dealer {
 		params.DealerID = &userID
+		params.AddArrayFilter("dealer_id")
 	}
 
 	leads, err := h.Service.GetLeads(r.Context(), params)
@@ -262,52 +304,32 @@ func (h *LeadHandler) UpdatePropertyInterest(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Decode the status update
-	var updateData struct {
-		Status    string `json:"status"`
-		SoldPrice int64  `json:"sold_price"`
-		Note      string `json:"note"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+	var update models.PropertyInterestStatusUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	// Validate status
-	validStatuses := []string{"view", "ongoing", "converted", "closed"}
-	isValid := false
-	for _, status := range validStatuses {
-		if status == updateData.Status {
-			isValid = true
-			break
-		}
-	}
-	if !isValid {
-		http.Error(w, "Invalid status. Must be one of: view, ongoing, converted, closed", http.StatusBadRequest)
-		return
-	}
-
-	// Validate note: only allowed for "ongoing" status
-	if updateData.Note != "" && updateData.Status != "ongoing" {
-		http.Error(w, "Note is only allowed when status is 'ongoing'", http.StatusBadRequest)
-		return
-	}
-
-	// ← CALL the specific service method
-	err = h.Service.UpdatePropertyInterest(r.Context(), leadObjID.Hex(), propertyObjID.Hex(), updateData.Status, updateData.Note)
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	interest, err := h.Service.ChangePropertyInterestStatus(r.Context(), leadObjID.Hex(), propertyObjID.Hex(), update, userID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			http.Error(w, "Property interest not found for this lead", http.StatusNotFound)
-		} else {
-			http.Error(w, "Failed to update property status: "+err.Error(), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, services.ErrPropertyInterestNotFound):
+			response.WithNotFound(w, r, err.Error())
+		case errors.Is(err, services.ErrLeadStatusInvalid):
+			response.WithValidationError(w, r, err.Error())
+		case errors.Is(err, services.ErrLeadStatusConflict):
+			response.WithConflict(w, r, err.Error())
+		default:
+			response.WithInternalError(w, r, "Failed to update property status: "+err.Error())
 		}
 		return
 	}
-	if updateData.Status == "converted" {
+	if interest.Status == models.LeadStatusConverted {
 		soldDate := time.Now()
 		err = h.PropertyService.UpdateProperty(propertyObjID.Hex(), models.PropertyUpdate{
 			Sold:      &[]bool{true}[0],
-			SoldPrice: &updateData.SoldPrice,
+			SoldPrice: &interest.SoldPrice,
 			UpdatedAt: &soldDate,
 			SoldDate:  &soldDate,
 		})
@@ -317,10 +339,139 @@ func (h *LeadHandler) UpdatePropertyInterest(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Property status updated successfully",
+	response.WithPayload(w, r, map[string]interface{}{
+		"message":           "Property status updated successfully",
+		"property_interest": interest,
 	})
 }
 
+// GetAssignedLeads lists the leads assigned to the calling dealer
+func (h *LeadHandler) GetAssignedLeads(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var params models.LeadQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	leads, err := h.Service.GetAssignedLeads(r.Context(), dealerID, params)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch leads")
+		return
+	}
+
+	response.WithPayload(w, r, leads)
+}
+
+// AssignLead hands the lead to another dealer; an empty dealer_id unassigns it
+func (h *LeadHandler) AssignLead(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req models.LeadAssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	assignment, err := h.Routing.AssignLead(r.Context(), mux.Vars(r)["leadID"], req, adminID)
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to assign lead")
+		return
+	}
 
+	response.WithPayload(w, r, assignment)
+}
+
+// RouteLead assigns the lead by the routing rules, as if it had just been created
+func (h *LeadHandler) RouteLead(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	assignment, err := h.Routing.RouteLead(r.Context(), mux.Vars(r)["leadID"], adminID)
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to route lead")
+		return
+	}
+
+	response.WithPayload(w, r, assignment)
+}
+
+func (h *LeadHandler) GetAssignments(w http.ResponseWriter, r *http.Request) {
+	assignments, err := h.Routing.GetAssignments(r.Context(), mux.Vars(r)["leadID"])
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to fetch assignment history")
+		return
+	}
+
+	response.WithPayload(w, r, assignments)
+}
+
+// MergeLead folds the lead named in the body into this one and deletes it
+func (h *LeadHandler) MergeLead(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req models.LeadMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	result, err := h.Contacts.MergeLeads(r.Context(), mux.Vars(r)["leadID"], req, adminID)
+	if err != nil {
+		writeContactError(w, r, err, "Failed to merge leads")
+		return
+	}
+
+	response.WithPayload(w, r, result)
+}
+
+// GetActivities returns the lead's timeline, newest first
+func (h *LeadHandler) GetActivities(w http.ResponseWriter, r *http.Request) {
+	getActivities(w, r, h.Activities, models.ActivitySubjectLead, mux.Vars(r)["id"])
+}
+
+// LogActivity adds a call, WhatsApp message, visit or note to the lead's timeline
+func (h *LeadHandler) LogActivity(w http.ResponseWriter, r *http.Request) {
+	logActivity(w, r, h.Activities, models.ActivitySubjectLead, mux.Vars(r)["id"])
+}
+
+// RevealAadhar returns the lead's full Aadhaar number and photo, recording who asked and why
+func (h *LeadHandler) RevealAadhar(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req models.AadharRevealRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WithError(w, r, "Invalid request body")
+			return
+		}
+	}
+
+	reveal, err := h.Aadhar.Reveal(r.Context(), mux.Vars(r)["leadID"], adminID, req.Reason, utils.ClientIP(r), r.UserAgent())
+	if err != nil {
+		writeAadharError(w, r, err, "Failed to reveal Aadhaar details")
+		return
+	}
+
+	response.WithPayload(w, r, reveal)
+}
+
+// GetAadharAccessLog lists who revealed the lead's Aadhaar details, most recent first
+func (h *LeadHandler) GetAadharAccessLog(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.Aadhar.GetAccessLog(r.Context(), mux.Vars(r)["leadID"])
+	if err != nil {
+		writeAadharError(w, r, err, "Failed to fetch Aadhaar access log")
+		return
+	}
+
+	response.WithPayload(w, r, entries)
+}
+
+func writeAadharError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrLeadNotFound), errors.Is(err, services.ErrAadharNotFound):
+		response.WithNotFound(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+
+	"github.com/gorilla/mux"
+)
+
+type LeadRoutingHandler struct {
+	Service *services.LeadRoutingService
+}
+
+func NewLeadRoutingHandler(service *services.LeadRoutingService) *LeadRoutingHandler {
+	return &LeadRoutingHandler{Service: service}
+}
+
+func (h *LeadRoutingHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req models.LeadRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	rule, err := h.Service.CreateRule(r.Context(), req)
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to create routing rule")
+		return
+	}
+
+	response.WithPayload(w, r, rule)
+}
+
+// GetRules lists every rule, inactive ones included, in the order they are tried
+func (h *LeadRoutingHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.Service.GetRules(r.Context())
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch routing rules")
+		return
+	}
+
+	response.WithPayload(w, r, rules)
+}
+
+func (h *LeadRoutingHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.Service.GetRule(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to fetch routing rule")
+		return
+	}
+
+	response.WithPayload(w, r, rule)
+}
+
+func (h *LeadRoutingHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	var req models.LeadRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	rule, err := h.Service.UpdateRule(r.Context(), mux.Vars(r)["id"], req)
+	if err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to update routing rule")
+		return
+	}
+
+	response.WithPayload(w, r, rule)
+}
+
+func (h *LeadRoutingHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.Service.DeleteRule(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeLeadRoutingError(w, r, err, "Failed to delete routing rule")
+		return
+	}
+
+	response.WithMessage(w, r, "Routing rule deleted")
+}
+
+func writeLeadRoutingError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrRoutingRuleInvalid),
+		errors.Is(err, services.ErrLeadAssignInvalid),
+		errors.Is(err, services.ErrAssigneeNotAvailable),
+		errors.Is(err, services.ErrLeadNotRouted):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrRoutingRuleNotFound), errors.Is(err, services.ErrLeadNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, services.ErrLeadAssignConflict):
+		response.WithConflict(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"myapp/response"
+	"myapp/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// matchLimit reads the optional limit query parameter shared by the matching endpoints
+func matchLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > 100 {
+		return 0, errors.New("limit must be between 1 and 100")
+	}
+	return limit, nil
+}
+
+func writeMatchingError(w http.ResponseWriter, r *http.Request, err error, notFound string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+		response.WithNotFound(w, r, notFound)
+	case errors.Is(err, services.ErrRequirementMissing), errors.Is(err, services.ErrRequirementInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrRequirementNotVisible),
+		errors.Is(err, services.ErrPropertyNotOwned),
+		errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, "Failed to find matches")
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"myapp/response"
+	"myapp/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaHandler serves the admin media library
+type MediaHandler struct {
+	Service          *services.MediaService
+	OrphanRetainDays int
+}
+
+func (h *MediaHandler) ListObjects(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := int64(100)
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || parsed <= 0 || parsed > 1000 {
+			response.WithValidationError(w, r, "limit must be between 1 and 1000")
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.Service.ListObjects(r.Context(), query.Get("prefix"), query.Get("token"), int32(limit))
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to list objects")
+		return
+	}
+
+	response.WithPayload(w, r, page)
+}
+
+func (h *MediaHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+
+	dealerID := r.URL.Query().Get("dealer_id")
+	if dealerID != "" {
+		if _, err := primitive.ObjectIDFromHex(dealerID); err != nil {
+			response.WithValidationError(w, r, "Invalid dealer ID")
+			return
+		}
+	}
+
+	usage, err := h.Service.GetUsage(r.Context(), dealerID)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch storage usage")
+		return
+	}
+
+	response.WithPayload(w, r, usage)
+}
+
+func (h *MediaHandler) GetReferences(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		response.WithValidationError(w, r, "key is required")
+		return
+	}
+
+	references, err := h.Service.FindReferences(r.Context(), key)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to look up references")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"key":        key,
+		"referenced": len(references) > 0,
+		"references": references,
+	})
+}
+
+// SweepOrphans runs the orphan sweeper on demand. It is a dry run unless dry_run=false is passed.
+func (h *MediaHandler) SweepOrphans(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		response.WithInternalError(w, r, "Service not initialized")
+		return
+	}
+
+	query := r.URL.Query()
+	dryRun := query.Get("dry_run") != "false"
+	days := h.OrphanRetainDays
+	if days < 1 {
+		days = 30
+	}
+	if value := query.Get("older_than_days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			response.WithValidationError(w, r, "older_than_days must be a positive number")
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.Service.SweepOrphans(r.Context(), time.Duration(days)*24*time.Hour, dryRun)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to sweep orphaned media")
+		return
+	}
+
+	response.WithPayload(w, r, report)
+}
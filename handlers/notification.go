@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type NotificationHandler struct {
+	Service *services.NotificationService
+}
+
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Service: service}
+}
+
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var params models.NotificationQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	notifications, unread, err := h.Service.GetNotifications(r.Context(), userID, params)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch notifications")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"notifications": notifications,
+		"unread":        unread,
+	})
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if err := h.Service.MarkRead(r.Context(), mux.Vars(r)["id"], userID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			response.WithNotFound(w, r, "Notification not found")
+		} else {
+			response.WithInternalError(w, r, "Failed to update notification")
+		}
+		return
+	}
+
+	response.WithMessage(w, r, "Notification marked as read")
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	count, err := h.Service.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to update notifications")
+		return
+	}
+
+	response.WithPayload(w, r, map[string]int64{"marked": count})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"myapp/constants"
 	"myapp/middlewares"
 	"myapp/models"
@@ -10,16 +11,21 @@ import (
 	"myapp/utils"
 	"myapp/validate"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type PropertyHandler struct {
 	Service             *services.PropertyService
 	DealerService       *services.DealerService
 	CloudflarePublicURL string
+	Brochures           *services.BrochureService
+	Matching            *services.MatchingService
+	Analytics           *services.AnalyticsService
 }
 
 
@@ -58,15 +64,17 @@ func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request)
 	}
 
 	property.DealerID = dealerIDObj.Hex()
+	// Listings join the co-broke pool through the co-broke endpoints only
+	property.CoBroke = nil
 
 	now := time.Now()
 	property.CreatedAt = now
 
 	publicURLPrefix := h.CloudflarePublicURL
 
-	for i, photoKey := range property.Photos {
-		if photoKey != "" {
-			property.Photos[i] = publicURLPrefix + photoKey
+	for i, photo := range property.Photos {
+		if photo.Key != "" {
+			property.Photos[i].URL = publicURLPrefix + photo.Key
 		}
 	}
 
@@ -74,7 +82,11 @@ func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request)
 
 	id, err := h.Service.CreateProperty(r.Context(), property)
 	if err != nil {
-		response.WithInternalError(w, r, "Failed to create property: "+err.Error())
+		if services.IsMediaError(err) {
+			response.WithValidationError(w, r, err.Error())
+		} else {
+			response.WithInternalError(w, r, "Failed to create property: "+err.Error())
+		}
 		return
 	}
 
@@ -109,7 +121,20 @@ func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The public URL of an uploaded photo always derives from its key, never from the client
+	if updates.Photos != nil {
+		for i, photo := range *updates.Photos {
+			if photo.Key != "" {
+				(*updates.Photos)[i].URL = h.CloudflarePublicURL + photo.Key
+			}
+		}
+	}
+
 	if err := h.Service.UpdateProperty(objID.Hex(), updates); err != nil {
+		if services.IsMediaError(err) || errors.Is(err, services.ErrPropertyInvalid) {
+			response.WithValidationError(w, r, err.Error())
+			return
+		}
 		http.Error(w, "Failed to update property", http.StatusInternalServerError)
 		return
 	}
@@ -180,8 +205,123 @@ func (h *PropertyHandler) GetProperties(w http.ResponseWriter, r *http.Request)
 	
 }
 
+// GetBrochure serves a printable one-page PDF of the listing for walk-in clients
+func (h *PropertyHandler) GetBrochure(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	if h.Brochures == nil {
+		response.WithStatusCode(w, r, http.StatusServiceUnavailable, "Brochures are not available")
+		return
+	}
+
+	brochure, err := h.Brochures.GetBrochure(r.Context(), mux.Vars(r)["id"], userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+			response.WithNotFound(w, r, "Property not found")
+		case errors.Is(err, services.ErrPropertyNotOwned):
+			response.WithForbidden(w, r, err.Error())
+		default:
+			response.WithInternalError(w, r, "Failed to generate brochure")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="`+brochure.FileName+`"`)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Write(brochure.Data)
+}
+
+// GetMatchingLeads ranks the requirements this listing meets. Admins see leads and inquiries,
+// the listing's dealer sees their own clients and inquiries.
+func (h *PropertyHandler) GetMatchingLeads(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
 
+	limit, err := matchLimit(r)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
 
+	matches, err := h.Matching.MatchingRequirements(r.Context(), mux.Vars(r)["id"], userID, role, limit)
+	if err != nil {
+		writeMatchingError(w, r, err, "Property not found")
+		return
+	}
+	response.WithPayload(w, r, matches)
+}
 
+// GetStats returns a listing's views, brochure downloads and interests per day. The optional
+// days parameter sets the range, 30 days by default.
+func (h *PropertyHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
 
+	days, err := statsDays(r)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
 
+	stats, err := h.Analytics.GetPropertyStats(r.Context(), mux.Vars(r)["id"], userID, role, days)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrStatsRangeInvalid):
+			response.WithValidationError(w, r, err.Error())
+		case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+			response.WithNotFound(w, r, "Property not found")
+		case errors.Is(err, services.ErrPropertyNotOwned):
+			response.WithForbidden(w, r, err.Error())
+		default:
+			response.WithInternalError(w, r, "Failed to fetch property stats")
+		}
+		return
+	}
+	response.WithPayload(w, r, stats)
+}
+
+// GetStatsSummary totals engagement across a dealer's listings. Dealers see their own; admins
+// pass dealer_id.
+func (h *PropertyHandler) GetStatsSummary(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+	if role != constants.Dealer {
+		dealerID = r.URL.Query().Get("dealer_id")
+	}
+	if _, err := primitive.ObjectIDFromHex(dealerID); err != nil {
+		response.WithValidationError(w, r, "Invalid dealer ID")
+		return
+	}
+
+	days, err := statsDays(r)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+
+	summary, err := h.Analytics.GetDealerSummary(r.Context(), dealerID, days)
+	if err != nil {
+		if errors.Is(err, services.ErrStatsRangeInvalid) {
+			response.WithValidationError(w, r, err.Error())
+			return
+		}
+		response.WithInternalError(w, r, "Failed to fetch stats summary")
+		return
+	}
+	response.WithPayload(w, r, summary)
+}
+
+func statsDays(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("days")
+	if value == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, services.ErrStatsRangeInvalid
+	}
+	return days, nil
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type SavedSearchHandler struct {
+	Service *services.SavedSearchService
+}
+
+func NewSavedSearchHandler(service *services.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{Service: service}
+}
+
+func (h *SavedSearchHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req models.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	search, err := h.Service.CreateSavedSearch(r.Context(), dealerID, req)
+	if err != nil {
+		writeSavedSearchError(w, r, err, "Failed to save search")
+		return
+	}
+
+	response.WithPayload(w, r, search)
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	searches, err := h.Service.GetSavedSearches(r.Context(), dealerID, r.URL.Query().Get("dealer_client_id"))
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch saved searches")
+		return
+	}
+
+	response.WithPayload(w, r, searches)
+}
+
+func (h *SavedSearchHandler) GetSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	search, err := h.Service.GetSavedSearch(r.Context(), mux.Vars(r)["id"], dealerID)
+	if err != nil {
+		writeSavedSearchError(w, r, err, "Failed to fetch saved search")
+		return
+	}
+
+	response.WithPayload(w, r, search)
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req models.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	search, err := h.Service.UpdateSavedSearch(r.Context(), mux.Vars(r)["id"], dealerID, req)
+	if err != nil {
+		writeSavedSearchError(w, r, err, "Failed to update saved search")
+		return
+	}
+
+	response.WithPayload(w, r, search)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	if err := h.Service.DeleteSavedSearch(r.Context(), mux.Vars(r)["id"], dealerID); err != nil {
+		writeSavedSearchError(w, r, err, "Failed to delete saved search")
+		return
+	}
+
+	response.WithMessage(w, r, "Saved search deleted")
+}
+
+// RunSavedSearch returns the search's current results; page, limit and fields work as on GET /properties
+func (h *SavedSearchHandler) RunSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var paging models.BaseQueryParams
+	if err := utils.ParseQueryParams(r, &paging); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	properties, err := h.Service.RunSavedSearch(r.Context(), mux.Vars(r)["id"], dealerID, paging.Page, paging.Limit, utils.ParseFieldSelection(r))
+	if err != nil {
+		writeSavedSearchError(w, r, err, "Failed to run saved search")
+		return
+	}
+
+	response.WithPayload(w, r, properties)
+}
+
+func (h *SavedSearchHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var paging models.BaseQueryParams
+	if err := utils.ParseQueryParams(r, &paging); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	matches, err := h.Service.GetMatches(r.Context(), mux.Vars(r)["id"], dealerID, paging.Page, paging.Limit)
+	if err != nil {
+		writeSavedSearchError(w, r, err, "Failed to fetch matches")
+		return
+	}
+
+	response.WithPayload(w, r, matches)
+}
+
+func writeSavedSearchError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSavedSearchInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrSavedSearchNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
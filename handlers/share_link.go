@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ShareLinkHandler struct {
+	Service *services.ShareLinkService
+	// AppURL is the public base URL the share links point at
+	AppURL string
+}
+
+func NewShareLinkHandler(service *services.ShareLinkService, appURL string) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		Service: service,
+		AppURL:  appURL,
+	}
+}
+
+func (h *ShareLinkHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	var req struct {
+		PropertyIDs    []string `json:"property_ids"`
+		Title          string   `json:"title"`
+		ExpiresInHours int      `json:"expires_in_hours"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+	if req.ExpiresInHours < 0 {
+		response.WithValidationError(w, r, "expires_in_hours must be positive")
+		return
+	}
+
+	link, token, err := h.Service.CreateShareLink(r.Context(), dealerID, req.PropertyIDs, req.Title, time.Duration(req.ExpiresInHours)*time.Hour)
+	if err != nil {
+		response.WithValidationError(w, r, err.Error())
+		return
+	}
+
+	response.WithPayload(w, r, map[string]interface{}{
+		"link":     link,
+		"token":    token,
+		"url":      h.AppURL + "/share/" + token + "/page",
+		"json_url": h.AppURL + "/share/" + token,
+	})
+}
+
+func (h *ShareLinkHandler) GetShareLinks(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+
+	links, err := h.Service.GetShareLinks(r.Context(), dealerID)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch share links")
+		return
+	}
+
+	response.WithPayload(w, r, links)
+}
+
+func (h *ShareLinkHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	id := mux.Vars(r)["id"]
+
+	if err := h.Service.RevokeShareLink(r.Context(), id, dealerID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			response.WithNotFound(w, r, "Share link not found")
+		} else {
+			response.WithInternalError(w, r, "Failed to revoke share link")
+		}
+		return
+	}
+
+	response.WithMessage(w, r, "Share link revoked")
+}
+
+// GetSharedListing is the public JSON view of a share link
+func (h *ShareLinkHandler) GetSharedListing(w http.ResponseWriter, r *http.Request) {
+	viewerID := services.PublicViewerID(utils.ClientIP(r), r.UserAgent())
+	listing, err := h.Service.OpenShareLink(r.Context(), mux.Vars(r)["token"], viewerID)
+	if err != nil {
+		writeShareLinkError(w, r, err)
+		return
+	}
+
+	response.WithPayload(w, r, listing)
+}
+
+// GetSharedPage renders the share link as a dealer-branded page for messaging apps and browsers
+func (h *ShareLinkHandler) GetSharedPage(w http.ResponseWriter, r *http.Request) {
+	viewerID := services.PublicViewerID(utils.ClientIP(r), r.UserAgent())
+	listing, err := h.Service.OpenShareLink(r.Context(), mux.Vars(r)["token"], viewerID)
+	if err != nil {
+		writeShareLinkError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := sharedPageTemplate.Execute(w, listing); err != nil {
+		response.WithInternalError(w, r, "Failed to render page")
+	}
+}
+
+func writeShareLinkError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrShareLinkInvalid):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, services.ErrShareLinkExpired):
+		response.WithStatusCode(w, r, http.StatusGone, err.Error())
+	default:
+		response.WithInternalError(w, r, "Failed to open share link")
+	}
+}
+
+var sharedPageTemplate = template.Must(template.New("shared").Funcs(template.FuncMap{
+	"photo": func(p models.Photo) string {
+		if url, ok := p.Variants[models.PhotoVariantMedium]; ok {
+			return url
+		}
+		return p.URL
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{if .Title}}{{.Title}}{{else}}Properties from {{.Dealer.ShopName}}{{end}}</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:0;background:#f5f5f5;color:#222}
+header{background:#fff;padding:16px;display:flex;align-items:center;gap:12px;border-bottom:1px solid #ddd}
+header img{height:48px}
+main{max-width:720px;margin:auto;padding:16px}
+article{background:#fff;border-radius:8px;margin-bottom:16px;overflow:hidden}
+article img{width:100%;display:block}
+.body{padding:12px 16px}
+.price{font-weight:bold;font-size:1.1em}
+.sold{color:#b00}
+footer{text-align:center;padding:16px;color:#666}
+</style>
+</head>
+<body>
+<header>
+{{if .Dealer.LogoURL}}<img src="{{.Dealer.LogoURL}}" alt="">{{end}}
+<div><strong>{{.Dealer.ShopName}}</strong><br>{{.Dealer.Name}} &middot; <a href="tel:{{.Dealer.Phone}}">{{.Dealer.Phone}}</a></div>
+</header>
+<main>
+{{if .Title}}<h2>{{.Title}}</h2>{{end}}
+{{range .Properties}}
+<article>
+{{range $i, $p := .Photos}}{{if eq $i 0}}<img src="{{photo $p}}" alt="">{{end}}{{end}}
+<div class="body">
+<h3>{{.Title}}{{if .Sold}} <span class="sold">(Sold)</span>{{end}}</h3>
+<div class="price">&#8377;{{.MinPrice}}{{if gt .MaxPrice .MinPrice}} - &#8377;{{.MaxPrice}}{{end}}</div>
+<p>{{if .Bedrooms}}{{.Bedrooms}} BHK &middot; {{end}}{{if .Area}}{{.Area}} sq ft &middot; {{end}}{{.PropertyType}}{{if .Furnishing}} &middot; {{.Furnishing}}{{end}}</p>
+{{if .NearestLandmark}}<p>Near {{.NearestLandmark}}</p>{{end}}
+<p>{{.Description}}</p>
+</div>
+</article>
+{{else}}
+<p>These listings are no longer available.</p>
+{{end}}
+</main>
+<footer>Link valid until {{.ExpiresAt.Format "02 Jan 2006"}} &middot; Contact {{.Dealer.ShopName}} for a site visit</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"myapp/constants"
+	"myapp/response"
+	"myapp/services"
+
+	"github.com/gorilla/mux"
+)
+
+// LocalStorageHandler plays the part of the bucket when the local storage backend is configured
+type LocalStorageHandler struct {
+	Storage *services.LocalStorageService
+}
+
+// GetObject serves an object. Like a public bucket, unsigned reads are allowed outside the
+// private prefix; a signed URL is checked whenever one is presented or required.
+func (h *LocalStorageHandler) GetObject(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	if r.URL.Query().Has("X-Signature") || constants.IsPrivateMediaKey(key) {
+		if _, err := h.Storage.VerifySignedRequest(http.MethodGet, key, r.URL.Query()); err != nil {
+			response.WithForbidden(w, r, err.Error())
+			return
+		}
+	}
+
+	file, info, err := h.Storage.OpenObject(key)
+	if err != nil {
+		if errors.Is(err, services.ErrObjectNotFound) || errors.Is(err, services.ErrInvalidKey) {
+			response.WithNotFound(w, r, "Object not found")
+		} else {
+			response.WithInternalError(w, r, "Failed to read object")
+		}
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", info.ContentType)
+	http.ServeContent(w, r, "", info.LastModified, file)
+}
+
+// PutObject accepts an upload to a presigned URL, either a whole object or one multipart part.
+// The request must carry exactly the content type and length that were signed.
+func (h *LocalStorageHandler) PutObject(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	signed, err := h.Storage.VerifySignedRequest(http.MethodPut, key, r.URL.Query())
+	if err != nil {
+		response.WithForbidden(w, r, err.Error())
+		return
+	}
+	if signed.ContentType != "" && r.Header.Get("Content-Type") != signed.ContentType {
+		response.WithForbidden(w, r, "Content-Type does not match the signed upload")
+		return
+	}
+	if r.ContentLength != signed.ContentLength {
+		response.WithForbidden(w, r, "Content-Length does not match the signed upload")
+		return
+	}
+
+	if signed.UploadID != "" {
+		err = h.Storage.WritePart(key, signed.UploadID, signed.PartNumber, r.Body, signed.ContentLength)
+	} else {
+		err = h.Storage.WriteObject(key, signed.ContentType, r.Body, signed.ContentLength)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrObjectNotFound) || errors.Is(err, services.ErrInvalidKey) {
+			response.WithNotFound(w, r, err.Error())
+		} else {
+			response.WithInternalError(w, r, "Failed to store object")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TaskHandler struct {
+	Service *services.TaskService
+}
+
+func NewTaskHandler(service *services.TaskService) *TaskHandler {
+	return &TaskHandler{
+		Service: service,
+	}
+}
+
+func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := h.Service.CreateTask(r.Context(), userID, role, req)
+	if err != nil {
+		writeTaskError(w, r, err, "Failed to create task")
+		return
+	}
+
+	response.WithPayload(w, r, task)
+}
+
+func (h *TaskHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var params models.TaskQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	tasks, err := h.Service.GetTasks(r.Context(), userID, role, params)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch tasks")
+		return
+	}
+
+	response.WithPayload(w, r, tasks)
+}
+
+// GetOverdueTasks lists open tasks past their due time; admins may filter by assignee_id
+func (h *TaskHandler) GetOverdueTasks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var params models.TaskQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	tasks, err := h.Service.GetOverdueTasks(r.Context(), userID, role, params)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch overdue tasks")
+		return
+	}
+
+	response.WithPayload(w, r, tasks)
+}
+
+func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	task, err := h.Service.GetTask(r.Context(), mux.Vars(r)["id"], userID, role)
+	if err != nil {
+		writeTaskError(w, r, err, "Failed to fetch task")
+		return
+	}
+
+	response.WithPayload(w, r, task)
+}
+
+func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := h.Service.UpdateTask(r.Context(), mux.Vars(r)["id"], userID, role, req)
+	if err != nil {
+		writeTaskError(w, r, err, "Failed to update task")
+		return
+	}
+
+	response.WithPayload(w, r, task)
+}
+
+func (h *TaskHandler) SnoozeTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.TaskSnoozeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := h.Service.SnoozeTask(r.Context(), mux.Vars(r)["id"], userID, role, req)
+	if err != nil {
+		writeTaskError(w, r, err, "Failed to snooze task")
+		return
+	}
+
+	response.WithPayload(w, r, task)
+}
+
+func (h *TaskHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.TaskCompleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	task, err := h.Service.CompleteTask(r.Context(), mux.Vars(r)["id"], userID, role, req.Outcome)
+	if err != nil {
+		writeTaskError(w, r, err, "Failed to complete task")
+		return
+	}
+
+	response.WithPayload(w, r, task)
+}
+
+func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	if err := h.Service.DeleteTask(r.Context(), mux.Vars(r)["id"], userID, role); err != nil {
+		writeTaskError(w, r, err, "Failed to delete task")
+		return
+	}
+
+	response.WithMessage(w, r, "Task deleted")
+}
+
+func writeTaskError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTaskInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrTaskNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+		response.WithNotFound(w, r, "Lead, client or property not found")
+	case errors.Is(err, services.ErrTaskNotOwned), errors.Is(err, services.ErrPropertyNotOwned), errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrTaskClosed):
+		response.WithConflict(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
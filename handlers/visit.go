@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"myapp/constants"
+	"myapp/middlewares"
+	"myapp/models"
+	"myapp/response"
+	"myapp/services"
+	"myapp/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type VisitHandler struct {
+	Service *services.VisitService
+	// AppURL is the public base URL calendar feed links point at
+	AppURL string
+}
+
+func NewVisitHandler(service *services.VisitService, appURL string) *VisitHandler {
+	return &VisitHandler{
+		Service: service,
+		AppURL:  appURL,
+	}
+}
+
+func (h *VisitHandler) ScheduleVisit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.VisitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	visit, err := h.Service.ScheduleVisit(r.Context(), userID, role, req)
+	if err != nil {
+		writeVisitError(w, r, err, "Failed to schedule visit")
+		return
+	}
+
+	response.WithPayload(w, r, visit)
+}
+
+func (h *VisitHandler) GetVisits(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var params models.VisitQueryParams
+	if err := utils.ParseQueryParams(r, &params); err != nil {
+		response.WithError(w, r, "Invalid query parameters")
+		return
+	}
+
+	visits, err := h.Service.GetVisits(r.Context(), userID, role, params)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to fetch visits")
+		return
+	}
+
+	response.WithPayload(w, r, visits)
+}
+
+func (h *VisitHandler) GetVisit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	visit, err := h.Service.GetVisit(r.Context(), mux.Vars(r)["id"], userID, role)
+	if err != nil {
+		writeVisitError(w, r, err, "Failed to fetch visit")
+		return
+	}
+
+	response.WithPayload(w, r, visit)
+}
+
+func (h *VisitHandler) RescheduleVisit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req models.VisitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	visit, err := h.Service.RescheduleVisit(r.Context(), mux.Vars(r)["id"], userID, role, req)
+	if err != nil {
+		writeVisitError(w, r, err, "Failed to reschedule visit")
+		return
+	}
+
+	response.WithPayload(w, r, visit)
+}
+
+func (h *VisitHandler) CancelVisit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	visit, err := h.Service.CancelVisit(r.Context(), mux.Vars(r)["id"], userID, role, req.Reason)
+	if err != nil {
+		writeVisitError(w, r, err, "Failed to cancel visit")
+		return
+	}
+
+	response.WithPayload(w, r, visit)
+}
+
+func (h *VisitHandler) CompleteVisit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var req struct {
+		OutcomeNote string `json:"outcome_note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WithError(w, r, "Invalid request body")
+		return
+	}
+
+	visit, err := h.Service.CompleteVisit(r.Context(), mux.Vars(r)["id"], userID, role, req.OutcomeNote)
+	if err != nil {
+		writeVisitError(w, r, err, "Failed to complete visit")
+		return
+	}
+
+	response.WithPayload(w, r, visit)
+}
+
+// GetCalendarFeedURL returns the dealer's private ICS subscription link for Google Calendar, Outlook or iOS
+func (h *VisitHandler) GetCalendarFeedURL(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+	if role != constants.Dealer {
+		response.WithForbidden(w, r, "Calendar feeds are only available to dealers")
+		return
+	}
+
+	token, err := h.Service.CalendarFeedToken(r.Context(), dealerID)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to load calendar feed")
+		return
+	}
+	response.WithPayload(w, r, map[string]string{
+		"url": h.AppURL + "/calendar/" + token + "/visits.ics",
+	})
+}
+
+// RotateCalendarFeedURL replaces the dealer's subscription link, revoking the old one
+func (h *VisitHandler) RotateCalendarFeedURL(w http.ResponseWriter, r *http.Request) {
+	dealerID, _ := r.Context().Value(middlewares.UserIDKey).(string)
+	role, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+	if role != constants.Dealer {
+		response.WithForbidden(w, r, "Calendar feeds are only available to dealers")
+		return
+	}
+
+	token, err := h.Service.RotateCalendarFeed(r.Context(), dealerID)
+	if err != nil {
+		response.WithInternalError(w, r, "Failed to rotate calendar feed")
+		return
+	}
+	response.WithPayload(w, r, map[string]string{
+		"url": h.AppURL + "/calendar/" + token + "/visits.ics",
+	})
+}
+
+// GetCalendarFeed serves the ICS feed; calendar apps cannot send a JWT, so the signed token authorises it
+func (h *VisitHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.Service.CalendarFeed(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		if errors.Is(err, services.ErrCalendarFeedInvalid) {
+			response.WithNotFound(w, r, err.Error())
+		} else {
+			response.WithInternalError(w, r, "Failed to build calendar")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="visits.ics"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(feed)
+}
+
+func writeVisitError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	var conflict *services.VisitConflictError
+	switch {
+	case errors.As(err, &conflict):
+		response.WithConflictPayload(w, r, err.Error(), conflict.Conflicts)
+	case errors.Is(err, services.ErrVisitInvalid):
+		response.WithValidationError(w, r, err.Error())
+	case errors.Is(err, services.ErrVisitNotFound):
+		response.WithNotFound(w, r, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+		response.WithNotFound(w, r, "Property or client not found")
+	case errors.Is(err, services.ErrVisitNotOwned), errors.Is(err, services.ErrPropertyNotOwned), errors.Is(err, services.ErrDealerClientNotOwned):
+		response.WithForbidden(w, r, err.Error())
+	case errors.Is(err, services.ErrVisitClosed):
+		response.WithConflict(w, r, err.Error())
+	default:
+		response.WithInternalError(w, r, fallback)
+	}
+}
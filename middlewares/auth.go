@@ -16,6 +16,17 @@ const (
 	UserRoleKey contextKey = "userRole"
 )
 
+// AccountCheck reports whether a signed-in user may still use the API. Tokens outlive the
+// accounts they were issued to, so JWTAuth rejects tokens of accounts it reports inactive.
+type AccountCheck func(ctx context.Context, userID string, role string) bool
+
+var accountCheck AccountCheck
+
+// SetAccountCheck installs the check JWTAuth and OptionalJWTAuth apply to every token
+func SetAccountCheck(check AccountCheck) {
+	accountCheck = check
+}
+
 func JWTAuth(jwtSecret string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -38,6 +49,10 @@ func JWTAuth(jwtSecret string) func(http.Handler) http.Handler {
 
 			userID, _ := claims["id"].(string)
 			role, _ := claims["role"].(string)
+			if accountCheck != nil && !accountCheck(r.Context(), userID, role) {
+				http.Error(w, "Account is disabled", http.StatusUnauthorized)
+				return
+			}
 
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
 			ctx = context.WithValue(ctx, UserRoleKey, role)
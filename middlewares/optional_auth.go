@@ -33,6 +33,11 @@ func OptionalJWTAuth(jwtSecret string) func(http.Handler) http.Handler {
 
 			userID, _ := claims["id"].(string)
 			role, _ := claims["role"].(string)
+			if accountCheck != nil && !accountCheck(r.Context(), userID, role) {
+				// Disabled accounts are treated as anonymous, like invalid tokens
+				next.ServeHTTP(w, r)
+				return
+			}
 
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
 			ctx = context.WithValue(ctx, UserRoleKey, role)
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// StoredAadhar is a lead's Aadhaar as kept in the database. The number is encrypted with the key
+// of KeyVersion, with its last four digits kept for masking and a keyed hash kept for lookups.
+// KeyVersion 0 means the number was saved before encryption and Ciphertext is in plain text.
+// HashKeyID identifies the lookup key the hash was made with.
+type StoredAadhar struct {
+	Ciphertext string
+	KeyVersion int
+	Last4      string
+	Hash       string
+	HashKeyID  string
+	Photo      string
+}
+
+// AadharRevealRequest asks for a lead's full Aadhaar details; the reason goes in the audit log
+type AadharRevealRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AadharReveal is a lead's full Aadhaar number and photo, returned only by the reveal endpoint
+type AadharReveal struct {
+	LeadID       string    `json:"lead_id"`
+	AadharNumber string    `json:"aadhar_number"`
+	AadharPhoto  string    `json:"aadhar_photo,omitempty"`
+	RevealedAt   time.Time `json:"revealed_at"`
+}
+
+// AadharAccessLog records one reveal of a lead's Aadhaar details
+type AadharAccessLog struct {
+	ID         string    `json:"id"`
+	LeadID     string    `json:"lead_id"`
+	RevealedBy string    `json:"revealed_by"`
+	Reason     string    `json:"reason,omitempty"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	RevealedAt time.Time `json:"revealed_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// What an activity is recorded against
+const (
+	ActivitySubjectLead         = "lead"
+	ActivitySubjectDealerClient = "dealer_client"
+)
+
+// Activity types. Calls, WhatsApp messages, visits and notes are logged by people; the rest are
+// recorded by the app as the lead or client changes.
+const (
+	ActivityCall          = "call"
+	ActivityWhatsApp      = "whatsapp"
+	ActivityVisit         = "visit"
+	ActivityNote          = "note"
+	ActivityCreated       = "created"
+	ActivityPropertyAdded = "property_added"
+	ActivityStatusChange  = "status_change"
+	ActivityDocumentAdded = "document_added"
+	ActivityMerged        = "merged"
+)
+
+// ActivityLoggedTypes are the activity types users can log by hand
+var ActivityLoggedTypes = []string{ActivityCall, ActivityWhatsApp, ActivityVisit, ActivityNote}
+
+// Activity is one entry in the timeline of a lead or dealer client. Entries are never edited or
+// deleted. OccurredAt is when the call or visit happened, which can be before it was logged.
+type Activity struct {
+	ID          string    `json:"id"`
+	SubjectType string    `json:"subject_type"`
+	SubjectID   string    `json:"subject_id"`
+	Type        string    `json:"type"`
+	Body        string    `json:"body"`
+	PropertyID  string    `json:"property_id,omitempty"`
+	AuthorID    string    `json:"author_id,omitempty"`
+	System      bool      `json:"system"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ActivityRequest logs a call, WhatsApp message, visit or note. OccurredAt defaults to now.
+type ActivityRequest struct {
+	Type       string     `json:"type"`
+	Body       string     `json:"body"`
+	PropertyID string     `json:"property_id"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
+
+// ActivityQueryParams filters a timeline; type takes a comma-separated list. The subject always
+// comes from the URL.
+type ActivityQueryParams struct {
+	SubjectType *string `query:"subject_type" mongo:"subject_type"`
+	SubjectID   *string `query:"subject_id" mongo:"subject_id" convert:"objectid"`
+	Type        *string `query:"type" mongo:"type" operator:"$in" convert:"csv"`
+	PropertyID  *string `query:"property_id" mongo:"property_id" convert:"objectid"`
+	System      *bool   `query:"system" mongo:"system"`
+	BaseQueryParams
+}
+
+func (a *ActivityQueryParams) SetDefaults() {
+	if a.Sort == nil || *a.Sort == "" {
+		a.Sort = &[]string{"occurred_at"}[0]
+	}
+	if a.Order == nil || *a.Order == "" {
+		a.Order = &[]string{"desc"}[0]
+	}
+	a.BaseQueryParams.SetDefaults()
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// CoBrokeTerms opts a listing into the co-broking pool. CommissionSplit is the percentage of
+// the commission offered to the dealer who brings the buyer.
+type CoBrokeTerms struct {
+	Enabled         bool      `json:"enabled"`
+	CommissionSplit float64   `json:"commission_split"`
+	Terms           string    `json:"terms,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+const (
+	CoBrokeStatusPending    = "pending"
+	CoBrokeStatusAccepted   = "accepted"
+	CoBrokeStatusDeclined   = "declined"
+	CoBrokeStatusWithdrawn  = "withdrawn"
+	CoBrokeStatusCompleted  = "completed"
+	CoBrokeStatusTerminated = "terminated"
+)
+
+// Sides of an agreement a dealer can list by
+const (
+	CoBrokeSideListing = "listing"
+	CoBrokeSidePartner = "partner"
+)
+
+// OpenCoBrokeStatuses are the statuses in which a dealer may not raise another request for the same listing
+var OpenCoBrokeStatuses = []string{CoBrokeStatusPending, CoBrokeStatusAccepted}
+
+// CoBrokeAgreement is a partner dealer's collaboration on another dealer's listing, from request
+// to close. The split and terms are copied from the listing when the request is made.
+type CoBrokeAgreement struct {
+	ID              string     `json:"id"`
+	PropertyID      string     `json:"property_id"`
+	PropertyNumber  int64      `json:"property_number"`
+	PropertyTitle   string     `json:"property_title"`
+	ListingDealerID string     `json:"listing_dealer_id"`
+	PartnerDealerID string     `json:"partner_dealer_id"`
+	CommissionSplit float64    `json:"commission_split"`
+	Terms           string     `json:"terms,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	Status          string     `json:"status"`
+	ResponseNote    string     `json:"response_note,omitempty"`
+	RespondedAt     *time.Time `json:"responded_at,omitempty"`
+	ClosingNote     string     `json:"closing_note,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CoBrokeListing is a pooled listing as other dealers see it: no owner details or exact address
+type CoBrokeListing struct {
+	PropertyID      string         `json:"property_id"`
+	CommissionSplit float64        `json:"commission_split"`
+	Terms           string         `json:"terms,omitempty"`
+	Dealer          SharedDealer   `json:"dealer"`
+	Property        SharedProperty `json:"property"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // models/common.go
 type BaseQueryParams struct {
     Page    *int          `query:"page"`
@@ -33,4 +35,20 @@ func (b *BaseQueryParams) SetDefaults() {
 	}
 
 	
-}
\ No newline at end of file
+}
+
+// AddArrayFilter applies the named array parameter whether or not the client listed it in
+// array_filters, for filters that scope what the caller may see
+func (b *BaseQueryParams) AddArrayFilter(name string) {
+	if b.ArrayFilters == nil || strings.TrimSpace(*b.ArrayFilters) == "" {
+		b.ArrayFilters = &name
+		return
+	}
+	for _, existing := range strings.Split(*b.ArrayFilters, ",") {
+		if strings.TrimSpace(existing) == name {
+			return
+		}
+	}
+	filters := *b.ArrayFilters + "," + name
+	b.ArrayFilters = &filters
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// What a contact's history entry is about
+const (
+	ContactEntityLead         = "lead"
+	ContactEntityInquiry      = "inquiry"
+	ContactEntityDealerClient = "dealer_client"
+	ContactEntityVisit        = "visit"
+	ContactEntityTask         = "task"
+	ContactEntityLeadMerge    = "lead_merge"
+)
+
+// Contact is one person as known across leads, inquiries and dealer clients. Records are linked
+// by their contact key, the normalised phone number; there is no separate contact document.
+type Contact struct {
+	Key           string         `json:"key"`
+	Names         []string       `json:"names"`
+	Leads         []Lead         `json:"leads"`
+	Inquiries     []Inquiry      `json:"inquiries"`
+	DealerClients []DealerClient `json:"dealer_clients"`
+	Visits        []Visit        `json:"visits"`
+	Tasks         []Task         `json:"tasks"`
+	Merges        []LeadMerge    `json:"merges"`
+	// Everything above as one timeline, newest first
+	History []ContactEvent `json:"history"`
+}
+
+type ContactEvent struct {
+	At         time.Time `json:"at"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	DealerID   string    `json:"dealer_id,omitempty"`
+	Summary    string    `json:"summary"`
+}
+
+// ContactSummary counts a person's records; the duplicates list is made of these
+type ContactSummary struct {
+	Key           string   `json:"key"`
+	Names         []string `json:"names"`
+	Leads         int64    `json:"leads"`
+	Inquiries     int64    `json:"inquiries"`
+	DealerClients int64    `json:"dealer_clients"`
+}
+
+// LeadMerge records a duplicate lead folded into LeadID. The duplicate's own fields are kept
+// so the merge can be understood after the duplicate is gone.
+type LeadMerge struct {
+	ID                string `json:"id"`
+	LeadID            string `json:"lead_id"`
+	MergedLeadID      string `json:"merged_lead_id"`
+	MergedName        string `json:"merged_name"`
+	MergedPhone       string `json:"merged_phone"`
+	MergedRequirement string `json:"merged_requirement,omitempty"`
+	// Interests the duplicate had that the lead did not, and ones both had that were combined
+	InterestsAdded    int       `json:"interests_added"`
+	InterestsCombined int       `json:"interests_combined"`
+	MergedBy          string    `json:"merged_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type LeadMergeRequest struct {
+	DuplicateLeadID string `json:"duplicate_lead_id"`
+	// Leads are only merged when they share a contact key unless this is set
+	AllowDifferentContact bool `json:"allow_different_contact"`
+}
+
+// LeadMergeResult is the surviving lead after a merge and the record of the merge
+type LeadMergeResult struct {
+	Lead  Lead      `json:"lead"`
+	Merge LeadMerge `json:"merge"`
+}
@@ -13,10 +13,23 @@ type Dealer struct {
 	ShopName      string    `json:"shop_name"`
 	Location      string    `json:"location"`
 	SubLocation   string    `json:"sub_location"`
+	Watermark     *WatermarkSettings `json:"watermark,omitempty"`
+	// Set once the dealer is offboarded; their records now belong to the successor
+	DeactivatedAt     *time.Time `json:"deactivated_at,omitempty"`
+	SuccessorDealerID string     `json:"successor_dealer_id,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// WatermarkSettings controls the branding stamped on a dealer's public photo variants
+type WatermarkSettings struct {
+	Enabled  bool    `json:"enabled"`
+	Type     string  `json:"type"`
+	LogoKey  string  `json:"logo_key,omitempty"`
+	Position string  `json:"position"`
+	Opacity  float64 `json:"opacity"`
+}
+
 type LocationWithSubLocations struct {
 	Location    string   `json:"location"`
 	SubLocation []string `json:"sub_location"`
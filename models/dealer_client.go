@@ -3,6 +3,7 @@ package models
 import "time"
 
 type Document struct {
+	Key  string `json:"key,omitempty"`
 	URL  string `json:"url"`
 	Type string `json:"type"`
 	Size int64  `json:"size"`
@@ -13,8 +14,12 @@ type DealerClient struct {
 	DealerID          string                         `json:"dealer_id"`
 	Name              string                         `json:"name"`
 	Phone             string                         `json:"phone"`
+	ContactKey        string                         `json:"contact_key,omitempty"`
 	Note              string                         `json:"note"`
+	Criteria          *RequirementCriteria           `json:"criteria,omitempty"`
 	Docs              []Document                     `json:"docs"`
+	// The inquiry the client was converted from, if any
+	SourceInquiryID   string                         `json:"source_inquiry_id,omitempty"`
 	CreatedAt         time.Time                      `json:"created_at"`
 	UpdatedAt         time.Time                      `json:"updated_at"`
 	PropertyInterests []DealerClientPropertyInterest `json:"properties"`
@@ -31,10 +36,11 @@ type DealerClientPropertyInterest struct {
 }
 
 type DealerClientUpdate struct {
-	Name  *string     `json:"name,omitempty"`
-	Phone *string     `json:"phone,omitempty"`
-	Note  *string     `json:"note,omitempty"`
-	Docs  *[]Document `json:"docs,omitempty"`
+	Name     *string              `json:"name,omitempty"`
+	Phone    *string              `json:"phone,omitempty"`
+	Note     *string              `json:"note,omitempty"`
+	Criteria *RequirementCriteria `json:"criteria,omitempty"`
+	Docs     *[]Document          `json:"docs,omitempty"`
 }
 
 type DealerClientPropertyInterestUpdate struct {
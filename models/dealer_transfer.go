@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// DealerTransfer records a dealer's offboarding: everything they held moved to the successor
+// in one transaction. The counts are what was moved.
+type DealerTransfer struct {
+	ID            string `json:"id"`
+	FromDealerID  string `json:"from_dealer_id"`
+	ToDealerID    string `json:"to_dealer_id"`
+	Reason        string `json:"reason,omitempty"`
+	PerformedBy   string `json:"performed_by"`
+	Properties    int64  `json:"properties"`
+	DealerClients int64  `json:"dealer_clients"`
+	Inquiries     int64  `json:"inquiries"`
+	SavedSearches int64  `json:"saved_searches"`
+	// Leads with at least one property interest re-pointed to the successor
+	Leads int64 `json:"leads"`
+	// Leads the departing dealer owned, now assigned to the successor
+	AssignedLeads int64 `json:"assigned_leads"`
+	// Open follow-up tasks assigned to the departing dealer, now assigned to the successor
+	Tasks int64 `json:"tasks"`
+	// Site visits booked on the departing dealer's calendar
+	Visits int64 `json:"visits"`
+	// Co-broke agreements the departing dealer was either side of
+	CoBrokeAgreements int64 `json:"co_broke_agreements"`
+	// The departing dealer's share links are revoked, as they carry the departing dealer's branding
+	RevokedShareLinks int64     `json:"revoked_share_links"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type DealerOffboardRequest struct {
+	SuccessorDealerID string `json:"successor_dealer_id"`
+	Reason            string `json:"reason"`
+}
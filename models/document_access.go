@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DocumentAccessLog records one download link issued for a client document
+type DocumentAccessLog struct {
+	ID         string    `json:"id"`
+	DealerID   string    `json:"dealer_id"`
+	ClientID   string    `json:"client_id"`
+	Key        string    `json:"key"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	AccessedAt time.Time `json:"accessed_at"`
+}
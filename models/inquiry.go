@@ -2,24 +2,39 @@ package models
 
 import "time"
 
+// What an inquiry was converted into
+const (
+	InquiryConvertedToLead         = "lead"
+	InquiryConvertedToDealerClient = "dealer_client"
+)
+
 type Inquiry struct {
-	ID          string    `json:"id"`
-	DealerID    *string   `json:"dealer_id,omitempty"`
-	Source      string    `json:"source"`
-	Name        string    `json:"name"`
-	Phone       string    `json:"phone"`
-	Requirement string    `json:"requirement"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string               `json:"id"`
+	DealerID    *string              `json:"dealer_id,omitempty"`
+	Source      string               `json:"source"`
+	Name        string               `json:"name"`
+	Phone       string               `json:"phone"`
+	ContactKey  string               `json:"contact_key,omitempty"`
+	Requirement string               `json:"requirement"`
+	Criteria    *RequirementCriteria `json:"criteria,omitempty"`
+	// Set once the inquiry has been turned into a lead or a dealer client
+	Converted   bool       `json:"converted"`
+	ConvertedTo string     `json:"converted_to,omitempty"`
+	ConvertedID string     `json:"converted_id,omitempty"`
+	ConvertedBy string     `json:"converted_by,omitempty"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 type InquiryUpdate struct {
-	DealerID    *string    `json:"dealer_id,omitempty"`
-	Source      *string    `json:"source,omitempty"`
-	Name        *string    `json:"name,omitempty"`
-	Phone       *string    `json:"phone,omitempty"`
-	Requirement *string    `json:"requirement,omitempty"`
-	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DealerID    *string              `json:"dealer_id,omitempty"`
+	Source      *string              `json:"source,omitempty"`
+	Name        *string              `json:"name,omitempty"`
+	Phone       *string              `json:"phone,omitempty"`
+	Requirement *string              `json:"requirement,omitempty"`
+	Criteria    *RequirementCriteria `json:"criteria,omitempty"`
+	UpdatedAt   *time.Time           `json:"updated_at,omitempty"`
 }
 
 type InquiryQueryParams struct {
@@ -29,9 +44,16 @@ type InquiryQueryParams struct {
 	Name        *string `query:"name"`
 	Phone       *string `query:"phone"`
 	Requirement *string `query:"requirement"`
+	Converted   *bool   `query:"converted" mongo:"converted"`
 	BaseQueryParams
 }
 
+// InquiryConvertRequest picks what to convert an inquiry into. It defaults to a dealer client of
+// the inquiry's dealer when it came from one, and to a lead otherwise.
+type InquiryConvertRequest struct {
+	ConvertTo string `json:"convert_to"`
+}
+
 func (i *InquiryQueryParams) SetDefaults() {
 	// Call parent defaults
 	i.BaseQueryParams.SetDefaults()
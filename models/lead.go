@@ -3,15 +3,27 @@ package models
 import "time"
 
 type Lead struct {
-	ID           string                 `json:"id"`
-	Name         string                 `json:"name"`
-	Phone        string                 `json:"phone"`
-	Requirement  string                 `json:"requirement"`
-	AadharNumber string                 `json:"aadhar_number"`
-	AadharPhoto  string                 `json:"aadhar_photo"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
-	Properties   []PropertyInterest `json:"properties,omitempty"`
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Phone        string               `json:"phone"`
+	// The normalised phone that links the lead to the same person's inquiries and dealer clients
+	ContactKey   string               `json:"contact_key,omitempty"`
+	Requirement  string               `json:"requirement"`
+	Criteria     *RequirementCriteria `json:"criteria,omitempty"`
+	// Sent in full when creating or updating a lead; every response masks it as XXXX-XXXX-1234
+	AadharNumber string               `json:"aadhar_number"`
+	// Accepted on input but only given out by the admin reveal endpoint
+	AadharPhoto    string `json:"aadhar_photo,omitempty"`
+	HasAadharPhoto bool   `json:"has_aadhar_photo"`
+	StoredAadhar   StoredAadhar `json:"-"`
+	// The dealer who owns the lead, set by the routing rules or an admin
+	AssignedDealerID string     `json:"assigned_dealer_id,omitempty"`
+	AssignedAt       *time.Time `json:"assigned_at,omitempty"`
+	// The inquiry the lead was converted from, if any
+	SourceInquiryID string `json:"source_inquiry_id,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	Properties   []PropertyInterest   `json:"properties,omitempty"`
 }
 
 
@@ -23,31 +35,85 @@ type PropertyInterest struct {
 	DealerID   string    `json:"dealer_id"`
 	Status     string    `json:"status"`
 	Note       string    `json:"note"`
+	// Set on conversion and loss respectively
+	SoldPrice       int64                          `json:"sold_price,omitempty"`
+	LostReason      string                         `json:"lost_reason,omitempty"`
+	StatusChangedAt *time.Time                     `json:"status_changed_at,omitempty"`
+	History         []PropertyInterestStatusChange `json:"history,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// PropertyInterestStatusChange is one step of a property interest through the lead pipeline
+type PropertyInterestStatusChange struct {
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Note      string    `json:"note,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	SoldPrice int64     `json:"sold_price,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type PropertyInterestStatusUpdate struct {
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+	Reason    string `json:"reason"`
+	SoldPrice int64  `json:"sold_price"`
+}
+
 // models/lead.go
 type LeadQueryParams struct {
 	ID          *string    `query:"id" mongo:"_id" convert:"objectid"`
 	Name        *string    `query:"name" mongo:"name"`
 	Phone       *string    `query:"phone" mongo:"phone"`
-	AadharNumber *string   `query:"aadhar_number" mongo:"aadhar_number"`
+	// Matched through the keyed hash, as the number itself is encrypted
+	AadharNumber *string   `query:"aadhar_number" mongo:"aadhar_hash"`
 	DealerID    *string    `query:"dealer_id" mongo:"properties.dealer_id" convert:"objectid" array:"properties"`
+	AssignedDealerID *string `query:"assigned_dealer_id" mongo:"assigned_dealer_id" convert:"objectid"`
 	PropertyID  *string    `query:"property_id" mongo:"properties.property_id" convert:"objectid" array:"properties"`
 	PropertyNumber *int64  `query:"property_number" mongo:"properties.property_number" convert:"int64" array:"properties"`
-	Status      *string    `query:"status" mongo:"status"`
+	Status      *string    `query:"status" mongo:"properties.status" operator:"$in" convert:"csv" array:"properties"`
+	StatusChangedFrom *string `query:"status_changed_from" mongo:"properties.status_changed_at" operator:"$gte" convert:"date" array:"properties"`
+	StatusChangedTo   *string `query:"status_changed_to" mongo:"properties.status_changed_at" operator:"$lte" convert:"date" array:"properties"`
 	CreatedAt   *time.Time `query:"created_at" mongo:"created_at" convert:"date"`
 	UpdatedAt   *time.Time `query:"updated_at" mongo:"updated_at" convert:"date"`
 	BaseQueryParams
 }
 
 
+// The lead pipeline. Every property interest starts at view and moves along
+// LeadStatusTransitions; converted is final and lost can be reopened.
 const (
-	LeadStatusView         = "view"
-	LeadStatusInterested   = "interested"
-	LeadStatusBooked       = "booked"
-	LeadStatusCancelled    = "cancelled"
-	LeadStatusFailed       = "failed"
-	LeadStatusUninterested = "uninterested"
+	LeadStatusView       = "view"
+	LeadStatusInterested = "interested"
+	LeadStatusOngoing    = "ongoing"
+	LeadStatusBooked     = "booked"
+	LeadStatusConverted  = "converted"
+	LeadStatusLost       = "lost"
+	// Written before the pipeline existed; treated as lost
+	LeadStatusClosed = "closed"
 )
+
+// LeadStatusTransitions lists the statuses each status may move to
+var LeadStatusTransitions = map[string][]string{
+	LeadStatusView:       {LeadStatusInterested, LeadStatusOngoing, LeadStatusLost},
+	LeadStatusInterested: {LeadStatusOngoing, LeadStatusBooked, LeadStatusConverted, LeadStatusLost},
+	LeadStatusOngoing:    {LeadStatusBooked, LeadStatusConverted, LeadStatusLost},
+	LeadStatusBooked:     {LeadStatusConverted, LeadStatusLost},
+	LeadStatusLost:       {LeadStatusInterested, LeadStatusOngoing},
+	LeadStatusClosed:     {LeadStatusInterested, LeadStatusOngoing},
+	LeadStatusConverted:  {},
+}
+
+// OpenLeadStatuses are the statuses of interests still being worked
+var OpenLeadStatuses = []string{LeadStatusView, LeadStatusInterested, LeadStatusOngoing, LeadStatusBooked}
+
+func CanTransitionLeadStatus(from string, to string) bool {
+	for _, next := range LeadStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// How a routing rule picks among its dealers
+const (
+	LeadRoutingRoundRobin  = "round_robin"
+	LeadRoutingLeastLoaded = "least_loaded"
+)
+
+// How a lead came to be assigned
+const (
+	LeadAssignmentRule     = "rule"
+	LeadAssignmentManual   = "manual"
+	LeadAssignmentTransfer = "transfer"
+)
+
+// LeadRoutingRule assigns new leads that meet its conditions to one of its dealers. Active rules
+// are tried by ascending priority and the first that can place the lead wins. Conditions read
+// the lead's structured criteria; a condition left empty matches any lead.
+type LeadRoutingRule struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Priority     int      `json:"priority"`
+	Active       bool     `json:"active"`
+	Locations    []string `json:"locations,omitempty"`
+	SubLocations []string `json:"sub_locations,omitempty"`
+	// Budget band the lead's budget must fall in; zero leaves that end open
+	MinBudget int64 `json:"min_budget,omitempty"`
+	MaxBudget int64 `json:"max_budget,omitempty"`
+	// Dealers the rule assigns to. When empty, the dealers based in the lead's preferred
+	// sub-locations are used, or failing that those in its preferred locations.
+	DealerIDs []string `json:"dealer_ids,omitempty"`
+	Strategy  string   `json:"strategy"`
+	// Most leads a dealer may own before the rule passes them over; zero means no limit
+	DealerCapacity int       `json:"dealer_capacity,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type LeadRoutingRuleRequest struct {
+	Name           string   `json:"name"`
+	Priority       int      `json:"priority"`
+	Active         *bool    `json:"active"`
+	Locations      []string `json:"locations"`
+	SubLocations   []string `json:"sub_locations"`
+	MinBudget      int64    `json:"min_budget"`
+	MaxBudget      int64    `json:"max_budget"`
+	DealerIDs      []string `json:"dealer_ids"`
+	Strategy       string   `json:"strategy"`
+	DealerCapacity int      `json:"dealer_capacity"`
+}
+
+// LeadAssignment is one change of a lead's owner. An empty ToDealerID means the lead was
+// unassigned.
+type LeadAssignment struct {
+	ID           string    `json:"id"`
+	LeadID       string    `json:"lead_id"`
+	FromDealerID string    `json:"from_dealer_id,omitempty"`
+	ToDealerID   string    `json:"to_dealer_id,omitempty"`
+	Method       string    `json:"method"`
+	RuleID       string    `json:"rule_id,omitempty"`
+	AssignedBy   string    `json:"assigned_by,omitempty"`
+	Note         string    `json:"note,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type LeadAssignRequest struct {
+	DealerID string `json:"dealer_id"`
+	Note     string `json:"note"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Media is an object in storage, reserved at presign time and confirmed once uploaded
+type Media struct {
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	OwnerID     string    `json:"owner_id"`
+	Purpose     string    `json:"purpose"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Status      string    `json:"status"`
+	UploadID    string    `json:"upload_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const (
+	MediaStatusPending   = "pending"
+	MediaStatusConfirmed = "confirmed"
+)
+
+// MediaUsage is how much one owner stores for one purpose
+type MediaUsage struct {
+	OwnerID string `json:"owner_id"`
+	Purpose string `json:"purpose"`
+	Count   int64  `json:"count"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// MediaReference is a record that points at a stored object
+type MediaReference struct {
+	Key        string `json:"key"`
+	Collection string `json:"collection"`
+	RecordID   string `json:"record_id"`
+	Field      string `json:"field"`
+}
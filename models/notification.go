@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	NotificationSavedSearchMatch = "saved_search_match"
+	NotificationLeadAssigned     = "lead_assigned"
+	NotificationTaskReminder     = "task_reminder"
+)
+
+// Notification is an entry in a user's in-app inbox. RecipientID is the user ID from the JWT,
+// so it is not always an ObjectID. Data carries IDs the client needs to open the subject.
+type Notification struct {
+	ID          string            `json:"id"`
+	RecipientID string            `json:"recipient_id"`
+	Type        string            `json:"type"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Data        map[string]string `json:"data,omitempty"`
+	ReadAt      *time.Time        `json:"read_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type NotificationQueryParams struct {
+	Unread *bool `query:"unread"`
+	BaseQueryParams
+}
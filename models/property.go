@@ -1,34 +1,81 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
 
 type Property struct {
-	ID              string    `json:"id"`
-	PropertyNumber  int64     `json:"property_number"`
-	DealerID        string    `json:"dealer_id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	Address         string    `json:"address"`
-	MinPrice        int64     `json:"min_price"`
-	MaxPrice        int64     `json:"max_price"`
-	Photos          []string  `json:"photos"`
-	Videos          []string  `json:"videos"`
-	OwnerName       string    `json:"owner_name"`
-	OwnerPhone      string    `json:"owner_phone"`
-	NearestLandmark string    `json:"nearest_landmark"`
-	IsDeleted       bool      `json:"is_deleted"`
-	Sold            bool      `json:"sold"`
-	SoldPrice       int64     `json:"sold_price"`
-	SoldDate        time.Time `json:"sold_date"`
-	Area            float64   `json:"area"`
-	Bedrooms        int       `json:"bedrooms"`
-	Bathrooms       int       `json:"bathrooms"`
-	PropertyType    string    `json:"property_type"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              string        `json:"id"`
+	PropertyNumber  int64         `json:"property_number"`
+	DealerID        string        `json:"dealer_id"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	Address         string        `json:"address"`
+	MinPrice        int64         `json:"min_price"`
+	MaxPrice        int64         `json:"max_price"`
+	Photos          []Photo       `json:"photos"`
+	Videos          []string      `json:"videos"`
+	OwnerName       string        `json:"owner_name"`
+	OwnerPhone      string        `json:"owner_phone"`
+	NearestLandmark string        `json:"nearest_landmark"`
+	IsDeleted       bool          `json:"is_deleted"`
+	Sold            bool          `json:"sold"`
+	SoldPrice       int64         `json:"sold_price"`
+	SoldDate        time.Time     `json:"sold_date"`
+	Area            float64       `json:"area"`
+	Bedrooms        int           `json:"bedrooms"`
+	Bathrooms       int           `json:"bathrooms"`
+	PropertyType    string        `json:"property_type"`
+	Furnishing      string        `json:"furnishing"`
+	ListingType     string        `json:"listing_type,omitempty"`
+	Floor           int           `json:"floor"`
+	TotalFloors     int           `json:"total_floors"`
+	Facing          string        `json:"facing"`
+	Parking         int           `json:"parking"`
+	PropertyAge     int           `json:"property_age"`
+	Amenities       []string      `json:"amenities"`
+	CoBroke         *CoBrokeTerms `json:"co_broke,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
 
+// Photo is an uploaded listing photo with its processed size variants
+type Photo struct {
+	Key      string            `json:"key,omitempty"`
+	URL      string            `json:"url"`
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+// UnmarshalJSON also accepts a plain string: an object key from a presigned upload,
+// or a full URL sent by older clients
+func (p *Photo) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+			*p = Photo{URL: value}
+		} else {
+			*p = Photo{Key: value}
+		}
+		return nil
+	}
+
+	type photoAlias Photo
+	var alias photoAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*p = Photo(alias)
+	return nil
+}
+
+const (
+	PhotoVariantThumbnail = "thumbnail"
+	PhotoVariantMedium    = "medium"
+	PhotoVariantLarge     = "large"
+)
 
 type PropertyUpdate struct {
 	Title           *string    `json:"title,omitempty"`
@@ -38,7 +85,7 @@ type PropertyUpdate struct {
 	MinPrice        *int64     `json:"min_price,omitempty"`
 	MaxPrice        *int64     `json:"max_price,omitempty"`
 	Description     *string    `json:"description,omitempty"`
-	Photos          *[]string  `json:"photos,omitempty"`
+	Photos          *[]Photo   `json:"photos,omitempty"`
 	Videos          *[]string  `json:"videos,omitempty"`
 	OwnerName       *string    `json:"owner_name,omitempty"`
 	OwnerPhone      *string    `json:"owner_phone,omitempty"`
@@ -50,6 +97,14 @@ type PropertyUpdate struct {
 	Bedrooms        *int       `json:"bedrooms,omitempty"`
 	Bathrooms       *int       `json:"bathrooms,omitempty"`
 	PropertyType    *string    `json:"property_type,omitempty"`
+	Furnishing      *string    `json:"furnishing,omitempty"`
+	ListingType     *string    `json:"listing_type,omitempty"`
+	Floor           *int       `json:"floor,omitempty"`
+	TotalFloors     *int       `json:"total_floors,omitempty"`
+	Facing          *string    `json:"facing,omitempty"`
+	Parking         *int       `json:"parking,omitempty"`
+	PropertyAge     *int       `json:"property_age,omitempty"`
+	Amenities       *[]string  `json:"amenities,omitempty"`
 	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
 }
 
@@ -71,6 +126,14 @@ type PropertyQueryParams struct {
     Bathrooms       *int     `query:"bathrooms"`
     MinPrice        *float64 `query:"min_price"`
     MaxPrice        *float64 `query:"max_price"`
+    Furnishing      *string  `query:"furnishing"`
+    ListingType     *string  `query:"listing_type"`
+    Floor           *int     `query:"floor"`
+    TotalFloors     *int     `query:"total_floors"`
+    Facing          *string  `query:"facing"`
+    Parking         *int     `query:"parking"`
+    PropertyAge     *int     `query:"property_age"`
+    Amenities       *string  `query:"amenities[all]" mongo:"amenities" operator:"$all" convert:"csv"`
 	BaseQueryParams
 }
 
@@ -0,0 +1,55 @@
+package models
+
+// Engagement events counted per property per day
+const (
+	// A share page showing the listing was opened; share pages are the only public surface
+	PropertyEventPublicView = "public_view"
+	// An admin or another dealer opened the listing in the app; the owner's own views are not counted
+	PropertyEventDealerView       = "dealer_view"
+	PropertyEventBrochureDownload = "brochure_download"
+	// The listing was added to a lead's or a dealer client's interests
+	PropertyEventInterest = "interest"
+)
+
+// EngagementCounts are the counters shared by daily and total stats. Unique viewers over a
+// range count each viewer once, however many days they came back.
+type EngagementCounts struct {
+	PublicViews         int64 `json:"public_views"`
+	UniquePublicViewers int64 `json:"unique_public_viewers"`
+	DealerViews         int64 `json:"dealer_views"`
+	UniqueDealerViewers int64 `json:"unique_dealer_viewers"`
+	BrochureDownloads   int64 `json:"brochure_downloads"`
+	Interests           int64 `json:"interests"`
+}
+
+// PropertyDayStats is one property's engagement on one day, dated in Indian time
+type PropertyDayStats struct {
+	PropertyID string `json:"property_id,omitempty"`
+	Date       string `json:"date"`
+	EngagementCounts
+}
+
+type PropertyStats struct {
+	PropertyID string             `json:"property_id"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Totals     EngagementCounts   `json:"totals"`
+	Days       []PropertyDayStats `json:"days"`
+}
+
+// PropertyStatsSummary is one listing's totals in a dealer summary
+type PropertyStatsSummary struct {
+	PropertyID     string           `json:"property_id"`
+	PropertyNumber int64            `json:"property_number"`
+	Title          string           `json:"title"`
+	Totals         EngagementCounts `json:"totals"`
+}
+
+// DealerStatsSummary totals engagement across a dealer's listings, most viewed listing first
+type DealerStatsSummary struct {
+	DealerID   string                 `json:"dealer_id"`
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Totals     EngagementCounts       `json:"totals"`
+	Properties []PropertyStatsSummary `json:"properties"`
+}
@@ -0,0 +1,46 @@
+package models
+
+// RequirementCriteria is the structured form of what a buyer or tenant is looking for. It sits
+// beside the free-text requirement, which is kept as written. Zero values mean no preference.
+type RequirementCriteria struct {
+	MinBudget     int64    `json:"min_budget,omitempty"`
+	MaxBudget     int64    `json:"max_budget,omitempty"`
+	MinBedrooms   int      `json:"min_bedrooms,omitempty"`
+	MaxBedrooms   int      `json:"max_bedrooms,omitempty"`
+	PropertyTypes []string `json:"property_types,omitempty"`
+	Locations     []string `json:"locations,omitempty"`
+	SubLocations  []string `json:"sub_locations,omitempty"`
+	ListingType   string   `json:"listing_type,omitempty"`
+	Furnishing    string   `json:"furnishing,omitempty"`
+}
+
+// IsEmpty reports whether no preference is set
+func (c RequirementCriteria) IsEmpty() bool {
+	return c.MinBudget == 0 && c.MaxBudget == 0 && c.MinBedrooms == 0 && c.MaxBedrooms == 0 &&
+		len(c.PropertyTypes) == 0 && len(c.Locations) == 0 && len(c.SubLocations) == 0 && c.ListingType == "" && c.Furnishing == ""
+}
+
+// PropertyMatch is a listing ranked against a requirement
+type PropertyMatch struct {
+	Property Property `json:"property"`
+	Score    int      `json:"score"`
+	Reasons  []string `json:"reasons"`
+}
+
+// What a RequirementMatch points at
+const (
+	RequirementSourceLead         = "lead"
+	RequirementSourceInquiry      = "inquiry"
+	RequirementSourceDealerClient = "dealer_client"
+)
+
+// RequirementMatch is a lead, inquiry or dealer client whose requirement a listing meets
+type RequirementMatch struct {
+	Source   string              `json:"source"`
+	ID       string              `json:"id"`
+	Name     string              `json:"name"`
+	Phone    string              `json:"phone"`
+	Criteria RequirementCriteria `json:"criteria"`
+	Score    int                 `json:"score"`
+	Reasons  []string            `json:"reasons"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// What caused a property to be checked against saved searches
+const (
+	SavedSearchTriggerCreated      = "created"
+	SavedSearchTriggerPriceUpdated = "price_updated"
+)
+
+// SavedSearch is a named GET /properties query a dealer reruns, optionally for one of their
+// clients. Query holds the same keys GET /properties accepts, without paging; it is always
+// scoped to the dealer's own listings, as GET /properties is for dealers.
+type SavedSearch struct {
+	ID             string            `json:"id"`
+	DealerID       string            `json:"dealer_id"`
+	DealerClientID string            `json:"dealer_client_id,omitempty"`
+	Name           string            `json:"name"`
+	Query          map[string]string `json:"query"`
+	Active         bool              `json:"active"`
+	MatchCount     int               `json:"match_count"`
+	LastMatchedAt  *time.Time        `json:"last_matched_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// SavedSearchMatch records that a property entered a saved search's results
+type SavedSearchMatch struct {
+	ID             string    `json:"id"`
+	SavedSearchID  string    `json:"saved_search_id"`
+	DealerID       string    `json:"dealer_id"`
+	PropertyID     string    `json:"property_id"`
+	PropertyNumber int64     `json:"property_number"`
+	PropertyTitle  string    `json:"property_title"`
+	MinPrice       int64     `json:"min_price"`
+	MaxPrice       int64     `json:"max_price"`
+	Trigger        string    `json:"trigger"`
+	MatchedAt      time.Time `json:"matched_at"`
+}
+
+type SavedSearchRequest struct {
+	Name           string            `json:"name"`
+	DealerClientID string            `json:"dealer_client_id"`
+	Query          map[string]string `json:"query"`
+	Active         *bool             `json:"active"`
+}
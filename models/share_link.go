@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// ShareLink is a dealer's expiring, revocable public link to one or more properties
+type ShareLink struct {
+	ID           string     `json:"id"`
+	DealerID     string     `json:"dealer_id"`
+	PropertyIDs  []string   `json:"property_ids"`
+	Title        string     `json:"title"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	Revoked      bool       `json:"revoked"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	Opens        int64      `json:"opens"`
+	LastOpenedAt *time.Time `json:"last_opened_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// SharedListing is what a share link's recipient sees: the dealer's branding and the listings,
+// without owner details or the exact address so the client cannot bypass the dealer
+type SharedListing struct {
+	Title      string           `json:"title"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+	Dealer     SharedDealer     `json:"dealer"`
+	Properties []SharedProperty `json:"properties"`
+}
+
+type SharedDealer struct {
+	Name          string `json:"name"`
+	ShopName      string `json:"shop_name"`
+	Phone         string `json:"phone"`
+	Email         string `json:"email,omitempty"`
+	OfficeAddress string `json:"office_address,omitempty"`
+	LogoURL       string `json:"logo_url,omitempty"`
+}
+
+type SharedProperty struct {
+	PropertyNumber  int64    `json:"property_number"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	NearestLandmark string   `json:"nearest_landmark"`
+	MinPrice        int64    `json:"min_price"`
+	MaxPrice        int64    `json:"max_price"`
+	Photos          []Photo  `json:"photos"`
+	Videos          []string `json:"videos"`
+	Area            float64  `json:"area"`
+	Bedrooms        int      `json:"bedrooms"`
+	Bathrooms       int      `json:"bathrooms"`
+	PropertyType    string   `json:"property_type"`
+	Furnishing      string   `json:"furnishing,omitempty"`
+	Floor           int      `json:"floor,omitempty"`
+	TotalFloors     int      `json:"total_floors,omitempty"`
+	Facing          string   `json:"facing,omitempty"`
+	Parking         int      `json:"parking,omitempty"`
+	PropertyAge     int      `json:"property_age,omitempty"`
+	Amenities       []string `json:"amenities,omitempty"`
+	Sold            bool     `json:"sold"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// What a task is about
+const (
+	TaskSubjectLead         = "lead"
+	TaskSubjectDealerClient = "dealer_client"
+	TaskSubjectProperty     = "property"
+)
+
+const (
+	TaskStatusOpen      = "open"
+	TaskStatusCompleted = "completed"
+)
+
+// Task is a follow-up someone has to do by DueAt, such as calling a client back. AssigneeID is
+// the user ID from the JWT of the dealer or staff member doing it. A reminder notification is
+// sent to the assignee at RemindAt.
+type Task struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Notes       string     `json:"notes,omitempty"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   string     `json:"subject_id"`
+	SubjectName string     `json:"subject_name,omitempty"`
+	AssigneeID  string     `json:"assignee_id"`
+	CreatedBy   string     `json:"created_by"`
+	Status      string     `json:"status"`
+	DueAt       time.Time  `json:"due_at"`
+	RemindAt    time.Time  `json:"remind_at"`
+	RemindedAt  *time.Time `json:"reminded_at,omitempty"`
+	SnoozeCount int        `json:"snooze_count"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CompletedBy string     `json:"completed_by,omitempty"`
+	Outcome     string     `json:"outcome,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TaskRequest creates or edits a task. The reminder goes out RemindMinutesBefore the due time,
+// or at the due time when it is zero. The subject cannot be changed once the task exists.
+type TaskRequest struct {
+	Title               string    `json:"title"`
+	Notes               string    `json:"notes"`
+	SubjectType         string    `json:"subject_type"`
+	SubjectID           string    `json:"subject_id"`
+	AssigneeID          string    `json:"assignee_id"`
+	DueAt               time.Time `json:"due_at"`
+	RemindMinutesBefore int       `json:"remind_minutes_before"`
+}
+
+// TaskSnoozeRequest pushes an open task back, either to Until or by Minutes
+type TaskSnoozeRequest struct {
+	Until   *time.Time `json:"until"`
+	Minutes int        `json:"minutes"`
+}
+
+type TaskCompleteRequest struct {
+	Outcome string `json:"outcome"`
+}
+
+// TaskQueryParams filters tasks; due_from and due_to are dates (YYYY-MM-DD) bounding the due time
+type TaskQueryParams struct {
+	AssigneeID  *string `query:"assignee_id" mongo:"assignee_id"`
+	SubjectType *string `query:"subject_type" mongo:"subject_type"`
+	SubjectID   *string `query:"subject_id" mongo:"subject_id" convert:"objectid"`
+	Status      *string `query:"status" mongo:"status" operator:"$in" convert:"csv"`
+	DueFrom     *string `query:"due_from" mongo:"due_at" operator:"$gte" convert:"date"`
+	DueTo       *string `query:"due_to" mongo:"due_at" operator:"$lte" convert:"date"`
+	BaseQueryParams
+}
+
+func (t *TaskQueryParams) SetDefaults() {
+	if t.Sort == nil || *t.Sort == "" {
+		t.Sort = &[]string{"due_at"}[0]
+	}
+	if t.Order == nil || *t.Order == "" {
+		t.Order = &[]string{"asc"}[0]
+	}
+	t.BaseQueryParams.SetDefaults()
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+const (
+	VisitStatusScheduled   = "scheduled"
+	VisitStatusRescheduled = "rescheduled"
+	VisitStatusCancelled   = "cancelled"
+	VisitStatusCompleted   = "completed"
+)
+
+// ActiveVisitStatuses are the statuses that still hold a slot on the calendar
+var ActiveVisitStatuses = []string{VisitStatusScheduled, VisitStatusRescheduled}
+
+// Visit is a site visit to a property by a lead or a dealer's client.
+// AgentName is the staff member showing the property; empty means the dealer in person.
+type Visit struct {
+	ID             string       `json:"id"`
+	DealerID       string       `json:"dealer_id"`
+	PropertyID     string       `json:"property_id"`
+	PropertyNumber int64        `json:"property_number"`
+	LeadID         string       `json:"lead_id,omitempty"`
+	DealerClientID string       `json:"dealer_client_id,omitempty"`
+	ClientName     string       `json:"client_name"`
+	ClientPhone    string       `json:"client_phone"`
+	AgentName      string       `json:"agent_name,omitempty"`
+	StartsAt       time.Time    `json:"starts_at"`
+	EndsAt         time.Time    `json:"ends_at"`
+	Status         string       `json:"status"`
+	Note           string       `json:"note,omitempty"`
+	CancelReason   string       `json:"cancel_reason,omitempty"`
+	OutcomeNote    string       `json:"outcome_note,omitempty"`
+	History        []VisitEvent `json:"history"`
+	CreatedBy      string       `json:"created_by"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// VisitEvent records a status change, with the slot as it stood after the change
+type VisitEvent struct {
+	Status    string    `json:"status"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Note      string    `json:"note,omitempty"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// VisitQueryParams filters visits; from and to are dates (YYYY-MM-DD) bounding the slot
+type VisitQueryParams struct {
+	ID             *string `query:"id" mongo:"_id" convert:"objectid"`
+	DealerID       *string `query:"dealer_id" mongo:"dealer_id" convert:"objectid"`
+	PropertyID     *string `query:"property_id" mongo:"property_id" convert:"objectid"`
+	LeadID         *string `query:"lead_id" mongo:"lead_id" convert:"objectid"`
+	DealerClientID *string `query:"dealer_client_id" mongo:"dealer_client_id" convert:"objectid"`
+	AgentName      *string `query:"agent_name" mongo:"agent_name"`
+	Status         *string `query:"status" mongo:"status" operator:"$in" convert:"csv"`
+	From           *string `query:"from" mongo:"starts_at" operator:"$gte" convert:"date"`
+	To             *string `query:"to" mongo:"ends_at" operator:"$lte" convert:"date"`
+	BaseQueryParams
+}
+
+func (v *VisitQueryParams) SetDefaults() {
+	if v.Sort == nil || *v.Sort == "" {
+		v.Sort = &[]string{"starts_at"}[0]
+	}
+	if v.Order == nil || *v.Order == "" {
+		v.Order = &[]string{"asc"}[0]
+	}
+	v.BaseQueryParams.SetDefaults()
+}
+
+// VisitRequest schedules a visit. Exactly one of LeadID and DealerClientID is set;
+// DurationMinutes defaults to 30. Rescheduling reads only the slot and the note.
+type VisitRequest struct {
+	PropertyID      string    `json:"property_id"`
+	LeadID          string    `json:"lead_id"`
+	DealerClientID  string    `json:"dealer_client_id"`
+	AgentName       string    `json:"agent_name"`
+	StartsAt        time.Time `json:"starts_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Note            string    `json:"note"`
+}
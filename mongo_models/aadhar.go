@@ -0,0 +1,17 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AadharAccessLog struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	LeadID     primitive.ObjectID `bson:"lead_id"`
+	RevealedBy string             `bson:"revealed_by"`
+	Reason     string             `bson:"reason,omitempty"`
+	IP         string             `bson:"ip"`
+	UserAgent  string             `bson:"user_agent"`
+	RevealedAt time.Time          `bson:"revealed_at"`
+}
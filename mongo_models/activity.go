@@ -0,0 +1,20 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Activity struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	SubjectType string              `bson:"subject_type"`
+	SubjectID   primitive.ObjectID  `bson:"subject_id"`
+	Type        string              `bson:"type"`
+	Body        string              `bson:"body"`
+	PropertyID  *primitive.ObjectID `bson:"property_id,omitempty"`
+	AuthorID    string              `bson:"author_id,omitempty"`
+	System      bool                `bson:"system"`
+	OccurredAt  time.Time           `bson:"occurred_at"`
+	CreatedAt   time.Time           `bson:"created_at"`
+}
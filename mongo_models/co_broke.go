@@ -0,0 +1,33 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CoBrokeTerms struct {
+	Enabled         bool      `bson:"enabled"`
+	CommissionSplit float64   `bson:"commission_split"`
+	Terms           string    `bson:"terms,omitempty"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+}
+
+type CoBrokeAgreement struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	PropertyID      primitive.ObjectID `bson:"property_id"`
+	PropertyNumber  int64              `bson:"property_number"`
+	PropertyTitle   string             `bson:"property_title"`
+	ListingDealerID primitive.ObjectID `bson:"listing_dealer_id"`
+	PartnerDealerID primitive.ObjectID `bson:"partner_dealer_id"`
+	CommissionSplit float64            `bson:"commission_split"`
+	Terms           string             `bson:"terms,omitempty"`
+	Message         string             `bson:"message,omitempty"`
+	Status          string             `bson:"status"`
+	ResponseNote    string             `bson:"response_note,omitempty"`
+	RespondedAt     *time.Time         `bson:"responded_at,omitempty"`
+	ClosingNote     string             `bson:"closing_note,omitempty"`
+	ClosedAt        *time.Time         `bson:"closed_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LeadMerge struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	LeadID            primitive.ObjectID `bson:"lead_id"`
+	MergedLeadID      primitive.ObjectID `bson:"merged_lead_id"`
+	MergedName        string             `bson:"merged_name"`
+	MergedPhone       string             `bson:"merged_phone"`
+	MergedRequirement string             `bson:"merged_requirement,omitempty"`
+	InterestsAdded    int                `bson:"interests_added"`
+	InterestsCombined int                `bson:"interests_combined"`
+	MergedBy          string             `bson:"merged_by"`
+	CreatedAt         time.Time          `bson:"created_at"`
+}
+
+// ContactSummary is one row of the duplicate contacts aggregation
+type ContactSummary struct {
+	Key           string   `bson:"_id"`
+	Names         []string `bson:"names"`
+	Leads         int64    `bson:"leads"`
+	Inquiries     int64    `bson:"inquiries"`
+	DealerClients int64    `bson:"dealer_clients"`
+}
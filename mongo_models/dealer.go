@@ -15,10 +15,21 @@ type Dealer struct {
 	ShopName string `json:"shop_name" bson:"shop_name"`
 	Location string `json:"location" bson:"location"`
 	SubLocation string `json:"sub_location" bson:"sub_location"`
+	Watermark *WatermarkSettings `json:"watermark,omitempty" bson:"watermark,omitempty"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" bson:"deactivated_at,omitempty"`
+	SuccessorDealerID *primitive.ObjectID `json:"successor_dealer_id,omitempty" bson:"successor_dealer_id,omitempty"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
+type WatermarkSettings struct {
+	Enabled  bool    `json:"enabled" bson:"enabled"`
+	Type     string  `json:"type" bson:"type"`
+	LogoKey  string  `json:"logo_key,omitempty" bson:"logo_key,omitempty"`
+	Position string  `json:"position" bson:"position"`
+	Opacity  float64 `json:"opacity" bson:"opacity"`
+}
+
 type LocationWithSubLocations struct {
     Location string   `json:"location"`
     SubLocation []string `json:"sub_location"`
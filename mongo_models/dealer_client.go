@@ -7,6 +7,7 @@ import (
 )
 
 type Document struct {
+	Key  string `bson:"key,omitempty"`
 	URL  string `bson:"url"`
 	Type string `bson:"type"`
 	Size int64  `bson:"size"`
@@ -17,8 +18,11 @@ type DealerClient struct {
 	DealerID          primitive.ObjectID             `bson:"dealer_id"`
 	Name              string                         `bson:"name"`
 	Phone             string                         `bson:"phone"`
+	ContactKey        string                         `bson:"contact_key,omitempty"`
 	Note              string                         `bson:"note"`
+	Criteria          *RequirementCriteria           `bson:"criteria,omitempty"`
 	Docs              []Document                     `bson:"docs,omitempty"`
+	SourceInquiryID   *primitive.ObjectID            `bson:"source_inquiry_id,omitempty"`
 	PropertyInterests []DealerClientPropertyInterest `bson:"properties,omitempty"`
 	CreatedAt         time.Time                      `bson:"created_at"`
 	UpdatedAt         time.Time                      `bson:"updated_at"`
@@ -35,13 +39,15 @@ type DealerClientPropertyInterest struct {
 }
 
 type DealerClientUpdate struct {
-	Name  *string           `bson:"name"`
-	Phone *string           `bson:"phone"`
-	Note  *string           `bson:"note"`
-	Docs  *[]DocumentUpdate `bson:"docs"`
+	Name     *string              `bson:"name"`
+	Phone    *string              `bson:"phone"`
+	Note     *string              `bson:"note"`
+	Criteria *RequirementCriteria `bson:"criteria"`
+	Docs     *[]DocumentUpdate    `bson:"docs"`
 }
 
 type DocumentUpdate struct {
+	Key  *string `bson:"key"`
 	URL  *string `bson:"url"`
 	Type *string `bson:"type"`
 	Size *int64  `bson:"size"`
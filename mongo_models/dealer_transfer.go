@@ -0,0 +1,26 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DealerTransfer struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	FromDealerID      primitive.ObjectID `bson:"from_dealer_id"`
+	ToDealerID        primitive.ObjectID `bson:"to_dealer_id"`
+	Reason            string             `bson:"reason,omitempty"`
+	PerformedBy       string             `bson:"performed_by"`
+	Properties        int64              `bson:"properties"`
+	DealerClients     int64              `bson:"dealer_clients"`
+	Inquiries         int64              `bson:"inquiries"`
+	SavedSearches     int64              `bson:"saved_searches"`
+	Leads             int64              `bson:"leads"`
+	AssignedLeads     int64              `bson:"assigned_leads"`
+	Tasks             int64              `bson:"tasks"`
+	Visits            int64              `bson:"visits"`
+	CoBrokeAgreements int64              `bson:"co_broke_agreements"`
+	RevokedShareLinks int64              `bson:"revoked_share_links"`
+	CreatedAt         time.Time          `bson:"created_at"`
+}
@@ -0,0 +1,17 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DocumentAccessLog struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	DealerID   primitive.ObjectID `bson:"dealer_id"`
+	ClientID   primitive.ObjectID `bson:"client_id"`
+	Key        string             `bson:"key"`
+	IP         string             `bson:"ip"`
+	UserAgent  string             `bson:"user_agent"`
+	AccessedAt time.Time          `bson:"accessed_at"`
+}
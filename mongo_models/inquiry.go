@@ -7,21 +7,29 @@ import (
 )
 
 type Inquiry struct {
-	ID          primitive.ObjectID  `bson:"_id,omitempty"`
-	DealerID    *primitive.ObjectID `bson:"dealer_id,omitempty"`
-	Source      string              `bson:"source"`
-	Name        string              `bson:"name"`
-	Phone       string              `bson:"phone"`
-	Requirement string              `bson:"requirement"`
-	CreatedAt   time.Time           `bson:"created_at"`
-	UpdatedAt   time.Time           `bson:"updated_at"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty"`
+	DealerID    *primitive.ObjectID  `bson:"dealer_id,omitempty"`
+	Source      string               `bson:"source"`
+	Name        string               `bson:"name"`
+	Phone       string               `bson:"phone"`
+	ContactKey  string               `bson:"contact_key,omitempty"`
+	Requirement string               `bson:"requirement"`
+	Criteria    *RequirementCriteria `bson:"criteria,omitempty"`
+	Converted   bool                 `bson:"converted,omitempty"`
+	ConvertedTo string               `bson:"converted_to,omitempty"`
+	ConvertedID *primitive.ObjectID  `bson:"converted_id,omitempty"`
+	ConvertedBy string               `bson:"converted_by,omitempty"`
+	ConvertedAt *time.Time           `bson:"converted_at,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"`
 }
 
 type InquiryUpdate struct {
-	DealerID    *primitive.ObjectID `bson:"dealer_id"`
-	Source      *string             `bson:"source"`
-	Name        *string             `bson:"name"`
-	Phone       *string             `bson:"phone"`
-	Requirement *string             `bson:"requirement"`
-	UpdatedAt   *time.Time          `bson:"updated_at"`
+	DealerID    *primitive.ObjectID  `bson:"dealer_id"`
+	Source      *string              `bson:"source"`
+	Name        *string              `bson:"name"`
+	Phone       *string              `bson:"phone"`
+	Requirement *string              `bson:"requirement"`
+	Criteria    *RequirementCriteria `bson:"criteria"`
+	UpdatedAt   *time.Time           `bson:"updated_at"`
 }
@@ -7,14 +7,27 @@ import (
 )
 
 type Lead struct {
-	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Name        string             `json:"name" bson:"name"`
-	Phone       string             `json:"phone" bson:"phone"`
-	Requirement string             `json:"requirement" bson:"requirement"`
-	Properties  []PropertyInterest `json:"properties,omitempty" bson:"properties,omitempty"`
-
-	AadharNumber string `json:"aadhar_number,omitempty" bson:"aadhar_number,omitempty"`
-	AadharPhoto  string `json:"aadhar_photo,omitempty" bson:"aadhar_photo,omitempty"`
+	ID          primitive.ObjectID   `json:"id,omitempty" bson:"_id,omitempty"`
+	Name        string               `json:"name" bson:"name"`
+	Phone       string               `json:"phone" bson:"phone"`
+	ContactKey  string               `json:"contact_key,omitempty" bson:"contact_key,omitempty"`
+	Requirement string               `json:"requirement" bson:"requirement"`
+	Criteria    *RequirementCriteria `json:"criteria,omitempty" bson:"criteria,omitempty"`
+	Properties  []PropertyInterest   `json:"properties,omitempty" bson:"properties,omitempty"`
+
+	// The encrypted Aadhaar number; see models.StoredAadhar
+	AadharNumber     string `json:"aadhar_number,omitempty" bson:"aadhar_number,omitempty"`
+	AadharKeyVersion int    `json:"aadhar_key_version,omitempty" bson:"aadhar_key_version,omitempty"`
+	AadharLast4      string `json:"aadhar_last4,omitempty" bson:"aadhar_last4,omitempty"`
+	AadharHash       string `json:"aadhar_hash,omitempty" bson:"aadhar_hash,omitempty"`
+	AadharHashKey    string `json:"aadhar_hash_key,omitempty" bson:"aadhar_hash_key,omitempty"`
+	AadharPhoto      string `json:"aadhar_photo,omitempty" bson:"aadhar_photo,omitempty"`
+
+	AssignedDealerID *primitive.ObjectID `json:"assigned_dealer_id,omitempty" bson:"assigned_dealer_id,omitempty"`
+	AssignedAt       *time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
+
+	SourceInquiryID *primitive.ObjectID `json:"source_inquiry_id,omitempty" bson:"source_inquiry_id,omitempty"`
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 
 	PopulatedProperties []Property `json:"populated_properties,omitempty" bson:"populated_properties,omitempty"`
 }
@@ -25,16 +38,20 @@ type PropertyInterest struct {
 	DealerID   primitive.ObjectID `json:"dealer_id" bson:"dealer_id"` // ← ADD THIS
 	Status     string             `json:"status" bson:"status"`
 	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	SoldPrice       int64                          `json:"sold_price,omitempty" bson:"sold_price,omitempty"`
+	LostReason      string                         `json:"lost_reason,omitempty" bson:"lost_reason,omitempty"`
+	StatusChangedAt *time.Time                     `json:"status_changed_at,omitempty" bson:"status_changed_at,omitempty"`
+	History         []PropertyInterestStatusChange `json:"history,omitempty" bson:"history,omitempty"`
 	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
 
-const (
-	LeadStatusView         = "view"
-	LeadStatusInterested   = "interested"
-	LeadStatusBooked       = "booked"
-	LeadStatusCancelled    = "cancelled"
-	LeadStatusFailed       = "failed"
-	LeadStatusUninterested = "uninterested"
-)
-
-// Add these structs to your existing models/lead.go
+type PropertyInterestStatusChange struct {
+	From      string    `json:"from,omitempty" bson:"from,omitempty"`
+	To        string    `json:"to" bson:"to"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+	Reason    string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	SoldPrice int64     `json:"sold_price,omitempty" bson:"sold_price,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty" bson:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at" bson:"changed_at"`
+}
@@ -0,0 +1,37 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LeadRoutingRule struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty"`
+	Name           string               `bson:"name"`
+	Priority       int                  `bson:"priority"`
+	Active         bool                 `bson:"active"`
+	Locations      []string             `bson:"locations,omitempty"`
+	SubLocations   []string             `bson:"sub_locations,omitempty"`
+	MinBudget      int64                `bson:"min_budget,omitempty"`
+	MaxBudget      int64                `bson:"max_budget,omitempty"`
+	DealerIDs      []primitive.ObjectID `bson:"dealer_ids,omitempty"`
+	Strategy       string               `bson:"strategy"`
+	DealerCapacity int                  `bson:"dealer_capacity,omitempty"`
+	// Round-robin position, advanced atomically on every assignment the rule makes
+	Turn      int64     `bson:"turn"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+type LeadAssignment struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty"`
+	LeadID       primitive.ObjectID  `bson:"lead_id"`
+	FromDealerID *primitive.ObjectID `bson:"from_dealer_id,omitempty"`
+	ToDealerID   *primitive.ObjectID `bson:"to_dealer_id,omitempty"`
+	Method       string              `bson:"method"`
+	RuleID       *primitive.ObjectID `bson:"rule_id,omitempty"`
+	AssignedBy   string              `bson:"assigned_by,omitempty"`
+	Note         string              `bson:"note,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at"`
+}
@@ -0,0 +1,20 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Media struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Key         string             `bson:"key"`
+	OwnerID     string             `bson:"owner_id"`
+	Purpose     string             `bson:"purpose"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	Status      string             `bson:"status"`
+	UploadID    string             `bson:"upload_id,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Notification struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	RecipientID string             `bson:"recipient_id"`
+	Type        string             `bson:"type"`
+	Title       string             `bson:"title"`
+	Body        string             `bson:"body"`
+	Data        map[string]string  `bson:"data,omitempty"`
+	ReadAt      *time.Time         `bson:"read_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
@@ -1,8 +1,11 @@
 package mongo_models
 
 import (
+	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,7 +18,7 @@ type Property struct {
 	Address         string             `bson:"address"`
 	MinPrice        int64              `bson:"min_price"`
 	MaxPrice        int64              `bson:"max_price"`
-	Photos         []string           `bson:"photos,omitempty"`
+	Photos          []Photo            `bson:"photos,omitempty"`
 	Videos          []string           `bson:"videos,omitempty"`
 	OwnerName       string             `bson:"owner_name"`
 	OwnerPhone      string             `bson:"owner_phone"`
@@ -30,8 +33,45 @@ type Property struct {
 	Bedrooms        int                `bson:"bedrooms"`
 	Bathrooms       int                `bson:"bathrooms"`
 	PropertyType    string             `bson:"property_type"`
+	Furnishing      string             `bson:"furnishing,omitempty"`
+	ListingType     string             `bson:"listing_type,omitempty"`
+	Floor           int                `bson:"floor"`
+	TotalFloors     int                `bson:"total_floors,omitempty"`
+	Facing          string             `bson:"facing,omitempty"`
+	Parking         int                `bson:"parking"`
+	PropertyAge     int                `bson:"property_age"`
+	Amenities       []string           `bson:"amenities,omitempty"`
+	CoBroke         *CoBrokeTerms      `bson:"co_broke,omitempty"`
 }
 
+type Photo struct {
+	Key      string            `bson:"key,omitempty"`
+	URL      string            `bson:"url"`
+	Variants map[string]string `bson:"variants,omitempty"`
+}
+
+// UnmarshalBSONValue keeps older documents readable, where photos were stored as plain URL strings
+func (p *Photo) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null {
+		return nil
+	}
+	if t == bsontype.String {
+		url, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+		if !ok {
+			return fmt.Errorf("invalid photo value")
+		}
+		*p = Photo{URL: url}
+		return nil
+	}
+
+	type photoAlias Photo
+	var alias photoAlias
+	if err := bson.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*p = Photo(alias)
+	return nil
+}
 
 type PropertyUpdate struct {
     Title           *string    `bson:"title"`
@@ -41,7 +81,7 @@ type PropertyUpdate struct {
     MinPrice        *int64     `bson:"min_price"`
     MaxPrice        *int64     `bson:"max_price"`
     Description     *string    `bson:"description"`
-    Photos          *[]string  `bson:"photos"`
+    Photos          *[]Photo   `bson:"photos"`
     Videos          *[]string  `bson:"videos"`
     OwnerName       *string    `bson:"owner_name"`
     OwnerPhone      *string    `bson:"owner_phone"`
@@ -53,6 +93,14 @@ type PropertyUpdate struct {
     Bedrooms        *int       `bson:"bedrooms"`
     Bathrooms       *int       `bson:"bathrooms"`
     PropertyType    *string    `bson:"property_type"`
+    Furnishing      *string    `bson:"furnishing"`
+    ListingType     *string    `bson:"listing_type"`
+    Floor           *int       `bson:"floor"`
+    TotalFloors     *int       `bson:"total_floors"`
+    Facing          *string    `bson:"facing"`
+    Parking         *int       `bson:"parking"`
+    PropertyAge     *int       `bson:"property_age"`
+    Amenities       *[]string  `bson:"amenities"`
     UpdatedAt       *time.Time `bson:"updated_at"`
 }
 
@@ -0,0 +1,16 @@
+package mongo_models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// PropertyDayStats holds the counters for one property on one day. Unique viewers are kept in
+// Redis HyperLogLogs rather than here.
+type PropertyDayStats struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	PropertyID        primitive.ObjectID `bson:"property_id"`
+	DealerID          primitive.ObjectID `bson:"dealer_id"`
+	Date              string             `bson:"date"`
+	PublicViews       int64              `bson:"public_views"`
+	DealerViews       int64              `bson:"dealer_views"`
+	BrochureDownloads int64              `bson:"brochure_downloads"`
+	Interests         int64              `bson:"interests"`
+}
@@ -0,0 +1,13 @@
+package mongo_models
+
+type RequirementCriteria struct {
+	MinBudget     int64    `bson:"min_budget,omitempty"`
+	MaxBudget     int64    `bson:"max_budget,omitempty"`
+	MinBedrooms   int      `bson:"min_bedrooms,omitempty"`
+	MaxBedrooms   int      `bson:"max_bedrooms,omitempty"`
+	PropertyTypes []string `bson:"property_types,omitempty"`
+	Locations     []string `bson:"locations,omitempty"`
+	SubLocations  []string `bson:"sub_locations,omitempty"`
+	ListingType   string   `bson:"listing_type,omitempty"`
+	Furnishing    string   `bson:"furnishing,omitempty"`
+}
@@ -0,0 +1,34 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SavedSearch struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty"`
+	DealerID       primitive.ObjectID  `bson:"dealer_id"`
+	DealerClientID *primitive.ObjectID `bson:"dealer_client_id,omitempty"`
+	Name           string              `bson:"name"`
+	// Query is URL-encoded so keys with operators such as amenities[all] survive as field names
+	Query         string     `bson:"query"`
+	Active        bool       `bson:"active"`
+	MatchCount    int        `bson:"match_count"`
+	LastMatchedAt *time.Time `bson:"last_matched_at,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at"`
+}
+
+type SavedSearchMatch struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	SavedSearchID  primitive.ObjectID `bson:"saved_search_id"`
+	DealerID       primitive.ObjectID `bson:"dealer_id"`
+	PropertyID     primitive.ObjectID `bson:"property_id"`
+	PropertyNumber int64              `bson:"property_number"`
+	PropertyTitle  string             `bson:"property_title"`
+	MinPrice       int64              `bson:"min_price"`
+	MaxPrice       int64              `bson:"max_price"`
+	Trigger        string             `bson:"trigger"`
+	MatchedAt      time.Time          `bson:"matched_at"`
+}
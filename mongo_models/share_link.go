@@ -0,0 +1,21 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShareLink struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty"`
+	DealerID     primitive.ObjectID   `bson:"dealer_id"`
+	PropertyIDs  []primitive.ObjectID `bson:"property_ids"`
+	Title        string               `bson:"title"`
+	ExpiresAt    time.Time            `bson:"expires_at"`
+	Revoked      bool                 `bson:"revoked"`
+	RevokedAt    *time.Time           `bson:"revoked_at,omitempty"`
+	Opens        int64                `bson:"opens"`
+	LastOpenedAt *time.Time           `bson:"last_opened_at,omitempty"`
+	CreatedAt    time.Time            `bson:"created_at"`
+	UpdatedAt    time.Time            `bson:"updated_at"`
+}
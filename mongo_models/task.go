@@ -0,0 +1,28 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Task struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Title       string             `bson:"title"`
+	Notes       string             `bson:"notes,omitempty"`
+	SubjectType string             `bson:"subject_type"`
+	SubjectID   primitive.ObjectID `bson:"subject_id"`
+	SubjectName string             `bson:"subject_name,omitempty"`
+	AssigneeID  string             `bson:"assignee_id"`
+	CreatedBy   string             `bson:"created_by"`
+	Status      string             `bson:"status"`
+	DueAt       time.Time          `bson:"due_at"`
+	RemindAt    time.Time          `bson:"remind_at"`
+	RemindedAt  *time.Time         `bson:"reminded_at,omitempty"`
+	SnoozeCount int                `bson:"snooze_count"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+	CompletedBy string             `bson:"completed_by,omitempty"`
+	Outcome     string             `bson:"outcome,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package mongo_models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Visit struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty"`
+	DealerID       primitive.ObjectID  `bson:"dealer_id"`
+	PropertyID     primitive.ObjectID  `bson:"property_id"`
+	PropertyNumber int64               `bson:"property_number"`
+	LeadID         *primitive.ObjectID `bson:"lead_id,omitempty"`
+	DealerClientID *primitive.ObjectID `bson:"dealer_client_id,omitempty"`
+	ClientName     string              `bson:"client_name"`
+	ClientPhone    string              `bson:"client_phone"`
+	AgentName      string              `bson:"agent_name"`
+	StartsAt       time.Time           `bson:"starts_at"`
+	EndsAt         time.Time           `bson:"ends_at"`
+	Status         string              `bson:"status"`
+	Note           string              `bson:"note,omitempty"`
+	CancelReason   string              `bson:"cancel_reason,omitempty"`
+	OutcomeNote    string              `bson:"outcome_note,omitempty"`
+	History        []VisitEvent        `bson:"history"`
+	CreatedBy      string              `bson:"created_by"`
+	CreatedAt      time.Time           `bson:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at"`
+}
+
+type VisitEvent struct {
+	Status    string    `bson:"status"`
+	StartsAt  time.Time `bson:"starts_at"`
+	EndsAt    time.Time `bson:"ends_at"`
+	Note      string    `bson:"note,omitempty"`
+	ChangedBy string    `bson:"changed_by"`
+	ChangedAt time.Time `bson:"changed_at"`
+}
@@ -0,0 +1,60 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAadharAccessLogRepository is append-only; entries are never updated or deleted
+type MongoAadharAccessLogRepository struct {
+	accessLogCollection *mongo.Collection
+}
+
+func NewMongoAadharAccessLogRepository(accessLogCollection *mongo.Collection) repositories.AadharAccessLogRepository {
+	return &MongoAadharAccessLogRepository{
+		accessLogCollection: accessLogCollection,
+	}
+}
+
+func (r *MongoAadharAccessLogRepository) Create(ctx context.Context, entry models.AadharAccessLog) error {
+	if entry.RevealedAt.IsZero() {
+		entry.RevealedAt = time.Now()
+	}
+
+	mongoEntry, err := converters.ToMongoAadharAccessLog(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.accessLogCollection.InsertOne(ctx, mongoEntry)
+	return err
+}
+
+func (r *MongoAadharAccessLogRepository) GetByLead(ctx context.Context, leadID string, limit int64) ([]models.AadharAccessLog, error) {
+	leadObjectID, err := primitive.ObjectIDFromHex(leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.M{"revealed_at": -1}).SetLimit(limit)
+	cursor, err := r.accessLogCollection.Find(ctx, bson.M{"lead_id": leadObjectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []mongoModels.AadharAccessLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainAadharAccessLogSlice(entries), nil
+}
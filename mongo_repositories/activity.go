@@ -0,0 +1,69 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"myapp/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoActivityRepository struct {
+	activityCollection *mongo.Collection
+}
+
+func NewMongoActivityRepository(activityCollection *mongo.Collection) repositories.ActivityRepository {
+	return &MongoActivityRepository{
+		activityCollection: activityCollection,
+	}
+}
+
+func (r *MongoActivityRepository) Create(ctx context.Context, activity models.Activity) (models.Activity, error) {
+	activity.CreatedAt = time.Now()
+	if activity.OccurredAt.IsZero() {
+		activity.OccurredAt = activity.CreatedAt
+	}
+
+	mongoActivity, err := converters.ToMongoActivity(activity)
+	if err != nil {
+		return models.Activity{}, err
+	}
+
+	result, err := r.activityCollection.InsertOne(ctx, mongoActivity)
+	if err != nil {
+		return models.Activity{}, err
+	}
+
+	mongoActivity.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainActivity(mongoActivity), nil
+}
+
+func (r *MongoActivityRepository) GetActivities(ctx context.Context, params models.ActivityQueryParams) ([]models.Activity, error) {
+	params.SetDefaults()
+
+	filter := utils.BuildMongoFilter(params)
+	// Entries logged at the same moment keep the order they were written in
+	opts := options.Find().
+		SetSort(bson.D{{Key: *params.Sort, Value: getSortOrder(*params.Order)}, {Key: "_id", Value: getSortOrder(*params.Order)}}).
+		SetSkip(int64((*params.Page - 1) * (*params.Limit))).
+		SetLimit(int64(*params.Limit))
+
+	cursor, err := r.activityCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoActivities []mongoModels.Activity
+	if err := cursor.All(ctx, &mongoActivities); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainActivitySlice(mongoActivities), nil
+}
@@ -0,0 +1,161 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoCoBrokeRepository struct {
+	agreementCollection *mongo.Collection
+	lockCollection      *mongo.Collection
+}
+
+// NewMongoCoBrokeRepository serialises requests for the same listing and partner through a lock
+// document in lockCollection
+func NewMongoCoBrokeRepository(agreementCollection *mongo.Collection, lockCollection *mongo.Collection) repositories.CoBrokeRepository {
+	return &MongoCoBrokeRepository{
+		agreementCollection: agreementCollection,
+		lockCollection:      lockCollection,
+	}
+}
+
+// Create inserts a request unless the partner already has a pending or accepted agreement on the
+// listing, reporting false in that case. Each attempt first bumps the lock document of the
+// listing and partner, so concurrent requests write-conflict and the one retried sees the other.
+func (r *MongoCoBrokeRepository) Create(ctx context.Context, agreement models.CoBrokeAgreement) (models.CoBrokeAgreement, bool, error) {
+	now := time.Now()
+	agreement.CreatedAt = now
+	agreement.UpdatedAt = now
+
+	mongoAgreement, err := converters.ToMongoCoBrokeAgreement(agreement)
+	if err != nil {
+		return models.CoBrokeAgreement{}, false, err
+	}
+
+	session, err := r.agreementCollection.Database().Client().StartSession()
+	if err != nil {
+		return models.CoBrokeAgreement{}, false, err
+	}
+	defer session.EndSession(ctx)
+
+	created := false
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		created = false
+		lockID := agreement.PropertyID + ":" + agreement.PartnerDealerID
+		_, err := r.lockCollection.UpdateOne(sc, bson.M{"_id": lockID},
+			bson.M{"$inc": bson.M{"requests": 1}}, options.Update().SetUpsert(true))
+		if err != nil {
+			return nil, err
+		}
+
+		open, err := r.agreementCollection.CountDocuments(sc, bson.M{
+			"property_id":       mongoAgreement.PropertyID,
+			"partner_dealer_id": mongoAgreement.PartnerDealerID,
+			"status":            bson.M{"$in": models.OpenCoBrokeStatuses},
+		})
+		if err != nil || open > 0 {
+			return nil, err
+		}
+
+		result, err := r.agreementCollection.InsertOne(sc, mongoAgreement)
+		if err != nil {
+			return nil, err
+		}
+		mongoAgreement.ID = result.InsertedID.(primitive.ObjectID)
+		created = true
+		return nil, nil
+	})
+	if err != nil || !created {
+		return models.CoBrokeAgreement{}, false, err
+	}
+	return converters.ToDomainCoBrokeAgreement(mongoAgreement), true, nil
+}
+
+func (r *MongoCoBrokeRepository) GetByID(ctx context.Context, id string) (models.CoBrokeAgreement, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+
+	var mongoAgreement mongoModels.CoBrokeAgreement
+	if err := r.agreementCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoAgreement); err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+	return converters.ToDomainCoBrokeAgreement(mongoAgreement), nil
+}
+
+// GetByDealer lists a dealer's agreements, newest first. side narrows to those where the dealer
+// owns the listing or is the partner; an empty side or status matches all.
+func (r *MongoCoBrokeRepository) GetByDealer(ctx context.Context, dealerID string, side string, status string) ([]models.CoBrokeAgreement, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter bson.M
+	switch side {
+	case models.CoBrokeSideListing:
+		filter = bson.M{"listing_dealer_id": dealerObjectID}
+	case models.CoBrokeSidePartner:
+		filter = bson.M{"partner_dealer_id": dealerObjectID}
+	default:
+		filter = bson.M{"$or": []bson.M{
+			{"listing_dealer_id": dealerObjectID},
+			{"partner_dealer_id": dealerObjectID},
+		}}
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := r.agreementCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoAgreements []mongoModels.CoBrokeAgreement
+	if err := cursor.All(ctx, &mongoAgreements); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainCoBrokeAgreementSlice(mongoAgreements), nil
+}
+
+// UpdateStatus writes the agreement's status fields if it is still in fromStatus, so of two
+// concurrent changes only the first applies; mongo.ErrNoDocuments means it had already moved on
+func (r *MongoCoBrokeRepository) UpdateStatus(ctx context.Context, id string, fromStatus string, agreement models.CoBrokeAgreement) (models.CoBrokeAgreement, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+
+	set := bson.M{
+		"status":        agreement.Status,
+		"response_note": agreement.ResponseNote,
+		"responded_at":  agreement.RespondedAt,
+		"closing_note":  agreement.ClosingNote,
+		"closed_at":     agreement.ClosedAt,
+		"updated_at":    time.Now(),
+	}
+
+	var mongoAgreement mongoModels.CoBrokeAgreement
+	err = r.agreementCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID, "status": fromStatus},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&mongoAgreement)
+	if err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+	return converters.ToDomainCoBrokeAgreement(mongoAgreement), nil
+}
@@ -0,0 +1,353 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"myapp/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoContactRepository struct {
+	leadCollection         *mongo.Collection
+	inquiryCollection      *mongo.Collection
+	dealerClientCollection *mongo.Collection
+	visitCollection        *mongo.Collection
+	taskCollection         *mongo.Collection
+	assignmentCollection   *mongo.Collection
+	mergeCollection        *mongo.Collection
+	activityCollection     *mongo.Collection
+}
+
+func NewMongoContactRepository(leadCollection, inquiryCollection, dealerClientCollection, visitCollection, taskCollection, assignmentCollection, mergeCollection, activityCollection *mongo.Collection) repositories.ContactRepository {
+	return &MongoContactRepository{
+		leadCollection:         leadCollection,
+		inquiryCollection:      inquiryCollection,
+		dealerClientCollection: dealerClientCollection,
+		visitCollection:        visitCollection,
+		taskCollection:         taskCollection,
+		assignmentCollection:   assignmentCollection,
+		mergeCollection:        mergeCollection,
+		activityCollection:     activityCollection,
+	}
+}
+
+// BackfillContactKeys sets the contact key of records saved before contacts existed and returns
+// how many were updated
+func (r *MongoContactRepository) BackfillContactKeys(ctx context.Context) (int64, error) {
+	var total int64
+	for _, collection := range []*mongo.Collection{r.leadCollection, r.inquiryCollection, r.dealerClientCollection} {
+		cursor, err := collection.Find(ctx, bson.M{"contact_key": bson.M{"$exists": false}},
+			options.Find().SetProjection(bson.M{"_id": 1, "phone": 1}))
+		if err != nil {
+			return total, err
+		}
+		var records []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Phone string             `bson:"phone"`
+		}
+		if err := cursor.All(ctx, &records); err != nil {
+			return total, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		writes := make([]mongo.WriteModel, len(records))
+		for i, record := range records {
+			writes[i] = mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": record.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"contact_key": utils.NormalizePhone(record.Phone)}})
+		}
+		result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return total, err
+		}
+		total += result.ModifiedCount
+	}
+	return total, nil
+}
+
+// GetDuplicates lists contact keys shared by more than one record, or by more than one lead when
+// leadsOnly is set, those with the most leads first
+func (r *MongoContactRepository) GetDuplicates(ctx context.Context, leadsOnly bool, page int, limit int) ([]models.ContactSummary, error) {
+	records := func(kind string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{"contact_key": bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$project": bson.M{"contact_key": 1, "name": 1, "kind": bson.M{"$literal": kind}}},
+		}
+	}
+	count := func(kind string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$kind", kind}}, 1, 0}}}
+	}
+
+	having := bson.M{"total": bson.M{"$gt": 1}}
+	if leadsOnly {
+		having = bson.M{"leads": bson.M{"$gt": 1}}
+	}
+
+	pipeline := append(records(models.ContactEntityLead),
+		bson.M{"$unionWith": bson.M{"coll": r.inquiryCollection.Name(), "pipeline": records(models.ContactEntityInquiry)}},
+		bson.M{"$unionWith": bson.M{"coll": r.dealerClientCollection.Name(), "pipeline": records(models.ContactEntityDealerClient)}},
+		bson.M{"$group": bson.M{
+			"_id":            "$contact_key",
+			"names":          bson.M{"$addToSet": "$name"},
+			"leads":          count(models.ContactEntityLead),
+			"inquiries":      count(models.ContactEntityInquiry),
+			"dealer_clients": count(models.ContactEntityDealerClient),
+			"total":          bson.M{"$sum": 1},
+		}},
+		bson.M{"$match": having},
+		bson.M{"$sort": bson.D{{Key: "leads", Value: -1}, {Key: "total", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$skip": int64((page - 1) * limit)},
+		bson.M{"$limit": int64(limit)},
+	)
+
+	cursor, err := r.leadCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var summaries []mongoModels.ContactSummary
+	if err := cursor.All(ctx, &summaries); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainContactSummarySlice(summaries), nil
+}
+
+func (r *MongoContactRepository) GetLeads(ctx context.Context, key string) ([]models.Lead, error) {
+	cursor, err := r.leadCollection.Find(ctx, bson.M{"contact_key": key}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var leads []mongoModels.Lead
+	if err := cursor.All(ctx, &leads); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadSlice(leads), nil
+}
+
+func (r *MongoContactRepository) GetInquiries(ctx context.Context, key string) ([]models.Inquiry, error) {
+	cursor, err := r.inquiryCollection.Find(ctx, bson.M{"contact_key": key}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var inquiries []mongoModels.Inquiry
+	if err := cursor.All(ctx, &inquiries); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainInquirySlice(inquiries), nil
+}
+
+func (r *MongoContactRepository) GetDealerClients(ctx context.Context, key string) ([]models.DealerClient, error) {
+	cursor, err := r.dealerClientCollection.Find(ctx, bson.M{"contact_key": key}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var clients []mongoModels.DealerClient
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainDealerClientSlice(clients), nil
+}
+
+// GetVisits returns the visits booked for any of the leads or dealer clients, latest first
+func (r *MongoContactRepository) GetVisits(ctx context.Context, leadIDs []string, dealerClientIDs []string) ([]models.Visit, error) {
+	var subjects []bson.M
+	if ids := toObjectIDs(leadIDs); len(ids) > 0 {
+		subjects = append(subjects, bson.M{"lead_id": bson.M{"$in": ids}})
+	}
+	if ids := toObjectIDs(dealerClientIDs); len(ids) > 0 {
+		subjects = append(subjects, bson.M{"dealer_client_id": bson.M{"$in": ids}})
+	}
+	if len(subjects) == 0 {
+		return []models.Visit{}, nil
+	}
+
+	cursor, err := r.visitCollection.Find(ctx, bson.M{"$or": subjects}, options.Find().SetSort(bson.M{"starts_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var visits []mongoModels.Visit
+	if err := cursor.All(ctx, &visits); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainVisitSlice(visits), nil
+}
+
+// GetTasks returns the tasks about any of the leads or dealer clients, latest due first
+func (r *MongoContactRepository) GetTasks(ctx context.Context, leadIDs []string, dealerClientIDs []string) ([]models.Task, error) {
+	var subjects []bson.M
+	if ids := toObjectIDs(leadIDs); len(ids) > 0 {
+		subjects = append(subjects, bson.M{"subject_type": models.TaskSubjectLead, "subject_id": bson.M{"$in": ids}})
+	}
+	if ids := toObjectIDs(dealerClientIDs); len(ids) > 0 {
+		subjects = append(subjects, bson.M{"subject_type": models.TaskSubjectDealerClient, "subject_id": bson.M{"$in": ids}})
+	}
+	if len(subjects) == 0 {
+		return []models.Task{}, nil
+	}
+
+	cursor, err := r.taskCollection.Find(ctx, bson.M{"$or": subjects}, options.Find().SetSort(bson.M{"due_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []mongoModels.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainTaskSlice(tasks), nil
+}
+
+func (r *MongoContactRepository) GetLeadMerges(ctx context.Context, leadIDs []string) ([]models.LeadMerge, error) {
+	objectIDs := toObjectIDs(leadIDs)
+	if len(objectIDs) == 0 {
+		return []models.LeadMerge{}, nil
+	}
+
+	cursor, err := r.mergeCollection.Find(ctx, bson.M{"lead_id": bson.M{"$in": objectIDs}}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var merges []mongoModels.LeadMerge
+	if err := cursor.All(ctx, &merges); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadMergeSlice(merges), nil
+}
+
+// MergeLeads folds the duplicate into the lead in one transaction: the lead is saved as combine
+// returns it, the duplicate's visits, tasks, assignment history and earlier merges move to the
+// lead, and the duplicate is deleted. Both leads are read inside the transaction, so a change
+// made to either meanwhile aborts and retries the merge instead of being overwritten.
+func (r *MongoContactRepository) MergeLeads(ctx context.Context, leadID string, duplicateID string, combine repositories.LeadMergeFunc) (models.Lead, models.LeadMerge, error) {
+	leadObjectID, err := primitive.ObjectIDFromHex(leadID)
+	if err != nil {
+		return models.Lead{}, models.LeadMerge{}, err
+	}
+	duplicateObjectID, err := primitive.ObjectIDFromHex(duplicateID)
+	if err != nil {
+		return models.Lead{}, models.LeadMerge{}, err
+	}
+
+	session, err := r.leadCollection.Database().Client().StartSession()
+	if err != nil {
+		return models.Lead{}, models.LeadMerge{}, err
+	}
+	defer session.EndSession(ctx)
+
+	var merged models.Lead
+	var merge models.LeadMerge
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var lead, duplicate mongoModels.Lead
+		if err := r.leadCollection.FindOne(sc, bson.M{"_id": leadObjectID}).Decode(&lead); err != nil {
+			return nil, err
+		}
+		if err := r.leadCollection.FindOne(sc, bson.M{"_id": duplicateObjectID}).Decode(&duplicate); err != nil {
+			return nil, err
+		}
+
+		combined, record, err := combine(converters.ToDomainLead(lead), converters.ToDomainLead(duplicate))
+		if err != nil {
+			return nil, err
+		}
+
+		interests := make([]mongoModels.PropertyInterest, len(combined.Properties))
+		for i, interest := range combined.Properties {
+			if interests[i], err = converters.ToMongoPropertyInterest(interest); err != nil {
+				return nil, err
+			}
+		}
+		set := bson.M{
+			"requirement":  combined.Requirement,
+			"criteria":     converters.ToMongoRequirementCriteria(combined.Criteria),
+			"aadhar_photo": combined.StoredAadhar.Photo,
+			"properties":   interests,
+		}
+		for key, value := range converters.ToMongoAadharNumberUpdate(combined.StoredAadhar) {
+			set[key] = value
+		}
+		if combined.AssignedDealerID != "" {
+			dealerID, err := primitive.ObjectIDFromHex(combined.AssignedDealerID)
+			if err != nil {
+				return nil, err
+			}
+			set["assigned_dealer_id"] = dealerID
+			set["assigned_at"] = combined.AssignedAt
+		}
+		if _, err := r.leadCollection.UpdateOne(sc, bson.M{"_id": leadObjectID}, bson.M{"$set": set}); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		moves := []struct {
+			collection *mongo.Collection
+			filter     bson.M
+			set        bson.M
+		}{
+			{r.visitCollection, bson.M{"lead_id": duplicateObjectID}, bson.M{"lead_id": leadObjectID, "updated_at": now}},
+			{r.taskCollection, bson.M{"subject_type": models.TaskSubjectLead, "subject_id": duplicateObjectID},
+				bson.M{"subject_id": leadObjectID, "subject_name": combined.Name, "updated_at": now}},
+			{r.assignmentCollection, bson.M{"lead_id": duplicateObjectID}, bson.M{"lead_id": leadObjectID}},
+			{r.mergeCollection, bson.M{"lead_id": duplicateObjectID}, bson.M{"lead_id": leadObjectID}},
+			{r.activityCollection, bson.M{"subject_type": models.ActivitySubjectLead, "subject_id": duplicateObjectID}, bson.M{"subject_id": leadObjectID}},
+		}
+		for _, move := range moves {
+			if _, err := move.collection.UpdateMany(sc, move.filter, bson.M{"$set": move.set}); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := r.leadCollection.DeleteOne(sc, bson.M{"_id": duplicateObjectID}); err != nil {
+			return nil, err
+		}
+
+		record.CreatedAt = now
+		mongoMerge, err := converters.ToMongoLeadMerge(record)
+		if err != nil {
+			return nil, err
+		}
+		inserted, err := r.mergeCollection.InsertOne(sc, mongoMerge)
+		if err != nil {
+			return nil, err
+		}
+		mongoMerge.ID = inserted.InsertedID.(primitive.ObjectID)
+
+		merged, merge = combined, converters.ToDomainLeadMerge(mongoMerge)
+		return nil, nil
+	})
+	if err != nil {
+		return models.Lead{}, models.LeadMerge{}, err
+	}
+	return merged, merge, nil
+}
+
+func toObjectIDs(ids []string) []primitive.ObjectID {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	return objectIDs
+}
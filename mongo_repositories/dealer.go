@@ -7,6 +7,7 @@ import (
 	"myapp/models"
 	mongoModels "myapp/mongo_models"
 	"myapp/repositories"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -221,4 +222,24 @@ func (r *MongoDealerRepository) GetDealerWithProperties(ctx context.Context, sub
 	}
 
 	return results, nil
-}
\ No newline at end of file
+}
+
+func (r *MongoDealerRepository) UpdateWatermark(ctx context.Context, id string, watermark models.WatermarkSettings) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"watermark":  converters.ToMongoWatermark(&watermark),
+		"updated_at": time.Now(),
+	}}
+	result, err := r.dealerCollection.UpdateByID(ctx, objectID, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
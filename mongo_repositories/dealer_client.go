@@ -39,12 +39,21 @@ func (r *MongoDealerClientRepository) Create(ctx context.Context, dealerClient m
 		DealerID:          dealerObjectID,
 		Name:              dealerClient.Name,
 		Phone:             dealerClient.Phone,
+		ContactKey:        utils.NormalizePhone(dealerClient.Phone),
 		Note:              dealerClient.Note,
+		Criteria:          converters.ToMongoRequirementCriteria(dealerClient.Criteria),
 		Docs:              converters.ToMongoDealerClientDocs(dealerClient.Docs),
 		CreatedAt:         time.Now(),
 		UpdatedAt:         time.Now(),
 		PropertyInterests: mongoPropertyInterests,
 	}
+	if dealerClient.SourceInquiryID != "" {
+		inquiryID, err := primitive.ObjectIDFromHex(dealerClient.SourceInquiryID)
+		if err != nil {
+			return "", err
+		}
+		mongoDealerClient.SourceInquiryID = &inquiryID
+	}
 
 	result, err := r.dealerClientCollection.InsertOne(ctx, mongoDealerClient)
 	if err != nil {
@@ -58,12 +67,12 @@ func (r *MongoDealerClientRepository) GetByID(ctx context.Context, id string) (m
 	if err != nil {
 		return models.DealerClient{}, err
 	}
-	var dealerClient models.DealerClient
-	err = r.dealerClientCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&dealerClient)
+	var mongoDealerClient mongoModels.DealerClient
+	err = r.dealerClientCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoDealerClient)
 	if err != nil {
 		return models.DealerClient{}, err
 	}
-	return dealerClient, nil
+	return converters.ToDomainDealerClient(mongoDealerClient), nil
 }
 
 func (r *MongoDealerClientRepository) GetDealerClients(ctx context.Context, params models.DealerClientQueryParams, fields []string) ([]models.DealerClient, error) {
@@ -140,6 +149,9 @@ func (r *MongoDealerClientRepository) Update(ctx context.Context, id string, upd
 
 	mongoUpdate := converters.ToMongoDealerClientUpdate(updates)
 	updateDoc := utils.BuildUpdateDocument(mongoUpdate)
+	if updates.Phone != nil {
+		updateDoc["contact_key"] = utils.NormalizePhone(*updates.Phone)
+	}
 	updateDoc["updated_at"] = time.Now()
 	update := bson.M{"$set": updateDoc}
 	_, err = r.dealerClientCollection.UpdateByID(ctx, objectID, update)
@@ -168,6 +180,10 @@ func (r *MongoDealerClientRepository) CheckPhoneExistsForDealer(ctx context.Cont
 		"dealer_id": dealerObjectID,
 		"phone":     phone,
 	}
+	if key := utils.NormalizePhone(phone); key != "" {
+		delete(filter, "phone")
+		filter["$or"] = []bson.M{{"phone": phone}, {"contact_key": key}}
+	}
 
 	count, err := r.dealerClientCollection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -274,3 +290,18 @@ func (r *MongoDealerClientRepository) DeleteDealerClientPropertyInterest(ctx con
 	_, err = r.dealerClientCollection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"properties": bson.M{"property_id": propertyInterestObjectID}}})
 	return err
 }
+
+// GetFilteredDealerClients returns the newest dealer clients matching filter, up to limit
+func (r *MongoDealerClientRepository) GetFilteredDealerClients(ctx context.Context, filter bson.M, limit int64) ([]models.DealerClient, error) {
+	cursor, err := r.dealerClientCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoDealerClients []mongoModels.DealerClient
+	if err := cursor.All(ctx, &mongoDealerClients); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainDealerClientSlice(mongoDealerClients), nil
+}
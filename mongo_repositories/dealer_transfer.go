@@ -0,0 +1,322 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDealerTransferRepository struct {
+	transferCollection     *mongo.Collection
+	dealerCollection       *mongo.Collection
+	propertyCollection     *mongo.Collection
+	dealerClientCollection *mongo.Collection
+	leadCollection         *mongo.Collection
+	inquiryCollection      *mongo.Collection
+	savedSearchCollection  *mongo.Collection
+	shareLinkCollection    *mongo.Collection
+	assignmentCollection   *mongo.Collection
+	taskCollection         *mongo.Collection
+	visitCollection        *mongo.Collection
+	coBrokeCollection      *mongo.Collection
+}
+
+func NewMongoDealerTransferRepository(transferCollection, dealerCollection, propertyCollection, dealerClientCollection, leadCollection, inquiryCollection, savedSearchCollection, shareLinkCollection, assignmentCollection, taskCollection, visitCollection, coBrokeCollection *mongo.Collection) repositories.DealerTransferRepository {
+	return &MongoDealerTransferRepository{
+		transferCollection:     transferCollection,
+		dealerCollection:       dealerCollection,
+		propertyCollection:     propertyCollection,
+		dealerClientCollection: dealerClientCollection,
+		leadCollection:         leadCollection,
+		inquiryCollection:      inquiryCollection,
+		savedSearchCollection:  savedSearchCollection,
+		shareLinkCollection:    shareLinkCollection,
+		assignmentCollection:   assignmentCollection,
+		taskCollection:         taskCollection,
+		visitCollection:        visitCollection,
+		coBrokeCollection:      coBrokeCollection,
+	}
+}
+
+func (r *MongoDealerTransferRepository) Transfer(ctx context.Context, transfer models.DealerTransfer) (models.DealerTransfer, error) {
+	transfer.CreatedAt = time.Now()
+	mongoTransfer, err := converters.ToMongoDealerTransfer(transfer)
+	if err != nil {
+		return models.DealerTransfer{}, err
+	}
+	from, to := mongoTransfer.FromDealerID, mongoTransfer.ToDealerID
+	now := transfer.CreatedAt
+
+	session, err := r.transferCollection.Database().Client().StartSession()
+	if err != nil {
+		return models.DealerTransfer{}, err
+	}
+	defer session.EndSession(ctx)
+
+	// WithTransaction retries the callback on transient errors, so it rebuilds the counts each time
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		record := mongoTransfer
+
+		// Deactivating first makes a concurrent offboarding of the same dealer conflict here
+		result, err := r.dealerCollection.UpdateOne(sc,
+			bson.M{"_id": from, "deactivated_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"deactivated_at": now, "successor_dealer_id": to, "updated_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, mongo.ErrNoDocuments
+		}
+
+		// Writing to the successor too makes an offboarding of the successor conflict with this
+		// one, so records are never handed to a dealer deactivated at the same moment
+		result, err = r.dealerCollection.UpdateOne(sc,
+			bson.M{"_id": to, "deactivated_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"updated_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, repositories.ErrTransferSuccessorUnavailable
+		}
+
+		moved, err := r.propertyCollection.UpdateMany(sc, bson.M{"dealer_id": from},
+			bson.M{"$set": bson.M{"dealer_id": to, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.Properties = moved.ModifiedCount
+
+		moved, err = r.dealerClientCollection.UpdateMany(sc, bson.M{"dealer_id": from},
+			bson.M{"$set": bson.M{"dealer_id": to, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.DealerClients = moved.ModifiedCount
+
+		moved, err = r.inquiryCollection.UpdateMany(sc, bson.M{"dealer_id": from},
+			bson.M{"$set": bson.M{"dealer_id": to, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.Inquiries = moved.ModifiedCount
+
+		moved, err = r.savedSearchCollection.UpdateMany(sc, bson.M{"dealer_id": from},
+			bson.M{"$set": bson.M{"dealer_id": to, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.SavedSearches = moved.ModifiedCount
+
+		moved, err = r.leadCollection.UpdateMany(sc, bson.M{"properties.dealer_id": from},
+			bson.M{"$set": bson.M{"properties.$[interest].dealer_id": to}},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"interest.dealer_id": from}},
+			}),
+		)
+		if err != nil {
+			return nil, err
+		}
+		record.Leads = moved.ModifiedCount
+
+		assigned, err := r.reassignLeads(sc, from, to, transfer, now)
+		if err != nil {
+			return nil, err
+		}
+		record.AssignedLeads = assigned
+
+		// Task assignees are JWT user IDs, which for dealers are their hex IDs
+		moved, err = r.taskCollection.UpdateMany(sc, bson.M{"assignee_id": from.Hex(), "status": models.TaskStatusOpen},
+			bson.M{"$set": bson.M{"assignee_id": to.Hex(), "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.Tasks = moved.ModifiedCount
+
+		moved, err = r.visitCollection.UpdateMany(sc, bson.M{"dealer_id": from},
+			bson.M{"$set": bson.M{"dealer_id": to, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.Visits = moved.ModifiedCount
+
+		coBrokes, err := r.transferCoBrokes(sc, from, to, now)
+		if err != nil {
+			return nil, err
+		}
+		record.CoBrokeAgreements = coBrokes
+
+		moved, err = r.shareLinkCollection.UpdateMany(sc, bson.M{"dealer_id": from, "revoked": false},
+			bson.M{"$set": bson.M{"revoked": true, "revoked_at": now, "updated_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		record.RevokedShareLinks = moved.ModifiedCount
+
+		inserted, err := r.transferCollection.InsertOne(sc, record)
+		if err != nil {
+			return nil, err
+		}
+		record.ID = inserted.InsertedID.(primitive.ObjectID)
+		mongoTransfer = record
+		return nil, nil
+	})
+	if err != nil {
+		return models.DealerTransfer{}, err
+	}
+
+	return converters.ToDomainDealerTransfer(mongoTransfer), nil
+}
+
+// transferCoBrokes hands the departing dealer's side of every co-broke agreement to the
+// successor. Open agreements that would leave the successor co-broking with itself, or holding
+// two open requests for the same listing, are terminated instead.
+func (r *MongoDealerTransferRepository) transferCoBrokes(sc mongo.SessionContext, from, to primitive.ObjectID, now time.Time) (int64, error) {
+	involved, err := r.coBrokeCollection.CountDocuments(sc,
+		bson.M{"$or": []bson.M{{"listing_dealer_id": from}, {"partner_dealer_id": from}}})
+	if err != nil || involved == 0 {
+		return 0, err
+	}
+
+	open := bson.M{"$in": models.OpenCoBrokeStatuses}
+	duplicates, err := r.coBrokeCollection.Distinct(sc, "property_id", bson.M{"partner_dealer_id": to, "status": open})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = r.coBrokeCollection.UpdateMany(sc, bson.M{
+		"status": open,
+		"$or": []bson.M{
+			{"listing_dealer_id": from, "partner_dealer_id": to},
+			{"listing_dealer_id": to, "partner_dealer_id": from},
+			{"partner_dealer_id": from, "property_id": bson.M{"$in": duplicates}},
+		},
+	}, bson.M{"$set": bson.M{
+		"status":       models.CoBrokeStatusTerminated,
+		"closing_note": "Closed when the dealer was offboarded",
+		"closed_at":    now,
+		"updated_at":   now,
+	}})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := r.coBrokeCollection.UpdateMany(sc, bson.M{"listing_dealer_id": from},
+		bson.M{"$set": bson.M{"listing_dealer_id": to, "updated_at": now}}); err != nil {
+		return 0, err
+	}
+	if _, err := r.coBrokeCollection.UpdateMany(sc, bson.M{"partner_dealer_id": from},
+		bson.M{"$set": bson.M{"partner_dealer_id": to, "updated_at": now}}); err != nil {
+		return 0, err
+	}
+	return involved, nil
+}
+
+// reassignLeads gives the leads the departing dealer owned to the successor and records each
+// change in the leads' assignment history
+func (r *MongoDealerTransferRepository) reassignLeads(sc mongo.SessionContext, from, to primitive.ObjectID, transfer models.DealerTransfer, now time.Time) (int64, error) {
+	cursor, err := r.leadCollection.Find(sc, bson.M{"assigned_dealer_id": from},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var leads []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(sc, &leads); err != nil {
+		return 0, err
+	}
+	if len(leads) == 0 {
+		return 0, nil
+	}
+
+	leadIDs := make([]primitive.ObjectID, len(leads))
+	history := make([]interface{}, len(leads))
+	for i, lead := range leads {
+		leadIDs[i] = lead.ID
+		history[i] = mongoModels.LeadAssignment{
+			LeadID:       lead.ID,
+			FromDealerID: &from,
+			ToDealerID:   &to,
+			Method:       models.LeadAssignmentTransfer,
+			AssignedBy:   transfer.PerformedBy,
+			Note:         transfer.Reason,
+			CreatedAt:    now,
+		}
+	}
+
+	moved, err := r.leadCollection.UpdateMany(sc, bson.M{"_id": bson.M{"$in": leadIDs}},
+		bson.M{"$set": bson.M{"assigned_dealer_id": to, "assigned_at": now, "updated_at": now}})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.assignmentCollection.InsertMany(sc, history); err != nil {
+		return 0, err
+	}
+	return moved.ModifiedCount, nil
+}
+
+func (r *MongoDealerTransferRepository) GetByDealer(ctx context.Context, dealerID string) ([]models.DealerTransfer, error) {
+	objectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.transferCollection.Find(ctx,
+		bson.M{"$or": []bson.M{{"from_dealer_id": objectID}, {"to_dealer_id": objectID}}},
+		options.Find().SetSort(bson.M{"created_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transfers []mongoModels.DealerTransfer
+	if err := cursor.All(ctx, &transfers); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainDealerTransferSlice(transfers), nil
+}
+
+func (r *MongoDealerTransferRepository) CountOwned(ctx context.Context, dealerID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := []struct {
+		collection *mongo.Collection
+		filter     bson.M
+	}{
+		{r.propertyCollection, bson.M{"dealer_id": objectID}},
+		{r.dealerClientCollection, bson.M{"dealer_id": objectID}},
+		{r.inquiryCollection, bson.M{"dealer_id": objectID}},
+		{r.savedSearchCollection, bson.M{"dealer_id": objectID}},
+		{r.leadCollection, bson.M{"properties.dealer_id": objectID}},
+		{r.leadCollection, bson.M{"assigned_dealer_id": objectID}},
+		{r.taskCollection, bson.M{"assignee_id": dealerID, "status": models.TaskStatusOpen}},
+		{r.visitCollection, bson.M{"dealer_id": objectID, "status": bson.M{"$in": models.ActiveVisitStatuses}}},
+		{r.coBrokeCollection, bson.M{"listing_dealer_id": objectID, "status": bson.M{"$in": models.OpenCoBrokeStatuses}}},
+		{r.coBrokeCollection, bson.M{"partner_dealer_id": objectID, "status": bson.M{"$in": models.OpenCoBrokeStatuses}}},
+	}
+
+	var total int64
+	for _, count := range counts {
+		n, err := count.collection.CountDocuments(ctx, count.filter)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,60 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDocumentAccessLogRepository is append-only; entries are never updated or deleted
+type MongoDocumentAccessLogRepository struct {
+	accessLogCollection *mongo.Collection
+}
+
+func NewMongoDocumentAccessLogRepository(accessLogCollection *mongo.Collection) repositories.DocumentAccessLogRepository {
+	return &MongoDocumentAccessLogRepository{
+		accessLogCollection: accessLogCollection,
+	}
+}
+
+func (r *MongoDocumentAccessLogRepository) Create(ctx context.Context, entry models.DocumentAccessLog) error {
+	if entry.AccessedAt.IsZero() {
+		entry.AccessedAt = time.Now()
+	}
+
+	mongoEntry, err := converters.ToMongoDocumentAccessLog(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.accessLogCollection.InsertOne(ctx, mongoEntry)
+	return err
+}
+
+func (r *MongoDocumentAccessLogRepository) GetByClient(ctx context.Context, clientID string, limit int64) ([]models.DocumentAccessLog, error) {
+	clientObjectID, err := primitive.ObjectIDFromHex(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.M{"accessed_at": -1}).SetLimit(limit)
+	cursor, err := r.accessLogCollection.Find(ctx, bson.M{"client_id": clientObjectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []mongoModels.DocumentAccessLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainDocumentAccessLogSlice(entries), nil
+}
@@ -95,6 +95,9 @@ func (r *MongoInquiryRepository) Update(ctx context.Context, id string, updates
 
 	mongoUpdate := converters.ToMongoInquiryUpdate(updates)
 	updateDoc := utils.BuildUpdateDocument(mongoUpdate)
+	if updates.Phone != nil {
+		updateDoc["contact_key"] = utils.NormalizePhone(*updates.Phone)
+	}
 
 	_, err = r.inquiryCollection.UpdateByID(ctx, objectID, bson.M{"$set": updateDoc})
 	return err
@@ -116,3 +119,80 @@ func getSortOrder(order string) int {
 	}
 	return -1
 }
+
+// GetFilteredInquiries returns the newest inquiries matching filter, up to limit
+func (r *MongoInquiryRepository) GetFilteredInquiries(ctx context.Context, filter bson.M, limit int64) ([]models.Inquiry, error) {
+	cursor, err := r.inquiryCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoInquiries []mongoModels.Inquiry
+	if err := cursor.All(ctx, &mongoInquiries); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainInquirySlice(mongoInquiries), nil
+}
+
+func (r *MongoInquiryRepository) ClaimConversion(ctx context.Context, id string, convertedTo string, convertedBy string, at time.Time, staleBefore time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objectID, "$or": []bson.M{
+		{"converted": bson.M{"$ne": true}},
+		{"converted_id": bson.M{"$exists": false}, "converted_at": bson.M{"$lt": staleBefore}},
+	}}
+	result, err := r.inquiryCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"converted":    true,
+		"converted_to": convertedTo,
+		"converted_by": convertedBy,
+		"converted_at": at,
+		"updated_at":   at,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoInquiryRepository) CompleteConversion(ctx context.Context, id string, convertedID string) (models.Inquiry, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Inquiry{}, err
+	}
+	convertedObjectID, err := primitive.ObjectIDFromHex(convertedID)
+	if err != nil {
+		return models.Inquiry{}, err
+	}
+
+	var mongoInquiry mongoModels.Inquiry
+	err = r.inquiryCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "converted": true},
+		bson.M{"$set": bson.M{"converted_id": convertedObjectID}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&mongoInquiry)
+	if err != nil {
+		return models.Inquiry{}, err
+	}
+	return converters.ToDomainInquiry(mongoInquiry), nil
+}
+
+func (r *MongoInquiryRepository) ReleaseConversion(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objectID, "converted_id": bson.M{"$exists": false}}
+	_, err = r.inquiryCollection.UpdateOne(ctx, filter, bson.M{
+		"$unset": bson.M{"converted": "", "converted_to": "", "converted_by": "", "converted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
@@ -33,9 +33,22 @@ func (r *MongoLeadRepository) Create(ctx context.Context, lead models.Lead) (str
 	mongoLead := mongoModels.Lead{
 		Name:         lead.Name,
 		Phone:        lead.Phone,
+		ContactKey:   utils.NormalizePhone(lead.Phone),
 		Requirement:  lead.Requirement,
-		AadharNumber: lead.AadharNumber,
-		AadharPhoto:  lead.AadharPhoto,
+		Criteria:     converters.ToMongoRequirementCriteria(lead.Criteria),
+		AadharNumber: lead.StoredAadhar.Ciphertext,
+		AadharKeyVersion: lead.StoredAadhar.KeyVersion,
+		AadharLast4:      lead.StoredAadhar.Last4,
+		AadharHash:       lead.StoredAadhar.Hash,
+		AadharHashKey:    lead.StoredAadhar.HashKeyID,
+		AadharPhoto:      lead.StoredAadhar.Photo,
+	}
+	if lead.SourceInquiryID != "" {
+		inquiryID, err := primitive.ObjectIDFromHex(lead.SourceInquiryID)
+		if err != nil {
+			return "", err
+		}
+		mongoLead.SourceInquiryID = &inquiryID
 	}
 
 	res, err := r.leadCollection.InsertOne(ctx, mongoLead)
@@ -62,15 +75,7 @@ func (r *MongoLeadRepository) GetByID(ctx context.Context, id string) (models.Le
 		return models.Lead{}, err
 	}
 
-	// Convert mongoModels.Lead to models.LeadData
-	return models.Lead{
-		ID:           mongoLead.ID.Hex(),
-		Name:         mongoLead.Name,
-		Phone:        mongoLead.Phone,
-		Requirement:  mongoLead.Requirement,
-		AadharNumber: mongoLead.AadharNumber,
-		AadharPhoto:  mongoLead.AadharPhoto,
-	}, nil
+	return converters.ToDomainLead(mongoLead), nil
 }
 
 func (r *MongoLeadRepository) GetAll(ctx context.Context) ([]models.Lead, error) {
@@ -84,19 +89,7 @@ func (r *MongoLeadRepository) GetAll(ctx context.Context) ([]models.Lead, error)
 		return nil, err
 	}
 
-	// Convert mongoModels.Lead to models.LeadData
-	var leads []models.Lead
-	for _, mongoLead := range mongoLeads {
-		leads = append(leads, models.Lead{
-			ID:           mongoLead.ID.Hex(),
-			Name:         mongoLead.Name,
-			Phone:        mongoLead.Phone,
-			Requirement:  mongoLead.Requirement,
-			AadharNumber: mongoLead.AadharNumber,
-			AadharPhoto:  mongoLead.AadharPhoto,
-		})
-	}
-	return leads, nil
+	return converters.ToDomainLeadSlice(mongoLeads), nil
 }
 
 func (r *MongoLeadRepository) GetByDealerID(ctx context.Context, dealerID string) ([]models.Lead, error) {
@@ -115,19 +108,7 @@ func (r *MongoLeadRepository) GetByDealerID(ctx context.Context, dealerID string
 		return nil, err
 	}
 
-	// Convert mongoModels.Lead to models.LeadData
-	var leads []models.Lead
-	for _, mongoLead := range mongoLeads {
-		leads = append(leads, models.Lead{
-			ID:           mongoLead.ID.Hex(),
-			Name:         mongoLead.Name,
-			Phone:        mongoLead.Phone,
-			Requirement:  mongoLead.Requirement,
-			AadharNumber: mongoLead.AadharNumber,
-			AadharPhoto:  mongoLead.AadharPhoto,
-		})
-	}
-	return leads, nil
+	return converters.ToDomainLeadSlice(mongoLeads), nil
 }
 
 func (r *MongoLeadRepository) GetLeads(ctx context.Context, params models.LeadQueryParams) ([]models.Lead, error) {
@@ -199,6 +180,9 @@ func (r *MongoLeadRepository) Update(ctx context.Context, id string, updates map
 		return err
 	}
 
+	if phone, ok := updates["phone"].(string); ok {
+		updates["contact_key"] = utils.NormalizePhone(phone)
+	}
 	update := bson.M{"$set": updates}
 	_, err = r.leadCollection.UpdateByID(ctx, objectID, update)
 	return err
@@ -237,6 +221,12 @@ func (r *MongoLeadRepository) AddPropertyInterest(ctx context.Context, leadID st
 		DealerID:   dealerObjectID,
 		Status:     propertyInterest.Status,
 		Note:       propertyInterest.Note,
+		StatusChangedAt: propertyInterest.StatusChangedAt,
+		CreatedAt:       propertyInterest.CreatedAt,
+		UpdatedAt:       propertyInterest.UpdatedAt,
+	}
+	for _, change := range propertyInterest.History {
+		mongoPropertyInterest.History = append(mongoPropertyInterest.History, converters.ToMongoPropertyInterestStatusChange(change))
 	}
 
 	// Check if property already exists for this lead
@@ -264,50 +254,219 @@ func (r *MongoLeadRepository) AddPropertyInterest(ctx context.Context, leadID st
 	return err
 }
 
-func (r *MongoLeadRepository) UpdatePropertyInterest(ctx context.Context, leadID, propertyID string, status, note string) error {
+// GetPropertyInterest returns the lead's interest in the property
+func (r *MongoLeadRepository) GetPropertyInterest(ctx context.Context, leadID, propertyID string) (models.PropertyInterest, error) {
 	leadObjectID, err := primitive.ObjectIDFromHex(leadID)
 	if err != nil {
-		return err
+		return models.PropertyInterest{}, err
+	}
+	propertyObjectID, err := primitive.ObjectIDFromHex(propertyID)
+	if err != nil {
+		return models.PropertyInterest{}, err
+	}
+
+	var mongoLead mongoModels.Lead
+	err = r.leadCollection.FindOne(ctx,
+		bson.M{"_id": leadObjectID, "properties.property_id": propertyObjectID},
+		options.FindOne().SetProjection(bson.M{"properties.$": 1}),
+	).Decode(&mongoLead)
+	if err != nil {
+		return models.PropertyInterest{}, err
+	}
+	if len(mongoLead.Properties) == 0 {
+		return models.PropertyInterest{}, mongo.ErrNoDocuments
 	}
+	return converters.ToDomainPropertyInterest(leadObjectID, mongoLead.Properties[0]), nil
+}
 
+// ChangePropertyInterestStatus moves the interest along the pipeline and appends the change to
+// its history, provided it is still in change.From. mongo.ErrNoDocuments means the interest is
+// gone or its status changed in the meantime.
+func (r *MongoLeadRepository) ChangePropertyInterestStatus(ctx context.Context, leadID, propertyID string, change models.PropertyInterestStatusChange) error {
+	leadObjectID, err := primitive.ObjectIDFromHex(leadID)
+	if err != nil {
+		return err
+	}
 	propertyObjectID, err := primitive.ObjectIDFromHex(propertyID)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{
-		"_id":                    leadObjectID,
-		"properties.property_id": propertyObjectID,
+	set := bson.M{
+		"properties.$.status":            change.To,
+		"properties.$.status_changed_at": change.ChangedAt,
+		"properties.$.updated_at":        change.ChangedAt,
+		"properties.$.lost_reason":       change.Reason,
+	}
+	if change.Note != "" {
+		set["properties.$.note"] = change.Note
+	}
+	if change.SoldPrice > 0 {
+		set["properties.$.sold_price"] = change.SoldPrice
 	}
 
+	filter := bson.M{
+		"_id":        leadObjectID,
+		"properties": bson.M{"$elemMatch": bson.M{"property_id": propertyObjectID, "status": change.From}},
+	}
 	update := bson.M{
-		"$set": bson.M{
-			"properties.$.status": status,
-			"properties.$.note":   note,
-		},
+		"$set":  set,
+		"$push": bson.M{"properties.$.history": converters.ToMongoPropertyInterestStatusChange(change)},
 	}
 
 	result, err := r.leadCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
-
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
 		return mongo.ErrNoDocuments
 	}
-
 	return nil
 }
 
+func (r *MongoLeadRepository) CheckPhoneExists(ctx context.Context, phone string) (bool, error) {
+	filter := bson.M{"phone": phone}
+	if key := utils.NormalizePhone(phone); key != "" {
+		filter = bson.M{"$or": []bson.M{{"phone": phone}, {"contact_key": key}}}
+	}
+	count, err := r.leadCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
 
+// FindByPhone returns the lead with this phone number in any format, or mongo.ErrNoDocuments
+func (r *MongoLeadRepository) FindByPhone(ctx context.Context, phone string) (models.Lead, error) {
+	filter := bson.M{"phone": phone}
+	if key := utils.NormalizePhone(phone); key != "" {
+		filter = bson.M{"$or": []bson.M{{"phone": phone}, {"contact_key": key}}}
+	}
+	var mongoLead mongoModels.Lead
+	if err := r.leadCollection.FindOne(ctx, filter).Decode(&mongoLead); err != nil {
+		return models.Lead{}, err
+	}
+	return converters.ToDomainLead(mongoLead), nil
+}
 
+// GetFilteredLeads returns the newest leads matching filter, up to limit
+func (r *MongoLeadRepository) GetFilteredLeads(ctx context.Context, filter bson.M, limit int64) ([]models.Lead, error) {
+	cursor, err := r.leadCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
+	var mongoLeads []mongoModels.Lead
+	if err := cursor.All(ctx, &mongoLeads); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadSlice(mongoLeads), nil
+}
 
+// CountAssigned returns how many open leads each of the dealers owns; dealers with none are absent
+func (r *MongoLeadRepository) CountAssigned(ctx context.Context, dealerIDs []string) (map[string]int64, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(dealerIDs))
+	for _, id := range dealerIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
 
-func (r *MongoLeadRepository) CheckPhoneExists(ctx context.Context, phone string) (bool, error) {
-	count, err := r.leadCollection.CountDocuments(ctx, bson.M{"phone": phone})
+	counts := make(map[string]int64, len(dealerIDs))
+	if len(objectIDs) == 0 {
+		return counts, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: openAssignedLeadsFilter(bson.M{"$in": objectIDs})}},
+		{{Key: "$group", Value: bson.M{"_id": "$assigned_dealer_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.leadCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		DealerID primitive.ObjectID `bson:"_id"`
+		Count    int64              `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		counts[result.DealerID.Hex()] = result.Count
+	}
+	return counts, nil
+}
+
+// openAssignedLeadsFilter matches the leads owned by dealer that are still being worked: those
+// with no property interests yet or with at least one interest in an open status
+func openAssignedLeadsFilter(dealer interface{}) bson.M {
+	return bson.M{
+		"assigned_dealer_id": dealer,
+		"$or": []bson.M{
+			{"properties.0": bson.M{"$exists": false}},
+			{"properties.status": bson.M{"$in": models.OpenLeadStatuses}},
+		},
+	}
+}
+
+func (r *MongoLeadRepository) GetStaleAadhar(ctx context.Context, keyVersion int, hashKeyID string, afterID string, limit int64) ([]models.Lead, error) {
+	filter := bson.M{
+		"aadhar_number": bson.M{"$nin": bson.A{nil, ""}},
+		"$or": []bson.M{
+			{"aadhar_key_version": bson.M{"$ne": keyVersion}},
+			{"aadhar_hash_key": bson.M{"$ne": hashKeyID}},
+		},
+	}
+	if afterID != "" {
+		objectID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": objectID}
+	}
+	opts := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetProjection(bson.M{"aadhar_number": 1, "aadhar_key_version": 1, "aadhar_last4": 1, "aadhar_hash": 1, "aadhar_hash_key": 1}).
+		SetLimit(limit)
+	cursor, err := r.leadCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoLeads []mongoModels.Lead
+	if err := cursor.All(ctx, &mongoLeads); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadSlice(mongoLeads), nil
+}
+
+func (r *MongoLeadRepository) HasAadharNumbers(ctx context.Context) (bool, error) {
+	count, err := r.leadCollection.CountDocuments(ctx, bson.M{"aadhar_number": bson.M{"$nin": bson.A{nil, ""}}}, options.Count().SetLimit(1))
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+func (r *MongoLeadRepository) SetAadharNumber(ctx context.Context, id string, stored models.StoredAadhar, previous string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.leadCollection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "aadhar_number": previous},
+		bson.M{"$set": converters.ToMongoAadharNumberUpdate(stored)},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
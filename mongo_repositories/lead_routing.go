@@ -0,0 +1,251 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLeadRoutingRepository keeps the routing rules and the leads' assignment history. Each
+// dealer with a capacity has a document in lockCollection that assignments to them bump, so
+// concurrent assignments to the same dealer conflict and are retried against the new count.
+type MongoLeadRoutingRepository struct {
+	ruleCollection       *mongo.Collection
+	assignmentCollection *mongo.Collection
+	leadCollection       *mongo.Collection
+	lockCollection       *mongo.Collection
+}
+
+func NewMongoLeadRoutingRepository(ruleCollection, assignmentCollection, leadCollection, lockCollection *mongo.Collection) repositories.LeadRoutingRepository {
+	return &MongoLeadRoutingRepository{
+		ruleCollection:       ruleCollection,
+		assignmentCollection: assignmentCollection,
+		leadCollection:       leadCollection,
+		lockCollection:       lockCollection,
+	}
+}
+
+func (r *MongoLeadRoutingRepository) CreateRule(ctx context.Context, rule models.LeadRoutingRule) (models.LeadRoutingRule, error) {
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	mongoRule, err := converters.ToMongoLeadRoutingRule(rule)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+
+	result, err := r.ruleCollection.InsertOne(ctx, mongoRule)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+
+	mongoRule.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainLeadRoutingRule(mongoRule), nil
+}
+
+func (r *MongoLeadRoutingRepository) GetRule(ctx context.Context, id string) (models.LeadRoutingRule, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+
+	var mongoRule mongoModels.LeadRoutingRule
+	if err := r.ruleCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoRule); err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+	return converters.ToDomainLeadRoutingRule(mongoRule), nil
+}
+
+// GetRules lists rules in the order they are tried: by priority, older rules first on a tie
+func (r *MongoLeadRoutingRepository) GetRules(ctx context.Context, activeOnly bool) ([]models.LeadRoutingRule, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.ruleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoRules []mongoModels.LeadRoutingRule
+	if err := cursor.All(ctx, &mongoRules); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadRoutingRuleSlice(mongoRules), nil
+}
+
+// UpdateRule replaces the editable fields of the rule; its round-robin position is kept
+func (r *MongoLeadRoutingRepository) UpdateRule(ctx context.Context, rule models.LeadRoutingRule) (models.LeadRoutingRule, error) {
+	objectID, err := primitive.ObjectIDFromHex(rule.ID)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+	mongoRule, err := converters.ToMongoLeadRoutingRule(rule)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":            mongoRule.Name,
+		"priority":        mongoRule.Priority,
+		"active":          mongoRule.Active,
+		"locations":       mongoRule.Locations,
+		"sub_locations":   mongoRule.SubLocations,
+		"min_budget":      mongoRule.MinBudget,
+		"max_budget":      mongoRule.MaxBudget,
+		"dealer_ids":      mongoRule.DealerIDs,
+		"strategy":        mongoRule.Strategy,
+		"dealer_capacity": mongoRule.DealerCapacity,
+		"updated_at":      time.Now(),
+	}}
+
+	var updated mongoModels.LeadRoutingRule
+	err = r.ruleCollection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+	return converters.ToDomainLeadRoutingRule(updated), nil
+}
+
+func (r *MongoLeadRoutingRepository) DeleteRule(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.ruleCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// NextTurn advances the rule's round-robin position and returns the position taken, starting
+// at zero. Concurrent callers each get a different turn.
+func (r *MongoLeadRoutingRepository) NextTurn(ctx context.Context, ruleID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(ruleID)
+	if err != nil {
+		return 0, err
+	}
+
+	var updated mongoModels.LeadRoutingRule
+	err = r.ruleCollection.FindOneAndUpdate(ctx, bson.M{"_id": objectID},
+		bson.M{"$inc": bson.M{"turn": 1}},
+		options.FindOneAndUpdate().
+			SetReturnDocument(options.After).
+			SetProjection(bson.M{"turn": 1}),
+	).Decode(&updated)
+	if err != nil {
+		return 0, err
+	}
+	return updated.Turn - 1, nil
+}
+
+func (r *MongoLeadRoutingRepository) Assign(ctx context.Context, assignment models.LeadAssignment, capacity int) (models.LeadAssignment, error) {
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = time.Now()
+	}
+	mongoAssignment, err := converters.ToMongoLeadAssignment(assignment)
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	now := assignment.CreatedAt
+
+	// The lead moves only if it still belongs to the dealer the caller saw
+	filter := bson.M{"_id": mongoAssignment.LeadID, "assigned_dealer_id": bson.M{"$exists": false}}
+	if mongoAssignment.FromDealerID != nil {
+		filter["assigned_dealer_id"] = *mongoAssignment.FromDealerID
+	}
+	update := bson.M{"$unset": bson.M{"assigned_dealer_id": "", "assigned_at": ""}, "$set": bson.M{"updated_at": now}}
+	if mongoAssignment.ToDealerID != nil {
+		update = bson.M{"$set": bson.M{"assigned_dealer_id": *mongoAssignment.ToDealerID, "assigned_at": now, "updated_at": now}}
+	}
+
+	session, err := r.leadCollection.Database().Client().StartSession()
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if mongoAssignment.ToDealerID != nil && capacity > 0 {
+			to := *mongoAssignment.ToDealerID
+			_, err := r.lockCollection.UpdateOne(sc, bson.M{"_id": to.Hex()},
+				bson.M{"$inc": bson.M{"assignments": 1}}, options.Update().SetUpsert(true))
+			if err != nil {
+				return nil, err
+			}
+			open, err := r.leadCollection.CountDocuments(sc, openAssignedLeadsFilter(to))
+			if err != nil {
+				return nil, err
+			}
+			if open >= int64(capacity) {
+				return nil, repositories.ErrDealerAtCapacity
+			}
+		}
+
+		result, err := r.leadCollection.UpdateOne(sc, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			exists, err := r.leadCollection.CountDocuments(sc, bson.M{"_id": mongoAssignment.LeadID})
+			if err != nil {
+				return nil, err
+			}
+			if exists == 0 {
+				return nil, mongo.ErrNoDocuments
+			}
+			return nil, repositories.ErrLeadOwnerChanged
+		}
+
+		inserted, err := r.assignmentCollection.InsertOne(sc, mongoAssignment)
+		if err != nil {
+			return nil, err
+		}
+		mongoAssignment.ID = inserted.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	return converters.ToDomainLeadAssignment(mongoAssignment), nil
+}
+
+// GetAssignments returns the lead's assignment history, oldest first
+func (r *MongoLeadRoutingRepository) GetAssignments(ctx context.Context, leadID string) ([]models.LeadAssignment, error) {
+	objectID, err := primitive.ObjectIDFromHex(leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.assignmentCollection.Find(ctx, bson.M{"lead_id": objectID},
+		options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoAssignments []mongoModels.LeadAssignment
+	if err := cursor.All(ctx, &mongoAssignments); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainLeadAssignmentSlice(mongoAssignments), nil
+}
@@ -0,0 +1,253 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoMediaRepository struct {
+	mediaCollection *mongo.Collection
+	quotaCollection *mongo.Collection
+}
+
+func NewMongoMediaRepository(mediaCollection *mongo.Collection, quotaCollection *mongo.Collection) repositories.MediaRepository {
+	return &MongoMediaRepository{
+		mediaCollection: mediaCollection,
+		quotaCollection: quotaCollection,
+	}
+}
+
+func (r *MongoMediaRepository) GetByKey(ctx context.Context, key string) (models.Media, error) {
+	var mongoMedia mongoModels.Media
+	err := r.mediaCollection.FindOne(ctx, bson.M{"key": key}).Decode(&mongoMedia)
+	if err != nil {
+		return models.Media{}, err
+	}
+	return converters.ToDomainMedia(mongoMedia), nil
+}
+
+func (r *MongoMediaRepository) GetByKeys(ctx context.Context, keys []string) ([]models.Media, error) {
+	cursor, err := r.mediaCollection.Find(ctx, bson.M{"key": bson.M{"$in": keys}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoMedia []mongoModels.Media
+	if err := cursor.All(ctx, &mongoMedia); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainMediaSlice(mongoMedia), nil
+}
+
+func (r *MongoMediaRepository) Create(ctx context.Context, media []models.Media) error {
+	if len(media) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(media))
+	for i, item := range media {
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		mongoMedia, err := converters.ToMongoMedia(item)
+		if err != nil {
+			return err
+		}
+		documents[i] = mongoMedia
+	}
+
+	_, err := r.mediaCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// Confirm marks a pending record as uploaded, storing what storage actually holds
+func (r *MongoMediaRepository) Confirm(ctx context.Context, key string, contentType string, size int64) error {
+	result, err := r.mediaCollection.UpdateOne(
+		ctx,
+		bson.M{"key": key},
+		bson.M{
+			"$set": bson.M{
+				"status":       models.MediaStatusConfirmed,
+				"content_type": contentType,
+				"size":         size,
+				"updated_at":   time.Now(),
+			},
+			"$unset": bson.M{"upload_id": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeletePending removes a reservation whose upload was abandoned and releases its quota; confirmed media is left alone
+func (r *MongoMediaRepository) DeletePending(ctx context.Context, key string) error {
+	return r.deleteAndRelease(ctx, bson.M{"key": key, "status": models.MediaStatusPending})
+}
+
+// GetExpiredPending returns single-PUT reservations created before the cutoff that were never confirmed
+func (r *MongoMediaRepository) GetExpiredPending(ctx context.Context, before time.Time, limit int64) ([]models.Media, error) {
+	filter := bson.M{
+		"status":     models.MediaStatusPending,
+		"upload_id":  bson.M{"$exists": false},
+		"created_at": bson.M{"$lt": before},
+	}
+	cursor, err := r.mediaCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoMedia []mongoModels.Media
+	if err := cursor.All(ctx, &mongoMedia); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainMediaSlice(mongoMedia), nil
+}
+
+// Reserve adds files and bytes to an owner's usage for a purpose in one conditional update,
+// reporting false when the result would exceed maxFiles or maxBytes. The usage document's _id is
+// derived from owner and purpose, so a failed condition surfaces as a duplicate key on the upsert.
+func (r *MongoMediaRepository) Reserve(ctx context.Context, ownerID string, purpose string, files int64, bytes int64, maxFiles int64, maxBytes int64) (bool, error) {
+	filter := bson.M{
+		"_id":   quotaID(ownerID, purpose),
+		"count": bson.M{"$lte": maxFiles - files},
+		"bytes": bson.M{"$lte": maxBytes - bytes},
+	}
+	update := bson.M{
+		"$inc": bson.M{"count": files, "bytes": bytes},
+		"$set": bson.M{"owner_id": ownerID, "purpose": purpose, "updated_at": time.Now()},
+	}
+
+	_, err := r.quotaCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	// Either the quota is full or a concurrent first reservation created the document; retry
+	// against the existing document only
+	result, err := r.quotaCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Release gives back usage taken by Reserve for uploads that were never recorded or were removed
+func (r *MongoMediaRepository) Release(ctx context.Context, ownerID string, purpose string, files int64, bytes int64) error {
+	_, err := r.quotaCollection.UpdateOne(ctx, bson.M{"_id": quotaID(ownerID, purpose)}, bson.M{
+		"$inc": bson.M{"count": -files, "bytes": -bytes},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
+
+// GetUsageByOwner totals confirmed media per owner and purpose; an empty ownerID covers every owner
+func (r *MongoMediaRepository) GetUsageByOwner(ctx context.Context, ownerID string) ([]models.MediaUsage, error) {
+	match := bson.M{"status": models.MediaStatusConfirmed}
+	if ownerID != "" {
+		match["owner_id"] = ownerID
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"owner_id": "$owner_id", "purpose": "$purpose"},
+			"count": bson.M{"$sum": 1},
+			"bytes": bson.M{"$sum": "$size"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"bytes": -1}}},
+	}
+
+	cursor, err := r.mediaCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID struct {
+			OwnerID string `bson:"owner_id"`
+			Purpose string `bson:"purpose"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+		Bytes int64 `bson:"bytes"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	usage := make([]models.MediaUsage, len(results))
+	for i, result := range results {
+		usage[i] = models.MediaUsage{
+			OwnerID: result.ID.OwnerID,
+			Purpose: result.ID.Purpose,
+			Count:   result.Count,
+			Bytes:   result.Bytes,
+		}
+	}
+	return usage, nil
+}
+
+// GetCreatedBefore pages through media created before a cutoff in _id order, starting after afterID
+func (r *MongoMediaRepository) GetCreatedBefore(ctx context.Context, before time.Time, afterID string, limit int64) ([]models.Media, error) {
+	filter := bson.M{"created_at": bson.M{"$lt": before}}
+	if afterID != "" {
+		afterObjectID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": afterObjectID}
+	}
+
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(limit)
+	cursor, err := r.mediaCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoMedia []mongoModels.Media
+	if err := cursor.All(ctx, &mongoMedia); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainMediaSlice(mongoMedia), nil
+}
+
+// DeleteByKey removes an upload record and releases its quota
+func (r *MongoMediaRepository) DeleteByKey(ctx context.Context, key string) error {
+	return r.deleteAndRelease(ctx, bson.M{"key": key})
+}
+
+func (r *MongoMediaRepository) deleteAndRelease(ctx context.Context, filter bson.M) error {
+	var deleted mongoModels.Media
+	err := r.mediaCollection.FindOneAndDelete(ctx, filter).Decode(&deleted)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.Release(ctx, deleted.OwnerID, deleted.Purpose, 1, deleted.Size)
+}
+
+func quotaID(ownerID string, purpose string) string {
+	return ownerID + "/" + purpose
+}
@@ -0,0 +1,142 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/models"
+	"myapp/repositories"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mediaReferenceField is a field that can hold an object key, either bare or at the end of a
+// public URL. Path is dotted and may pass through arrays.
+type mediaReferenceField struct {
+	Collection string
+	Path       string
+}
+
+// Every field that stores an object key must be listed here, or the orphan sweeper will delete
+// the objects it points at
+var mediaReferenceFields = []mediaReferenceField{
+	{Collection: "properties", Path: "photos.key"},
+	{Collection: "properties", Path: "photos.url"},
+	{Collection: "properties", Path: "photos.variants." + models.PhotoVariantThumbnail},
+	{Collection: "properties", Path: "photos.variants." + models.PhotoVariantMedium},
+	{Collection: "properties", Path: "photos.variants." + models.PhotoVariantLarge},
+	{Collection: "properties", Path: "videos"},
+	{Collection: "dealer_clients", Path: "docs.key"},
+	{Collection: "dealer_clients", Path: "docs.url"},
+	{Collection: "dealers", Path: "watermark.logo_key"},
+	{Collection: "leads", Path: "aadhar_photo"},
+}
+
+// MongoMediaReferenceRepository looks across every collection that stores object keys.
+// Soft-deleted records still count as references so their media survives a restore.
+type MongoMediaReferenceRepository struct {
+	collections map[string]*mongo.Collection
+}
+
+func NewMongoMediaReferenceRepository(propertyCollection *mongo.Collection, dealerClientCollection *mongo.Collection, dealerCollection *mongo.Collection, leadCollection *mongo.Collection) repositories.MediaReferenceRepository {
+	return &MongoMediaReferenceRepository{
+		collections: map[string]*mongo.Collection{
+			"properties":     propertyCollection,
+			"dealer_clients": dealerClientCollection,
+			"dealers":        dealerCollection,
+			"leads":          leadCollection,
+		},
+	}
+}
+
+func (r *MongoMediaReferenceRepository) FindReferences(ctx context.Context, keys []string) ([]models.MediaReference, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool, len(keys))
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		wanted[key] = true
+		quoted[i] = regexp.QuoteMeta(key)
+	}
+	// A URL references a key when the key is its whole path suffix, ignoring any query string
+	inURL := primitive.Regex{Pattern: "/(" + strings.Join(quoted, "|") + ")([?#].*)?$"}
+
+	var references []models.MediaReference
+	for _, field := range mediaReferenceFields {
+		filter := bson.M{"$or": []bson.M{
+			{field.Path: bson.M{"$in": keys}},
+			{field.Path: inURL},
+		}}
+
+		var records []bson.M
+		if err := r.find(ctx, r.collections[field.Collection], filter, bson.M{field.Path: 1}, &records); err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			recordID := ""
+			if id, ok := record["_id"].(primitive.ObjectID); ok {
+				recordID = id.Hex()
+			}
+			for _, value := range fieldValues(record, strings.Split(field.Path, ".")) {
+				if key, ok := referencedKey(value, wanted); ok {
+					references = append(references, models.MediaReference{Key: key, Collection: field.Collection, RecordID: recordID, Field: field.Path})
+				}
+			}
+		}
+	}
+
+	return references, nil
+}
+
+// fieldValues collects the strings found at path, descending into arrays along the way
+func fieldValues(value interface{}, path []string) []string {
+	switch typed := value.(type) {
+	case string:
+		if len(path) == 0 {
+			return []string{typed}
+		}
+	case bson.M:
+		if len(path) > 0 {
+			return fieldValues(typed[path[0]], path[1:])
+		}
+	case bson.D:
+		return fieldValues(typed.Map(), path)
+	case bson.A:
+		var values []string
+		for _, item := range typed {
+			values = append(values, fieldValues(item, path)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// referencedKey returns the wanted key that value is, or that ends the path of value as a URL
+func referencedKey(value string, wanted map[string]bool) (string, bool) {
+	if wanted[value] {
+		return value, true
+	}
+	if end := strings.IndexAny(value, "?#"); end >= 0 {
+		value = value[:end]
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] == '/' && wanted[value[i+1:]] {
+			return value[i+1:], true
+		}
+	}
+	return "", false
+}
+
+func (r *MongoMediaReferenceRepository) find(ctx context.Context, collection *mongo.Collection, filter bson.M, projection bson.M, results interface{}) error {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,102 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoNotificationRepository struct {
+	notificationCollection *mongo.Collection
+}
+
+func NewMongoNotificationRepository(notificationCollection *mongo.Collection) repositories.NotificationRepository {
+	return &MongoNotificationRepository{
+		notificationCollection: notificationCollection,
+	}
+}
+
+func (r *MongoNotificationRepository) Create(ctx context.Context, notification models.Notification) (models.Notification, error) {
+	notification.CreatedAt = time.Now()
+
+	mongoNotification := converters.ToMongoNotification(notification)
+	result, err := r.notificationCollection.InsertOne(ctx, mongoNotification)
+	if err != nil {
+		return models.Notification{}, err
+	}
+
+	mongoNotification.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainNotification(mongoNotification), nil
+}
+
+func (r *MongoNotificationRepository) GetByRecipient(ctx context.Context, recipientID string, params models.NotificationQueryParams) ([]models.Notification, error) {
+	params.SetDefaults()
+
+	filter := bson.M{"recipient_id": recipientID}
+	if params.Unread != nil {
+		filter["read_at"] = bson.M{"$exists": !*params.Unread}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64((*params.Page - 1) * (*params.Limit))).
+		SetLimit(int64(*params.Limit))
+
+	cursor, err := r.notificationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoNotifications []mongoModels.Notification
+	if err := cursor.All(ctx, &mongoNotifications); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainNotificationSlice(mongoNotifications), nil
+}
+
+func (r *MongoNotificationRepository) CountUnread(ctx context.Context, recipientID string) (int64, error) {
+	return r.notificationCollection.CountDocuments(ctx, bson.M{
+		"recipient_id": recipientID,
+		"read_at":      bson.M{"$exists": false},
+	})
+}
+
+// MarkRead returns mongo.ErrNoDocuments if the notification does not exist or belongs to someone else
+func (r *MongoNotificationRepository) MarkRead(ctx context.Context, id string, recipientID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.notificationCollection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "recipient_id": recipientID},
+		[]bson.M{{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoNotificationRepository) MarkAllRead(ctx context.Context, recipientID string) (int64, error) {
+	result, err := r.notificationCollection.UpdateMany(ctx,
+		bson.M{"recipient_id": recipientID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -183,8 +183,11 @@ func (r *MongoPropertyRepository) GetProperties(ctx context.Context, params mode
 
 }
 
+// GetFilteredProperties returns matching listings newest first, so a capped query keeps the latest
+// listings and callers can page on _id
 func (r *MongoPropertyRepository) GetFilteredProperties(ctx context.Context, filter bson.M, projection bson.M, limit int64, skip int64) ([]models.Property, error) {
-	cursor, err := r.propertyCollection.Find(ctx, filter, options.Find().SetProjection(projection).SetLimit(limit).SetSkip(skip))
+	opts := options.Find().SetProjection(projection).SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit).SetSkip(skip)
+	cursor, err := r.propertyCollection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -197,3 +200,88 @@ func (r *MongoPropertyRepository) GetFilteredProperties(ctx context.Context, fil
 
 	return converters.ToDomainPropertySlice(mongoProperties), nil
 }
+
+
+func (r *MongoPropertyRepository) SetPhotoVariants(ctx context.Context, id string, key string, variants map[string]string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// Target the photo by key so a concurrent edit of the photo list is not overwritten
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"photo.key": key}},
+	})
+	_, err = r.propertyCollection.UpdateByID(ctx, objectID, bson.M{"$set": bson.M{
+		"photos.$[photo].variants": variants,
+		"updated_at":               time.Now(),
+	}}, opts)
+	return err
+}
+
+// SetCoBroke sets a listing's co-broking terms; mongo.ErrNoDocuments means the dealer has no such active listing
+func (r *MongoPropertyRepository) SetCoBroke(ctx context.Context, id string, dealerID string, terms models.CoBrokeTerms) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.propertyCollection.UpdateOne(ctx, bson.M{
+		"_id":        objectID,
+		"dealer_id":  dealerObjectID,
+		"is_deleted": bson.M{"$ne": true},
+	}, bson.M{"$set": bson.M{
+		"co_broke":   converters.ToMongoCoBrokeTerms(&terms),
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetCoBrokePool pages through other dealers' listings opted into co-broking. Owner details
+// and the exact address are never read, so they cannot leak to other dealers.
+func (r *MongoPropertyRepository) GetCoBrokePool(ctx context.Context, params models.PropertyQueryParams, excludeDealerID string) ([]models.Property, error) {
+	excludeObjectID, err := primitive.ObjectIDFromHex(excludeDealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := utils.BuildMongoFilter(params)
+	filter["co_broke.enabled"] = true
+	if dealerID, ok := filter["dealer_id"].(primitive.ObjectID); ok && dealerID == excludeObjectID {
+		return []models.Property{}, nil
+	} else if !ok {
+		filter["dealer_id"] = bson.M{"$ne": excludeObjectID}
+	}
+
+	sortValue := 1
+	if *params.Order == "desc" {
+		sortValue = -1
+	}
+	opts := options.Find().
+		SetSort(bson.M{*params.Sort: sortValue}).
+		SetSkip(int64((*params.Page - 1) * *params.Limit)).
+		SetLimit(int64(*params.Limit)).
+		SetProjection(bson.M{"owner_name": 0, "owner_phone": 0, "address": 0})
+
+	cursor, err := r.propertyCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoProperties []mongoModels.Property
+	if err := cursor.All(ctx, &mongoProperties); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainPropertySlice(mongoProperties), nil
+}
@@ -0,0 +1,103 @@
+package mongo_repositories
+
+import (
+	"context"
+	"fmt"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Counter field incremented for each event
+var propertyEventFields = map[string]string{
+	models.PropertyEventPublicView:       "public_views",
+	models.PropertyEventDealerView:       "dealer_views",
+	models.PropertyEventBrochureDownload: "brochure_downloads",
+	models.PropertyEventInterest:         "interests",
+}
+
+type MongoPropertyStatsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoPropertyStatsRepository(collection *mongo.Collection) repositories.PropertyStatsRepository {
+	return &MongoPropertyStatsRepository{collection: collection}
+}
+
+func (r *MongoPropertyStatsRepository) Increment(ctx context.Context, propertyID string, dealerID string, date string, event string) error {
+	field, ok := propertyEventFields[event]
+	if !ok {
+		return fmt.Errorf("unknown property event %q", event)
+	}
+	propertyObjectID, err := primitive.ObjectIDFromHex(propertyID)
+	if err != nil {
+		return err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"property_id": propertyObjectID, "date": date},
+		bson.M{
+			"$inc":         bson.M{field: 1},
+			"$setOnInsert": bson.M{"dealer_id": dealerObjectID},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *MongoPropertyStatsRepository) GetByProperty(ctx context.Context, propertyID string, from string, to string) ([]models.PropertyDayStats, error) {
+	objectID, err := primitive.ObjectIDFromHex(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return r.aggregate(ctx, bson.M{"property_id": objectID, "date": bson.M{"$gte": from, "$lte": to}})
+}
+
+func (r *MongoPropertyStatsRepository) GetByDealer(ctx context.Context, dealerID string, from string, to string) ([]models.PropertyDayStats, error) {
+	objectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.aggregate(ctx, bson.M{"dealer_id": objectID, "date": bson.M{"$gte": from, "$lte": to}})
+}
+
+// aggregate sums by property and day, so two documents upserted concurrently for the same day
+// still read as one
+func (r *MongoPropertyStatsRepository) aggregate(ctx context.Context, match bson.M) ([]models.PropertyDayStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                bson.M{"property_id": "$property_id", "date": "$date"},
+			"dealer_id":          bson.M{"$first": "$dealer_id"},
+			"public_views":       bson.M{"$sum": "$public_views"},
+			"dealer_views":       bson.M{"$sum": "$dealer_views"},
+			"brochure_downloads": bson.M{"$sum": "$brochure_downloads"},
+			"interests":          bson.M{"$sum": "$interests"},
+		}}},
+		{{Key: "$addFields", Value: bson.M{"property_id": "$_id.property_id", "date": "$_id.date"}}},
+		{{Key: "$project", Value: bson.M{"_id": 0}}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}, {Key: "property_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []mongoModels.PropertyDayStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainPropertyDayStatsSlice(stats), nil
+}
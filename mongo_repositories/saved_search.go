@@ -0,0 +1,213 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSavedSearchRepository struct {
+	searchCollection *mongo.Collection
+	matchCollection  *mongo.Collection
+}
+
+func NewMongoSavedSearchRepository(searchCollection, matchCollection *mongo.Collection) repositories.SavedSearchRepository {
+	return &MongoSavedSearchRepository{
+		searchCollection: searchCollection,
+		matchCollection:  matchCollection,
+	}
+}
+
+func (r *MongoSavedSearchRepository) Create(ctx context.Context, search models.SavedSearch) (models.SavedSearch, error) {
+	now := time.Now()
+	search.CreatedAt = now
+	search.UpdatedAt = now
+
+	mongoSearch, err := converters.ToMongoSavedSearch(search)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+
+	result, err := r.searchCollection.InsertOne(ctx, mongoSearch)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+
+	mongoSearch.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainSavedSearch(mongoSearch), nil
+}
+
+func (r *MongoSavedSearchRepository) GetByID(ctx context.Context, id string) (models.SavedSearch, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+
+	var mongoSearch mongoModels.SavedSearch
+	if err := r.searchCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoSearch); err != nil {
+		return models.SavedSearch{}, err
+	}
+	return converters.ToDomainSavedSearch(mongoSearch), nil
+}
+
+// GetByDealer lists the dealer's saved searches, newest first; a non-empty dealerClientID narrows
+// to those saved for that client
+func (r *MongoSavedSearchRepository) GetByDealer(ctx context.Context, dealerID string, dealerClientID string) ([]models.SavedSearch, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"dealer_id": dealerObjectID}
+	if dealerClientID != "" {
+		dealerClientObjectID, err := primitive.ObjectIDFromHex(dealerClientID)
+		if err != nil {
+			return nil, err
+		}
+		filter["dealer_client_id"] = dealerClientObjectID
+	}
+
+	return r.find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+}
+
+func (r *MongoSavedSearchRepository) GetActiveByDealer(ctx context.Context, dealerID string) ([]models.SavedSearch, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"dealer_id": dealerObjectID, "active": true}, options.Find())
+}
+
+// Update replaces the editable fields of the search; match counters are left alone
+func (r *MongoSavedSearchRepository) Update(ctx context.Context, search models.SavedSearch) (models.SavedSearch, error) {
+	objectID, err := primitive.ObjectIDFromHex(search.ID)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	mongoSearch, err := converters.ToMongoSavedSearch(search)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+
+	set := bson.M{
+		"name":       mongoSearch.Name,
+		"query":      mongoSearch.Query,
+		"active":     mongoSearch.Active,
+		"updated_at": time.Now(),
+	}
+	update := bson.M{"$set": set}
+	if mongoSearch.DealerClientID != nil {
+		set["dealer_client_id"] = mongoSearch.DealerClientID
+	} else {
+		update["$unset"] = bson.M{"dealer_client_id": ""}
+	}
+
+	var updated mongoModels.SavedSearch
+	err = r.searchCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID, "dealer_id": mongoSearch.DealerID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	return converters.ToDomainSavedSearch(updated), nil
+}
+
+// Delete removes the search and its recorded matches
+func (r *MongoSavedSearchRepository) Delete(ctx context.Context, id string, dealerID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.searchCollection.DeleteOne(ctx, bson.M{"_id": objectID, "dealer_id": dealerObjectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = r.matchCollection.DeleteMany(ctx, bson.M{"saved_search_id": objectID})
+	return err
+}
+
+// RecordMatch stores the match unless the property has matched the search before, and reports
+// whether it was new. New matches bump the search's match counters.
+func (r *MongoSavedSearchRepository) RecordMatch(ctx context.Context, match models.SavedSearchMatch) (bool, error) {
+	match.MatchedAt = time.Now()
+	mongoMatch, err := converters.ToMongoSavedSearchMatch(match)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.matchCollection.UpdateOne(ctx,
+		bson.M{"saved_search_id": mongoMatch.SavedSearchID, "property_id": mongoMatch.PropertyID},
+		bson.M{"$setOnInsert": mongoMatch},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+
+	_, err = r.searchCollection.UpdateByID(ctx, mongoMatch.SavedSearchID, bson.M{
+		"$inc": bson.M{"match_count": 1},
+		"$set": bson.M{"last_matched_at": mongoMatch.MatchedAt},
+	})
+	return true, err
+}
+
+func (r *MongoSavedSearchRepository) GetMatches(ctx context.Context, savedSearchID string, page int, limit int) ([]models.SavedSearchMatch, error) {
+	objectID, err := primitive.ObjectIDFromHex(savedSearchID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"matched_at": -1}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.matchCollection.Find(ctx, bson.M{"saved_search_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoMatches []mongoModels.SavedSearchMatch
+	if err := cursor.All(ctx, &mongoMatches); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainSavedSearchMatchSlice(mongoMatches), nil
+}
+
+func (r *MongoSavedSearchRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.SavedSearch, error) {
+	cursor, err := r.searchCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoSearches []mongoModels.SavedSearch
+	if err := cursor.All(ctx, &mongoSearches); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainSavedSearchSlice(mongoSearches), nil
+}
@@ -0,0 +1,116 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoShareLinkRepository struct {
+	shareLinkCollection *mongo.Collection
+}
+
+func NewMongoShareLinkRepository(shareLinkCollection *mongo.Collection) repositories.ShareLinkRepository {
+	return &MongoShareLinkRepository{
+		shareLinkCollection: shareLinkCollection,
+	}
+}
+
+func (r *MongoShareLinkRepository) Create(ctx context.Context, link models.ShareLink) (models.ShareLink, error) {
+	now := time.Now()
+	link.CreatedAt = now
+	link.UpdatedAt = now
+
+	mongoLink, err := converters.ToMongoShareLink(link)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	result, err := r.shareLinkCollection.InsertOne(ctx, mongoLink)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	mongoLink.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainShareLink(mongoLink), nil
+}
+
+func (r *MongoShareLinkRepository) GetByID(ctx context.Context, id string) (models.ShareLink, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	var mongoLink mongoModels.ShareLink
+	if err := r.shareLinkCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoLink); err != nil {
+		return models.ShareLink{}, err
+	}
+	return converters.ToDomainShareLink(mongoLink), nil
+}
+
+func (r *MongoShareLinkRepository) GetByDealer(ctx context.Context, dealerID string) ([]models.ShareLink, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := r.shareLinkCollection.Find(ctx, bson.M{"dealer_id": dealerObjectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoLinks []mongoModels.ShareLink
+	if err := cursor.All(ctx, &mongoLinks); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainShareLinkSlice(mongoLinks), nil
+}
+
+// Revoke disables a link owned by dealerID; mongo.ErrNoDocuments means no such link for that dealer
+func (r *MongoShareLinkRepository) Revoke(ctx context.Context, id string, dealerID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := r.shareLinkCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "dealer_id": dealerObjectID},
+		bson.M{"$set": bson.M{"revoked": true, "revoked_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoShareLinkRepository) RecordOpen(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.shareLinkCollection.UpdateByID(ctx, objectID, bson.M{
+		"$inc": bson.M{"opens": 1},
+		"$set": bson.M{"last_opened_at": time.Now()},
+	})
+	return err
+}
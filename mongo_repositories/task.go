@@ -0,0 +1,238 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"myapp/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoTaskRepository struct {
+	taskCollection *mongo.Collection
+}
+
+func NewMongoTaskRepository(taskCollection *mongo.Collection) repositories.TaskRepository {
+	return &MongoTaskRepository{
+		taskCollection: taskCollection,
+	}
+}
+
+func (r *MongoTaskRepository) Create(ctx context.Context, task models.Task) (models.Task, error) {
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	mongoTask, err := converters.ToMongoTask(task)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	result, err := r.taskCollection.InsertOne(ctx, mongoTask)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	mongoTask.ID = result.InsertedID.(primitive.ObjectID)
+	return converters.ToDomainTask(mongoTask), nil
+}
+
+func (r *MongoTaskRepository) GetByID(ctx context.Context, id string) (models.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var mongoTask mongoModels.Task
+	if err := r.taskCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoTask); err != nil {
+		return models.Task{}, err
+	}
+	return converters.ToDomainTask(mongoTask), nil
+}
+
+func (r *MongoTaskRepository) GetTasks(ctx context.Context, params models.TaskQueryParams) ([]models.Task, error) {
+	params.SetDefaults()
+
+	filter := utils.BuildMongoFilter(params)
+	opts := options.Find().
+		SetSort(bson.D{{Key: *params.Sort, Value: getSortOrder(*params.Order)}}).
+		SetSkip(int64((*params.Page - 1) * (*params.Limit))).
+		SetLimit(int64(*params.Limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+// GetOverdue returns open tasks that were due before now, most overdue first. An empty
+// assigneeID returns everyone's.
+func (r *MongoTaskRepository) GetOverdue(ctx context.Context, assigneeID string, now time.Time, page int, limit int) ([]models.Task, error) {
+	filter := bson.M{
+		"status": models.TaskStatusOpen,
+		"due_at": bson.M{"$lt": now},
+	}
+	if assigneeID != "" {
+		filter["assignee_id"] = assigneeID
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"due_at": 1}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+// Update saves the editable fields of an open task and re-arms its reminder; mongo.ErrNoDocuments
+// means it is no longer open
+func (r *MongoTaskRepository) Update(ctx context.Context, task models.Task) (models.Task, error) {
+	return r.updateOpen(ctx, task.ID, bson.M{
+		"$set": bson.M{
+			"title":       task.Title,
+			"notes":       task.Notes,
+			"assignee_id": task.AssigneeID,
+			"due_at":      task.DueAt,
+			"remind_at":   task.RemindAt,
+			"updated_at":  time.Now(),
+		},
+		"$unset": bson.M{"reminded_at": ""},
+	})
+}
+
+func (r *MongoTaskRepository) Snooze(ctx context.Context, id string, dueAt time.Time, remindAt time.Time) (models.Task, error) {
+	return r.updateOpen(ctx, id, bson.M{
+		"$set": bson.M{
+			"due_at":     dueAt,
+			"remind_at":  remindAt,
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{"reminded_at": ""},
+		"$inc":   bson.M{"snooze_count": 1},
+	})
+}
+
+func (r *MongoTaskRepository) Complete(ctx context.Context, id string, completedBy string, outcome string) (models.Task, error) {
+	now := time.Now()
+	return r.updateOpen(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":       models.TaskStatusCompleted,
+			"completed_at": now,
+			"completed_by": completedBy,
+			"outcome":      outcome,
+			"updated_at":   now,
+		},
+	})
+}
+
+// updateOpen applies update to a task that is still open. Matching on the status makes
+// concurrent changes safe: once a task is completed nothing else changes it.
+func (r *MongoTaskRepository) updateOpen(ctx context.Context, id string, update bson.M) (models.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var mongoTask mongoModels.Task
+	err = r.taskCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID, "status": models.TaskStatusOpen},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&mongoTask)
+	if err != nil {
+		return models.Task{}, err
+	}
+	return converters.ToDomainTask(mongoTask), nil
+}
+
+func (r *MongoTaskRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.taskCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetDueReminders returns up to limit open tasks whose reminder was due by before and has not been sent
+func (r *MongoTaskRepository) GetDueReminders(ctx context.Context, before time.Time, limit int) ([]models.Task, error) {
+	filter := bson.M{
+		"status":      models.TaskStatusOpen,
+		"remind_at":   bson.M{"$lte": before},
+		"reminded_at": bson.M{"$exists": false},
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.M{"remind_at": 1}).SetLimit(int64(limit)))
+}
+
+// GetPendingReminders returns every open task whose reminder has not been sent
+func (r *MongoTaskRepository) GetPendingReminders(ctx context.Context) ([]models.Task, error) {
+	filter := bson.M{
+		"status":      models.TaskStatusOpen,
+		"reminded_at": bson.M{"$exists": false},
+	}
+	return r.find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1, "remind_at": 1}))
+}
+
+// MarkReminded records that the reminder due at remindAt was sent. It reports false when the task
+// was completed, snoozed or already reminded in the meantime, so each reminder goes out once
+// even with several instances polling.
+func (r *MongoTaskRepository) MarkReminded(ctx context.Context, id string, remindAt time.Time) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.taskCollection.UpdateOne(ctx,
+		bson.M{
+			"_id":         objectID,
+			"status":      models.TaskStatusOpen,
+			"remind_at":   remindAt,
+			"reminded_at": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"reminded_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ClearReminded makes the reminder due at remindAt pending again, unless the task has since been
+// completed or snoozed
+func (r *MongoTaskRepository) ClearReminded(ctx context.Context, id string, remindAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.taskCollection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "status": models.TaskStatusOpen, "remind_at": remindAt},
+		bson.M{"$unset": bson.M{"reminded_at": ""}},
+	)
+	return err
+}
+
+func (r *MongoTaskRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Task, error) {
+	cursor, err := r.taskCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []mongoModels.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainTaskSlice(tasks), nil
+}
@@ -0,0 +1,277 @@
+package mongo_repositories
+
+import (
+	"context"
+	"myapp/converters"
+	"myapp/models"
+	mongoModels "myapp/mongo_models"
+	"myapp/repositories"
+	"myapp/utils"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoVisitRepository struct {
+	visitCollection        *mongo.Collection
+	lockCollection         *mongo.Collection
+	calendarFeedCollection *mongo.Collection
+}
+
+// NewMongoVisitRepository serialises bookings through one lock document per property and per
+// agent in lockCollection, and keeps each dealer's calendar feed version in calendarFeedCollection
+func NewMongoVisitRepository(visitCollection *mongo.Collection, lockCollection *mongo.Collection, calendarFeedCollection *mongo.Collection) repositories.VisitRepository {
+	return &MongoVisitRepository{
+		visitCollection:        visitCollection,
+		lockCollection:         lockCollection,
+		calendarFeedCollection: calendarFeedCollection,
+	}
+}
+
+// Create books visit unless an active visit overlaps its slot, in which case the overlapping
+// visits are returned and nothing is written
+func (r *MongoVisitRepository) Create(ctx context.Context, visit models.Visit) (models.Visit, []models.Visit, error) {
+	now := time.Now()
+	visit.CreatedAt = now
+	visit.UpdatedAt = now
+
+	mongoVisit, err := converters.ToMongoVisit(visit)
+	if err != nil {
+		return models.Visit{}, nil, err
+	}
+
+	conflicts, err := r.withSlotLocked(ctx, visit, func(sc mongo.SessionContext) error {
+		result, err := r.visitCollection.InsertOne(sc, mongoVisit)
+		if err != nil {
+			return err
+		}
+		mongoVisit.ID = result.InsertedID.(primitive.ObjectID)
+		return nil
+	})
+	if err != nil || len(conflicts) > 0 {
+		return models.Visit{}, conflicts, err
+	}
+	return converters.ToDomainVisit(mongoVisit), nil, nil
+}
+
+func (r *MongoVisitRepository) GetByID(ctx context.Context, id string) (models.Visit, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	var mongoVisit mongoModels.Visit
+	if err := r.visitCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mongoVisit); err != nil {
+		return models.Visit{}, err
+	}
+	return converters.ToDomainVisit(mongoVisit), nil
+}
+
+func (r *MongoVisitRepository) GetVisits(ctx context.Context, params models.VisitQueryParams) ([]models.Visit, error) {
+	params.SetDefaults()
+
+	filter := utils.BuildMongoFilter(params)
+	opts := options.Find().
+		SetSort(bson.D{{Key: *params.Sort, Value: getSortOrder(*params.Order)}}).
+		SetSkip(int64((*params.Page - 1) * (*params.Limit))).
+		SetLimit(int64(*params.Limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+// GetByDealerBetween returns every visit of the dealer's that starts in [from, to), for calendar feeds
+func (r *MongoVisitRepository) GetByDealerBetween(ctx context.Context, dealerID string, from, to time.Time) ([]models.Visit, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"dealer_id": dealerObjectID,
+		"starts_at": bson.M{"$gte": from, "$lt": to},
+	}
+	return r.find(ctx, filter, options.Find().SetSort(bson.M{"starts_at": 1}))
+}
+
+// withSlotLocked runs write in a transaction once no active visit overlaps visit's slot, returning
+// the overlapping visits otherwise. Every booking first bumps the lock documents of the property
+// and of the named agent, so two bookings on the same calendar write-conflict and the one retried
+// sees the other's visit.
+func (r *MongoVisitRepository) withSlotLocked(ctx context.Context, visit models.Visit, write func(sc mongo.SessionContext) error) ([]models.Visit, error) {
+	session, err := r.visitCollection.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var conflicts []models.Visit
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		lockIDs := []string{"property:" + visit.PropertyID}
+		if agent := agentKey(visit.AgentName); agent != "" {
+			lockIDs = append(lockIDs, "agent:"+visit.DealerID+":"+agent)
+		}
+		for _, lockID := range lockIDs {
+			_, err := r.lockCollection.UpdateOne(sc, bson.M{"_id": lockID},
+				bson.M{"$inc": bson.M{"bookings": 1}}, options.Update().SetUpsert(true))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		conflicts, err = r.findConflicts(sc, visit)
+		if err != nil || len(conflicts) > 0 {
+			return nil, err
+		}
+		return nil, write(sc)
+	})
+	return conflicts, err
+}
+
+// findConflicts returns active visits, other than visit itself, whose slot overlaps visit's
+// at the same property or with the same named agent of the same dealer
+func (r *MongoVisitRepository) findConflicts(ctx context.Context, visit models.Visit) ([]models.Visit, error) {
+	mongoVisit, err := converters.ToMongoVisit(visit)
+	if err != nil {
+		return nil, err
+	}
+
+	sameCalendar := []bson.M{{"property_id": mongoVisit.PropertyID}}
+	// Visits without an agent are the dealer's own and may run in parallel at other properties
+	if agent := agentKey(visit.AgentName); agent != "" {
+		sameCalendar = append(sameCalendar, bson.M{
+			"dealer_id":  mongoVisit.DealerID,
+			"agent_name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(agent) + "$", Options: "i"},
+		})
+	}
+	filter := bson.M{
+		"status":    bson.M{"$in": models.ActiveVisitStatuses},
+		"starts_at": bson.M{"$lt": visit.EndsAt},
+		"ends_at":   bson.M{"$gt": visit.StartsAt},
+		"$or":       sameCalendar,
+	}
+	if visit.ID != "" {
+		objectID, err := primitive.ObjectIDFromHex(visit.ID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$ne": objectID}
+	}
+
+	return r.find(ctx, filter, options.Find().SetSort(bson.M{"starts_at": 1}))
+}
+
+// agentKey is how an agent's name is compared between visits, so "Ravi" and "ravi" are one agent
+func agentKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Reschedule moves an active visit to the slot in event unless another active visit overlaps it,
+// returning the overlapping visits instead; mongo.ErrNoDocuments means it is no longer active
+func (r *MongoVisitRepository) Reschedule(ctx context.Context, visit models.Visit, event models.VisitEvent) (models.Visit, []models.Visit, error) {
+	visit.StartsAt, visit.EndsAt = event.StartsAt, event.EndsAt
+
+	var rescheduled models.Visit
+	conflicts, err := r.withSlotLocked(ctx, visit, func(sc mongo.SessionContext) error {
+		var err error
+		rescheduled, err = r.transition(sc, visit.ID, event, bson.M{
+			"starts_at": event.StartsAt,
+			"ends_at":   event.EndsAt,
+		})
+		return err
+	})
+	if err != nil || len(conflicts) > 0 {
+		return models.Visit{}, conflicts, err
+	}
+	return rescheduled, nil, nil
+}
+
+func (r *MongoVisitRepository) Cancel(ctx context.Context, id string, reason string, event models.VisitEvent) (models.Visit, error) {
+	return r.transition(ctx, id, event, bson.M{"cancel_reason": reason})
+}
+
+func (r *MongoVisitRepository) Complete(ctx context.Context, id string, outcomeNote string, event models.VisitEvent) (models.Visit, error) {
+	return r.transition(ctx, id, event, bson.M{"outcome_note": outcomeNote})
+}
+
+// transition applies a status change to a visit that is still active and appends it to the history.
+// Matching on the status makes concurrent changes safe: only the first one wins.
+func (r *MongoVisitRepository) transition(ctx context.Context, id string, event models.VisitEvent, set bson.M) (models.Visit, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	set["status"] = event.Status
+	set["updated_at"] = event.ChangedAt
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": converters.ToMongoVisitEvent(event)},
+	}
+
+	var mongoVisit mongoModels.Visit
+	err = r.visitCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID, "status": bson.M{"$in": models.ActiveVisitStatuses}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&mongoVisit)
+	if err != nil {
+		return models.Visit{}, err
+	}
+	return converters.ToDomainVisit(mongoVisit), nil
+}
+
+// GetCalendarFeedVersion returns the version of the dealer's current feed link, 0 if it was never rotated
+func (r *MongoVisitRepository) GetCalendarFeedVersion(ctx context.Context, dealerID string) (int64, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return 0, err
+	}
+
+	var feed struct {
+		Version int64 `bson:"version"`
+	}
+	err = r.calendarFeedCollection.FindOne(ctx, bson.M{"_id": dealerObjectID}).Decode(&feed)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return feed.Version, err
+}
+
+// RotateCalendarFeed moves the dealer to a new feed version, returning it
+func (r *MongoVisitRepository) RotateCalendarFeed(ctx context.Context, dealerID string) (int64, error) {
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return 0, err
+	}
+
+	var feed struct {
+		Version int64 `bson:"version"`
+	}
+	err = r.calendarFeedCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": dealerObjectID},
+		bson.M{"$inc": bson.M{"version": 1}, "$set": bson.M{"rotated_at": time.Now()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&feed)
+	return feed.Version, err
+}
+
+func (r *MongoVisitRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Visit, error) {
+	cursor, err := r.visitCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoVisits []mongoModels.Visit
+	if err := cursor.All(ctx, &mongoVisits); err != nil {
+		return nil, err
+	}
+	return converters.ToDomainVisitSlice(mongoVisits), nil
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type AadharAccessLogRepository interface {
+	Create(ctx context.Context, entry models.AadharAccessLog) error
+	GetByLead(ctx context.Context, leadID string, limit int64) ([]models.AadharAccessLog, error)
+}
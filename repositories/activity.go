@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+// ActivityRepository stores lead and dealer client timelines. There is deliberately no update or
+// delete: the timeline is append-only.
+type ActivityRepository interface {
+	Create(ctx context.Context, activity models.Activity) (models.Activity, error)
+	GetActivities(ctx context.Context, params models.ActivityQueryParams) ([]models.Activity, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type CoBrokeRepository interface {
+	Create(ctx context.Context, agreement models.CoBrokeAgreement) (models.CoBrokeAgreement, bool, error)
+	GetByID(ctx context.Context, id string) (models.CoBrokeAgreement, error)
+	GetByDealer(ctx context.Context, dealerID string, side string, status string) ([]models.CoBrokeAgreement, error)
+	UpdateStatus(ctx context.Context, id string, fromStatus string, agreement models.CoBrokeAgreement) (models.CoBrokeAgreement, error)
+}
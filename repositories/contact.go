@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+// LeadMergeFunc combines a lead and its duplicate into the lead that survives, and describes the merge
+type LeadMergeFunc func(lead models.Lead, duplicate models.Lead) (models.Lead, models.LeadMerge, error)
+
+type ContactRepository interface {
+	BackfillContactKeys(ctx context.Context) (int64, error)
+	GetDuplicates(ctx context.Context, leadsOnly bool, page int, limit int) ([]models.ContactSummary, error)
+	GetLeads(ctx context.Context, key string) ([]models.Lead, error)
+	GetInquiries(ctx context.Context, key string) ([]models.Inquiry, error)
+	GetDealerClients(ctx context.Context, key string) ([]models.DealerClient, error)
+	GetVisits(ctx context.Context, leadIDs []string, dealerClientIDs []string) ([]models.Visit, error)
+	GetTasks(ctx context.Context, leadIDs []string, dealerClientIDs []string) ([]models.Task, error)
+	GetLeadMerges(ctx context.Context, leadIDs []string) ([]models.LeadMerge, error)
+	MergeLeads(ctx context.Context, leadID string, duplicateID string, combine LeadMergeFunc) (models.Lead, models.LeadMerge, error)
+}
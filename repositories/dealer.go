@@ -18,4 +18,5 @@ type DealerRepository interface {
 	Delete(ctx context.Context, id string) error
 	Exists(ctx context.Context, id string) (bool, error)
 	GetDealerWithProperties(ctx context.Context, subLocation string) ([]map[string]interface{}, error)
+	UpdateWatermark(ctx context.Context, id string, watermark models.WatermarkSettings) error
 }
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"myapp/models"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type DealerClientRepository interface {
@@ -17,4 +19,5 @@ type DealerClientRepository interface {
 	CreateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, dealerClientPropertyInterest models.DealerClientPropertyInterest) error
 	UpdateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, propertyInterestID string, update models.DealerClientPropertyInterestUpdate) error
 	DeleteDealerClientPropertyInterest(ctx context.Context, dealerClientID string, propertyInterestID string) error
+	GetFilteredDealerClients(ctx context.Context, filter bson.M, limit int64) ([]models.DealerClient, error)
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"myapp/models"
+)
+
+// ErrTransferSuccessorUnavailable is returned by Transfer when the successor does not exist or
+// is deactivated
+var ErrTransferSuccessorUnavailable = errors.New("successor dealer does not exist or is deactivated")
+
+type DealerTransferRepository interface {
+	// Transfer moves the departing dealer's records to the successor, deactivates the departing
+	// dealer and stores the transfer with what was moved, all in one transaction. It returns
+	// mongo.ErrNoDocuments if the departing dealer is already deactivated, and
+	// ErrTransferSuccessorUnavailable if the successor cannot take the records.
+	Transfer(ctx context.Context, transfer models.DealerTransfer) (models.DealerTransfer, error)
+	// GetByDealer returns the transfers the dealer was on either side of, newest first
+	GetByDealer(ctx context.Context, dealerID string) ([]models.DealerTransfer, error)
+	// CountOwned counts the records that would be orphaned if the dealer were deleted
+	CountOwned(ctx context.Context, dealerID string) (int64, error)
+}
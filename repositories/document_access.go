@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type DocumentAccessLogRepository interface {
+	Create(ctx context.Context, entry models.DocumentAccessLog) error
+	GetByClient(ctx context.Context, clientID string, limit int64) ([]models.DocumentAccessLog, error)
+}
@@ -3,6 +3,9 @@ package repositories
 import (
 	"context"
 	"myapp/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type InquiryRepository interface {
@@ -11,4 +14,12 @@ type InquiryRepository interface {
 	GetAll(ctx context.Context, params models.InquiryQueryParams) ([]models.Inquiry, error)
 	Update(ctx context.Context, id string, updates models.InquiryUpdate) error
 	Delete(ctx context.Context, id string) error
+	GetFilteredInquiries(ctx context.Context, filter bson.M, limit int64) ([]models.Inquiry, error)
+	// ClaimConversion marks an inquiry as being converted, failing with mongo.ErrNoDocuments if it
+	// already was. A claim made before staleBefore that never completed is taken over.
+	// CompleteConversion records what it became; ReleaseConversion undoes a claim whose
+	// conversion failed.
+	ClaimConversion(ctx context.Context, id string, convertedTo string, convertedBy string, at time.Time, staleBefore time.Time) error
+	CompleteConversion(ctx context.Context, id string, convertedID string) (models.Inquiry, error)
+	ReleaseConversion(ctx context.Context, id string) error
 }
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"myapp/models"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type LeadRepository interface {
@@ -14,7 +16,19 @@ type LeadRepository interface {
 	Update(ctx context.Context, id string, updates map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
 	AddPropertyInterest(ctx context.Context, leadID string, propertyInterest models.PropertyInterest) error
-	UpdatePropertyInterest(ctx context.Context, leadID, propertyID string, status, note string) error
+	GetPropertyInterest(ctx context.Context, leadID, propertyID string) (models.PropertyInterest, error)
+	ChangePropertyInterestStatus(ctx context.Context, leadID, propertyID string, change models.PropertyInterestStatusChange) error
 	
 	CheckPhoneExists(ctx context.Context, phone string) (bool, error)
+	FindByPhone(ctx context.Context, phone string) (models.Lead, error)
+	GetFilteredLeads(ctx context.Context, filter bson.M, limit int64) ([]models.Lead, error)
+	// CountAssigned counts the leads each dealer owns that are still being worked
+	CountAssigned(ctx context.Context, dealerIDs []string) (map[string]int64, error)
+	// GetStaleAadhar pages, by ID after afterID, through leads whose Aadhaar number is not encrypted
+	// with keyVersion, plain text included, or not hashed with the lookup key hashKeyID.
+	// SetAadharNumber replaces the number only if it is still previous.
+	GetStaleAadhar(ctx context.Context, keyVersion int, hashKeyID string, afterID string, limit int64) ([]models.Lead, error)
+	// HasAadharNumbers reports whether any lead has an Aadhaar number stored, encrypted or not
+	HasAadharNumbers(ctx context.Context) (bool, error)
+	SetAadharNumber(ctx context.Context, id string, stored models.StoredAadhar, previous string) (bool, error)
 }
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"myapp/models"
+)
+
+var (
+	// ErrLeadOwnerChanged is returned by Assign when the lead no longer belongs to the assignment's
+	// FromDealerID
+	ErrLeadOwnerChanged = errors.New("lead owner changed")
+	// ErrDealerAtCapacity is returned by Assign when the new owner already has capacity open leads
+	ErrDealerAtCapacity = errors.New("dealer is at capacity")
+)
+
+type LeadRoutingRepository interface {
+	CreateRule(ctx context.Context, rule models.LeadRoutingRule) (models.LeadRoutingRule, error)
+	GetRule(ctx context.Context, id string) (models.LeadRoutingRule, error)
+	GetRules(ctx context.Context, activeOnly bool) ([]models.LeadRoutingRule, error)
+	UpdateRule(ctx context.Context, rule models.LeadRoutingRule) (models.LeadRoutingRule, error)
+	DeleteRule(ctx context.Context, id string) error
+	NextTurn(ctx context.Context, ruleID string) (int64, error)
+	// Assign moves the lead from FromDealerID to ToDealerID, unassigning it when ToDealerID is
+	// empty, and records the change in one transaction. A capacity above zero caps how many open
+	// leads the new owner may hold. It returns mongo.ErrNoDocuments if the lead does not exist.
+	Assign(ctx context.Context, assignment models.LeadAssignment, capacity int) (models.LeadAssignment, error)
+	GetAssignments(ctx context.Context, leadID string) ([]models.LeadAssignment, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+	"time"
+)
+
+type MediaRepository interface {
+	Create(ctx context.Context, media []models.Media) error
+	GetByKey(ctx context.Context, key string) (models.Media, error)
+	GetByKeys(ctx context.Context, keys []string) ([]models.Media, error)
+	Confirm(ctx context.Context, key string, contentType string, size int64) error
+	DeletePending(ctx context.Context, key string) error
+	GetExpiredPending(ctx context.Context, before time.Time, limit int64) ([]models.Media, error)
+	Reserve(ctx context.Context, ownerID string, purpose string, files int64, bytes int64, maxFiles int64, maxBytes int64) (bool, error)
+	Release(ctx context.Context, ownerID string, purpose string, files int64, bytes int64) error
+	GetUsageByOwner(ctx context.Context, ownerID string) ([]models.MediaUsage, error)
+	GetCreatedBefore(ctx context.Context, before time.Time, afterID string, limit int64) ([]models.Media, error)
+	DeleteByKey(ctx context.Context, key string) error
+}
+
+// MediaReferenceRepository finds the records that point at stored objects
+type MediaReferenceRepository interface {
+	FindReferences(ctx context.Context, keys []string) ([]models.MediaReference, error)
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification models.Notification) (models.Notification, error)
+	GetByRecipient(ctx context.Context, recipientID string, params models.NotificationQueryParams) ([]models.Notification, error)
+	CountUnread(ctx context.Context, recipientID string) (int64, error)
+	MarkRead(ctx context.Context, id string, recipientID string) error
+	MarkAllRead(ctx context.Context, recipientID string) (int64, error)
+}
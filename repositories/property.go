@@ -16,4 +16,7 @@ type PropertyRepository interface {
 	GetNextPropertyNumber(ctx context.Context) (int64, error)
 	GetProperties(ctx context.Context, params models.PropertyQueryParams, fields []string) ([]models.Property, error)
 	GetFilteredProperties(ctx context.Context, filter bson.M, projection bson.M, limit int64, skip int64) ([]models.Property, error)
+	SetPhotoVariants(ctx context.Context, id string, key string, variants map[string]string) error
+	SetCoBroke(ctx context.Context, id string, dealerID string, terms models.CoBrokeTerms) error
+	GetCoBrokePool(ctx context.Context, params models.PropertyQueryParams, excludeDealerID string) ([]models.Property, error)
 }
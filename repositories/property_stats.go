@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type PropertyStatsRepository interface {
+	// Increment adds one to the event's counter for the property on date (YYYY-MM-DD)
+	Increment(ctx context.Context, propertyID string, dealerID string, date string, event string) error
+	GetByProperty(ctx context.Context, propertyID string, from string, to string) ([]models.PropertyDayStats, error)
+	GetByDealer(ctx context.Context, dealerID string, from string, to string) ([]models.PropertyDayStats, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search models.SavedSearch) (models.SavedSearch, error)
+	GetByID(ctx context.Context, id string) (models.SavedSearch, error)
+	GetByDealer(ctx context.Context, dealerID string, dealerClientID string) ([]models.SavedSearch, error)
+	GetActiveByDealer(ctx context.Context, dealerID string) ([]models.SavedSearch, error)
+	Update(ctx context.Context, search models.SavedSearch) (models.SavedSearch, error)
+	Delete(ctx context.Context, id string, dealerID string) error
+	RecordMatch(ctx context.Context, match models.SavedSearchMatch) (bool, error)
+	GetMatches(ctx context.Context, savedSearchID string, page int, limit int) ([]models.SavedSearchMatch, error)
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+)
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link models.ShareLink) (models.ShareLink, error)
+	GetByID(ctx context.Context, id string) (models.ShareLink, error)
+	GetByDealer(ctx context.Context, dealerID string) ([]models.ShareLink, error)
+	Revoke(ctx context.Context, id string, dealerID string) error
+	RecordOpen(ctx context.Context, id string) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+	"time"
+)
+
+type TaskRepository interface {
+	Create(ctx context.Context, task models.Task) (models.Task, error)
+	GetByID(ctx context.Context, id string) (models.Task, error)
+	GetTasks(ctx context.Context, params models.TaskQueryParams) ([]models.Task, error)
+	GetOverdue(ctx context.Context, assigneeID string, now time.Time, page int, limit int) ([]models.Task, error)
+	Update(ctx context.Context, task models.Task) (models.Task, error)
+	Snooze(ctx context.Context, id string, dueAt time.Time, remindAt time.Time) (models.Task, error)
+	Complete(ctx context.Context, id string, completedBy string, outcome string) (models.Task, error)
+	Delete(ctx context.Context, id string) error
+	GetDueReminders(ctx context.Context, before time.Time, limit int) ([]models.Task, error)
+	GetPendingReminders(ctx context.Context) ([]models.Task, error)
+	MarkReminded(ctx context.Context, id string, remindAt time.Time) (bool, error)
+	// ClearReminded undoes MarkReminded for a reminder that could not be sent
+	ClearReminded(ctx context.Context, id string, remindAt time.Time) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"myapp/models"
+	"time"
+)
+
+type VisitRepository interface {
+	Create(ctx context.Context, visit models.Visit) (models.Visit, []models.Visit, error)
+	GetByID(ctx context.Context, id string) (models.Visit, error)
+	GetVisits(ctx context.Context, params models.VisitQueryParams) ([]models.Visit, error)
+	GetByDealerBetween(ctx context.Context, dealerID string, from, to time.Time) ([]models.Visit, error)
+	Reschedule(ctx context.Context, visit models.Visit, event models.VisitEvent) (models.Visit, []models.Visit, error)
+	Cancel(ctx context.Context, id string, reason string, event models.VisitEvent) (models.Visit, error)
+	Complete(ctx context.Context, id string, outcomeNote string, event models.VisitEvent) (models.Visit, error)
+	GetCalendarFeedVersion(ctx context.Context, dealerID string) (int64, error)
+	RotateCalendarFeed(ctx context.Context, dealerID string) (int64, error)
+}
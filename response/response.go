@@ -122,6 +122,12 @@ func WithConflict(w http.ResponseWriter, r *http.Request, message string) {
 	writeResponse(w, resp)
 }
 
+// WithConflictPayload sends conflict error response with the conflicting data
+func WithConflictPayload(w http.ResponseWriter, r *http.Request, message string, data interface{}) {
+	resp := newResponse(data, false, message, http.StatusConflict)
+	writeResponse(w, resp)
+}
+
 // Error sends error response with custom status code (for backward compatibility)
 func Error(w http.ResponseWriter, statusCode int, message string) {
 	resp := newResponse(nil, false, message, statusCode)
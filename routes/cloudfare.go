@@ -11,5 +11,11 @@ func RegisterCloudFareRoutes(r *mux.Router, h *handlers.CloudfareHandler, jwtSec
 	cloudfareRouter := r.PathPrefix("/cloudfare").Subrouter()
 	cloudfareRouter.Use(middlewares.JWTAuth(jwtSecret))
 	cloudfareRouter.HandleFunc("/presigned-urls", h.GeneratePresignedURL).Methods("POST")
+	cloudfareRouter.HandleFunc("/uploads/complete", h.CompleteUpload).Methods("POST")
+	cloudfareRouter.HandleFunc("/multipart", h.StartMultipartUpload).Methods("POST")
+	cloudfareRouter.HandleFunc("/multipart/parts", h.PresignUploadParts).Methods("POST")
+	cloudfareRouter.HandleFunc("/multipart/parts", h.GetUploadedParts).Methods("GET")
+	cloudfareRouter.HandleFunc("/multipart/complete", h.CompleteMultipartUpload).Methods("POST")
+	cloudfareRouter.HandleFunc("/multipart/abort", h.AbortMultipartUpload).Methods("POST")
 
 }
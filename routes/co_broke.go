@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterCoBrokeRoutes(r *mux.Router, h *handlers.CoBrokeHandler, jwtSecret string) {
+	coBroke := r.PathPrefix("/co-broke").Subrouter()
+	coBroke.Use(middlewares.JWTAuth(jwtSecret))
+	coBroke.Use(middlewares.RequireRole("dealer"))
+	coBroke.HandleFunc("/listings/{id}", h.SetListingTerms).Methods("PUT")
+	coBroke.HandleFunc("/pool", h.GetPool).Methods("GET")
+	coBroke.HandleFunc("/agreements", h.RequestCollaboration).Methods("POST")
+	coBroke.HandleFunc("/agreements", h.GetAgreements).Methods("GET")
+	coBroke.HandleFunc("/agreements/{id}", h.GetAgreement).Methods("GET")
+	coBroke.HandleFunc("/agreements/{id}/{action}", h.UpdateAgreement).Methods("POST")
+}
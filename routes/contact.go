@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterContactRoutes(r *mux.Router, h *handlers.ContactHandler, jwtSecret string) {
+	// A person's records span dealers, so only admins see them together
+	contactRouter := r.PathPrefix("/contacts").Subrouter()
+	contactRouter.Use(middlewares.JWTAuth(jwtSecret))
+	contactRouter.Use(middlewares.RequireRole("admin"))
+	contactRouter.HandleFunc("/duplicates", h.GetDuplicates).Methods("GET")
+	contactRouter.HandleFunc("/{phone}", h.GetContact).Methods("GET")
+}
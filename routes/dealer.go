@@ -18,6 +18,7 @@ func RegisterDealerRoutes(r *mux.Router, h *handlers.DealerHandler, jwtSecret st
 	dealer := r.PathPrefix("/dealers").Subrouter()
 	dealer.Use(middlewares.JWTAuth(jwtSecret))
 	dealer.Use(middlewares.RequireRole("dealer"))
+	dealer.HandleFunc("/watermark", h.UpdateWatermark).Methods("PUT")
 
 	// Admin
 	admin := r.PathPrefix("/admin/dealers").Subrouter()
@@ -29,6 +30,8 @@ func RegisterDealerRoutes(r *mux.Router, h *handlers.DealerHandler, jwtSecret st
 	admin.HandleFunc("/", h.GetAllDealers).Methods("GET")
 	admin.HandleFunc("/{id}", h.UpdateDealer).Methods("PUT")
 	admin.HandleFunc("/{id}", h.DeleteDealer).Methods("DELETE")
+	admin.HandleFunc("/{id}/offboard", h.OffboardDealer).Methods("POST")
+	admin.HandleFunc("/{id}/transfers", h.GetDealerTransfers).Methods("GET")
 	admin.HandleFunc("/reset-password/{id}", h.ResetPasswordDealer).Methods("PUT")
 
 }
@@ -14,6 +14,11 @@ func RegisterDealerClientRoutes(r *mux.Router, h *handlers.DealerClientHandler,
 	dealerClientRouter.HandleFunc("", h.GetDealerClients).Methods("GET")
 	dealerClientRouter.HandleFunc("/{dealerClientID}", h.UpdateDealerClient).Methods("PUT")
 	dealerClientRouter.HandleFunc("/{dealerClientID}", h.DeleteDealerClient).Methods("DELETE")
+	dealerClientRouter.HandleFunc("/{dealerClientID}/matches", h.GetMatches).Methods("GET")
+	dealerClientRouter.HandleFunc("/{dealerClientID}/activities", h.GetActivities).Methods("GET")
+	dealerClientRouter.HandleFunc("/{dealerClientID}/activities", h.LogActivity).Methods("POST")
+	dealerClientRouter.HandleFunc("/{dealerClientID}/docs/download", h.DownloadDocument).Methods("GET")
+	dealerClientRouter.HandleFunc("/{dealerClientID}/docs/access-log", h.GetDocumentAccessLog).Methods("GET")
 	dealerClientRouter.HandleFunc("/{dealerClientID}/properties", h.CreateDealerClientPropertyInterest).Methods("POST")
 	dealerClientRouter.HandleFunc("/{dealerClientID}/properties/{propertyInterestID}", h.UpdateDealerClientPropertyInterest).Methods("PUT")
 	dealerClientRouter.HandleFunc("/{dealerClientID}/properties/{propertyInterestID}", h.DeleteDealerClientPropertyInterest).Methods("DELETE")
@@ -22,4 +22,5 @@ func SetupInquiryRoutes(router *mux.Router, h *handlers.InquiryHandler, jwtSecre
 	inquiryRouter.HandleFunc("/{id}", h.GetInquiryByID).Methods("GET")
 	inquiryRouter.HandleFunc("/{id}", h.UpdateInquiry).Methods("PUT")
 	inquiryRouter.HandleFunc("/{id}", h.DeleteInquiry).Methods("DELETE")
+	inquiryRouter.HandleFunc("/{id}/convert", h.ConvertInquiry).Methods("POST")
 }
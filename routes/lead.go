@@ -12,12 +12,19 @@ func RegisterLeadRoutes(r *mux.Router, h *handlers.LeadHandler, jwtSecret string
 	leadRouter := r.PathPrefix("/leads").Subrouter()
 	leadRouter.Use(authMW)
 	leadRouter.HandleFunc("", h.GetLeads).Methods("GET")
+	leadRouter.HandleFunc("/{id}/matches", h.GetMatches).Methods("GET")
+	leadRouter.HandleFunc("/{id}/activities", h.GetActivities).Methods("GET")
+	leadRouter.HandleFunc("/{id}/activities", h.LogActivity).Methods("POST")
 	
 
 	
 	
 
 	
+	dealerRouter := leadRouter.PathPrefix("/assigned").Subrouter()
+	dealerRouter.Use(middlewares.RequireRole("dealer"))
+	dealerRouter.HandleFunc("", h.GetAssignedLeads).Methods("GET")
+
 	adminRouter := leadRouter.PathPrefix("/admin").Subrouter()
 	adminRouter.Use(middlewares.RequireRole("admin"))
 	adminRouter.HandleFunc("/", h.CreateLead).Methods("POST")
@@ -26,5 +33,11 @@ func RegisterLeadRoutes(r *mux.Router, h *handlers.LeadHandler, jwtSecret string
 	adminRouter.HandleFunc("/{leadID}", h.UpdateLead).Methods("PUT")
 	adminRouter.HandleFunc("/{leadID}/properties", h.AddPropertyInterest).Methods("POST")
 	adminRouter.HandleFunc("/{leadID}/properties/{propertyID}", h.UpdatePropertyInterest).Methods("PUT")
+	adminRouter.HandleFunc("/{leadID}/assignment", h.AssignLead).Methods("PUT")
+	adminRouter.HandleFunc("/{leadID}/route", h.RouteLead).Methods("POST")
+	adminRouter.HandleFunc("/{leadID}/assignments", h.GetAssignments).Methods("GET")
+	adminRouter.HandleFunc("/{leadID}/merge", h.MergeLead).Methods("POST")
+	adminRouter.HandleFunc("/{leadID}/aadhar/reveal", h.RevealAadhar).Methods("POST")
+	adminRouter.HandleFunc("/{leadID}/aadhar/access-log", h.GetAadharAccessLog).Methods("GET")
 
 }
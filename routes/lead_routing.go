@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterLeadRoutingRoutes(r *mux.Router, h *handlers.LeadRoutingHandler, jwtSecret string) {
+	rules := r.PathPrefix("/lead-routing-rules").Subrouter()
+	rules.Use(middlewares.JWTAuth(jwtSecret))
+	rules.Use(middlewares.RequireRole("admin"))
+	rules.HandleFunc("", h.CreateRule).Methods("POST")
+	rules.HandleFunc("", h.GetRules).Methods("GET")
+	rules.HandleFunc("/{id}", h.GetRule).Methods("GET")
+	rules.HandleFunc("/{id}", h.UpdateRule).Methods("PUT")
+	rules.HandleFunc("/{id}", h.DeleteRule).Methods("DELETE")
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterMediaRoutes(r *mux.Router, h *handlers.MediaHandler, jwtSecret string) {
+	admin := r.PathPrefix("/admin/media").Subrouter()
+	admin.Use(middlewares.JWTAuth(jwtSecret))
+	admin.Use(middlewares.RequireRole("admin"))
+	admin.HandleFunc("/objects", h.ListObjects).Methods("GET")
+	admin.HandleFunc("/usage", h.GetUsage).Methods("GET")
+	admin.HandleFunc("/references", h.GetReferences).Methods("GET")
+	admin.HandleFunc("/sweep", h.SweepOrphans).Methods("POST")
+}
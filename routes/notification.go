@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterNotificationRoutes(r *mux.Router, h *handlers.NotificationHandler, jwtSecret string) {
+	notifications := r.PathPrefix("/notifications").Subrouter()
+	notifications.Use(middlewares.JWTAuth(jwtSecret))
+	notifications.HandleFunc("", h.GetNotifications).Methods("GET")
+	notifications.HandleFunc("/read-all", h.MarkAllRead).Methods("POST")
+	notifications.HandleFunc("/{id}/read", h.MarkRead).Methods("POST")
+}
@@ -14,6 +14,10 @@ func RegisterPropertyRoutes(r *mux.Router, h *handlers.PropertyHandler, jwtSecre
     
     // ✅ Single endpoint with role-based access control
     propertyRouter.HandleFunc("", h.GetProperties).Methods("GET")
+    propertyRouter.HandleFunc("/stats", h.GetStatsSummary).Methods("GET")
+    propertyRouter.HandleFunc("/{id}/brochure.pdf", h.GetBrochure).Methods("GET")
+    propertyRouter.HandleFunc("/{id}/matching-leads", h.GetMatchingLeads).Methods("GET")
+    propertyRouter.HandleFunc("/{id}/stats", h.GetStats).Methods("GET")
     
     // ✅ Role-specific operations
     dealerRouter := propertyRouter.PathPrefix("/dealer").Subrouter()
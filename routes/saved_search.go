@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterSavedSearchRoutes(r *mux.Router, h *handlers.SavedSearchHandler, jwtSecret string) {
+	searches := r.PathPrefix("/saved-searches").Subrouter()
+	searches.Use(middlewares.JWTAuth(jwtSecret))
+	searches.Use(middlewares.RequireRole("dealer"))
+	searches.HandleFunc("", h.CreateSavedSearch).Methods("POST")
+	searches.HandleFunc("", h.GetSavedSearches).Methods("GET")
+	searches.HandleFunc("/{id}", h.GetSavedSearch).Methods("GET")
+	searches.HandleFunc("/{id}", h.UpdateSavedSearch).Methods("PUT")
+	searches.HandleFunc("/{id}", h.DeleteSavedSearch).Methods("DELETE")
+	searches.HandleFunc("/{id}/results", h.RunSavedSearch).Methods("GET")
+	searches.HandleFunc("/{id}/matches", h.GetMatches).Methods("GET")
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterShareLinkRoutes(r *mux.Router, h *handlers.ShareLinkHandler, jwtSecret string) {
+	// Public
+	public := r.PathPrefix("/share").Subrouter()
+	public.HandleFunc("/{token}", h.GetSharedListing).Methods("GET")
+	public.HandleFunc("/{token}/page", h.GetSharedPage).Methods("GET")
+
+	// Dealer
+	dealer := r.PathPrefix("/share-links").Subrouter()
+	dealer.Use(middlewares.JWTAuth(jwtSecret))
+	dealer.Use(middlewares.RequireRole("dealer"))
+	dealer.HandleFunc("", h.CreateShareLink).Methods("POST")
+	dealer.HandleFunc("", h.GetShareLinks).Methods("GET")
+	dealer.HandleFunc("/{id}", h.RevokeShareLink).Methods("DELETE")
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"myapp/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterLocalStorageRoutes mounts the local storage backend; access is controlled by URL signatures, not JWTs
+func RegisterLocalStorageRoutes(r *mux.Router, h *handlers.LocalStorageHandler) {
+	storageRouter := r.PathPrefix("/storage").Subrouter()
+	storageRouter.HandleFunc("/{key:.+}", h.GetObject).Methods("GET", "HEAD")
+	storageRouter.HandleFunc("/{key:.+}", h.PutObject).Methods("PUT")
+}
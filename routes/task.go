@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterTaskRoutes(r *mux.Router, h *handlers.TaskHandler, jwtSecret string) {
+	// Dealers manage tasks assigned to them, admins any
+	taskRouter := r.PathPrefix("/tasks").Subrouter()
+	taskRouter.Use(middlewares.JWTAuth(jwtSecret))
+	taskRouter.HandleFunc("", h.CreateTask).Methods("POST")
+	taskRouter.HandleFunc("", h.GetTasks).Methods("GET")
+	taskRouter.HandleFunc("/overdue", h.GetOverdueTasks).Methods("GET")
+	taskRouter.HandleFunc("/{id}", h.GetTask).Methods("GET")
+	taskRouter.HandleFunc("/{id}", h.UpdateTask).Methods("PUT")
+	taskRouter.HandleFunc("/{id}", h.DeleteTask).Methods("DELETE")
+	taskRouter.HandleFunc("/{id}/snooze", h.SnoozeTask).Methods("POST")
+	taskRouter.HandleFunc("/{id}/complete", h.CompleteTask).Methods("POST")
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"myapp/handlers"
+	"myapp/middlewares"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterVisitRoutes(r *mux.Router, h *handlers.VisitHandler, jwtSecret string) {
+	// Public, authorised by the signed token in the path
+	r.HandleFunc("/calendar/{token}/visits.ics", h.GetCalendarFeed).Methods("GET")
+
+	// Dealers manage visits to their own listings, admins any
+	visitRouter := r.PathPrefix("/visits").Subrouter()
+	visitRouter.Use(middlewares.JWTAuth(jwtSecret))
+	visitRouter.HandleFunc("", h.ScheduleVisit).Methods("POST")
+	visitRouter.HandleFunc("", h.GetVisits).Methods("GET")
+	visitRouter.HandleFunc("/calendar-feed", h.GetCalendarFeedURL).Methods("GET")
+	visitRouter.HandleFunc("/calendar-feed/rotate", h.RotateCalendarFeedURL).Methods("POST")
+	visitRouter.HandleFunc("/{id}", h.GetVisit).Methods("GET")
+	visitRouter.HandleFunc("/{id}/reschedule", h.RescheduleVisit).Methods("POST")
+	visitRouter.HandleFunc("/{id}/cancel", h.CancelVisit).Methods("POST")
+	visitRouter.HandleFunc("/{id}/complete", h.CompleteVisit).Methods("POST")
+}
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"myapp/config"
 	"myapp/databases"
 	"myapp/handlers"
+	"myapp/middlewares"
 	"myapp/models"
 	"myapp/mongo_repositories"
 	"myapp/response"
 	"myapp/routes"
 	"myapp/services"
+	"myapp/utils"
 	"net/http"
 	"time"
 
@@ -21,6 +25,9 @@ import (
 
 func main() {
 	cfg := config.LoadConfig()
+	if err := utils.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	client := databases.ConnectMongo(cfg.MongoURI)
 
 	redisClient, err := databases.ConnectRedis(cfg.RedisURI, cfg.RedisUsername, cfg.RedisPassword)
@@ -30,12 +37,12 @@ func main() {
 		log.Printf("✅ Redis connected successfully")
 	}
 
-	r2Service, err := services.NewCloudflareR2Service(cfg.CloudflareAccountID, cfg.CloudflareAccessKeyID, cfg.CloudflareAccessKeySecret, cfg.CloudflareBucketName)
+	storage, publicURL, localStorage, err := newObjectStorage(cfg)
 	if err != nil {
-		log.Printf("⚠️  R2 service failed: %v", err)
+		log.Printf("⚠️  %s storage failed: %v", cfg.StorageBackend, err)
 
 	} else {
-		log.Printf("✅ R2 service connected successfully")
+		log.Printf("✅ %s storage connected successfully", cfg.StorageBackend)
 	}
 
 	dealerCollection := client.Database(cfg.MongoDB).Collection("dealers")
@@ -45,6 +52,27 @@ func main() {
 	counterCollection := client.Database(cfg.MongoDB).Collection("counters")
 	dealerClientCollection := client.Database(cfg.MongoDB).Collection("dealer_clients")
 	inquiryCollection := client.Database(cfg.MongoDB).Collection("inquiries")
+	mediaCollection := client.Database(cfg.MongoDB).Collection("media")
+	mediaQuotaCollection := client.Database(cfg.MongoDB).Collection("media_quotas")
+	documentAccessCollection := client.Database(cfg.MongoDB).Collection("document_access_logs")
+	shareLinkCollection := client.Database(cfg.MongoDB).Collection("share_links")
+	visitCollection := client.Database(cfg.MongoDB).Collection("visits")
+	visitLockCollection := client.Database(cfg.MongoDB).Collection("visit_locks")
+	calendarFeedCollection := client.Database(cfg.MongoDB).Collection("calendar_feeds")
+	coBrokeCollection := client.Database(cfg.MongoDB).Collection("co_broke_agreements")
+	coBrokeLockCollection := client.Database(cfg.MongoDB).Collection("co_broke_locks")
+	notificationCollection := client.Database(cfg.MongoDB).Collection("notifications")
+	savedSearchCollection := client.Database(cfg.MongoDB).Collection("saved_searches")
+	savedSearchMatchCollection := client.Database(cfg.MongoDB).Collection("saved_search_matches")
+	propertyStatsCollection := client.Database(cfg.MongoDB).Collection("property_stats")
+	dealerTransferCollection := client.Database(cfg.MongoDB).Collection("dealer_transfers")
+	leadRoutingRuleCollection := client.Database(cfg.MongoDB).Collection("lead_routing_rules")
+	leadAssignmentCollection := client.Database(cfg.MongoDB).Collection("lead_assignments")
+	leadAssignmentLockCollection := client.Database(cfg.MongoDB).Collection("lead_assignment_locks")
+	taskCollection := client.Database(cfg.MongoDB).Collection("tasks")
+	leadMergeCollection := client.Database(cfg.MongoDB).Collection("lead_merges")
+	activityCollection := client.Database(cfg.MongoDB).Collection("activities")
+	aadharAccessCollection := client.Database(cfg.MongoDB).Collection("aadhar_access_logs")
 
 	// Initialize repositories
 	dealerRepo := mongo_repositories.NewMongoDealerRepository(dealerCollection)
@@ -53,6 +81,22 @@ func main() {
 	// tokenRepo := mongo_repositories.NewMongoTokenRepository(tokenCollection)
 	dealerClientRepo := mongo_repositories.NewMongoDealerClientRepository(dealerClientCollection)
 	inquiryRepo := mongo_repositories.NewMongoInquiryRepository(inquiryCollection)
+	mediaRepo := mongo_repositories.NewMongoMediaRepository(mediaCollection, mediaQuotaCollection)
+	documentAccessRepo := mongo_repositories.NewMongoDocumentAccessLogRepository(documentAccessCollection)
+	shareLinkRepo := mongo_repositories.NewMongoShareLinkRepository(shareLinkCollection)
+	visitRepo := mongo_repositories.NewMongoVisitRepository(visitCollection, visitLockCollection, calendarFeedCollection)
+	coBrokeRepo := mongo_repositories.NewMongoCoBrokeRepository(coBrokeCollection, coBrokeLockCollection)
+	notificationRepo := mongo_repositories.NewMongoNotificationRepository(notificationCollection)
+	savedSearchRepo := mongo_repositories.NewMongoSavedSearchRepository(savedSearchCollection, savedSearchMatchCollection)
+	propertyStatsRepo := mongo_repositories.NewMongoPropertyStatsRepository(propertyStatsCollection)
+	dealerTransferRepo := mongo_repositories.NewMongoDealerTransferRepository(dealerTransferCollection, dealerCollection, propertyCollection,
+		dealerClientCollection, leadCollection, inquiryCollection, savedSearchCollection, shareLinkCollection, leadAssignmentCollection, taskCollection, visitCollection, coBrokeCollection)
+	leadRoutingRepo := mongo_repositories.NewMongoLeadRoutingRepository(leadRoutingRuleCollection, leadAssignmentCollection, leadCollection, leadAssignmentLockCollection)
+	taskRepo := mongo_repositories.NewMongoTaskRepository(taskCollection)
+	contactRepo := mongo_repositories.NewMongoContactRepository(leadCollection, inquiryCollection, dealerClientCollection,
+		visitCollection, taskCollection, leadAssignmentCollection, leadMergeCollection, activityCollection)
+	activityRepo := mongo_repositories.NewMongoActivityRepository(activityCollection)
+	aadharAccessRepo := mongo_repositories.NewMongoAadharAccessLogRepository(aadharAccessCollection)
 
 	// Initialize services with repositories
 	dealerService := &services.DealerService{
@@ -60,35 +104,137 @@ func main() {
 		// TokenRepo:  tokenRepo,
 		JWTSecret: cfg.JWTSecret,
 	}
-	dealerHandler := &handlers.DealerHandler{Service: dealerService}
 
+	analyticsService := services.NewAnalyticsService(propertyStatsRepo, propertyRepo, redisClient)
+	requirementParser := services.NewRequirementParser(dealerRepo)
+	activityService := services.NewActivityService(activityRepo, leadRepo, dealerClientRepo)
+	// A wrong key would make stored numbers unreadable, so it stops the server rather than being skipped
+	aadharService, err := services.NewAadharService(cfg.AadharEncryptionKeys, cfg.AadharKeyVersion, cfg.AadharLookupKey, leadRepo, aadharAccessRepo)
+	if err != nil {
+		log.Fatalf("Invalid Aadhaar encryption keys: %v", err)
+	}
+	// Stored numbers would otherwise stay readable in plain text or unreadable under missing keys
+	if err := aadharService.CheckStored(context.Background()); err != nil {
+		log.Fatalf("AADHAR_ENCRYPTION_KEYS and AADHAR_LOOKUP_KEY must be set: %v", err)
+	}
+	if !aadharService.Configured() {
+		log.Printf("⚠️  AADHAR_ENCRYPTION_KEYS is not set; leads cannot be saved with Aadhaar numbers")
+	}
+	// Numbers saved in plain text or under a rotated-out key are moved to the current key
+	if updated, err := aadharService.ReencryptStale(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to encrypt stored Aadhaar numbers: %v", err)
+	} else if updated > 0 {
+		log.Printf("aadhar: encrypted %d stored numbers with the current key", updated)
+	}
 	leadService := &services.LeadService{
 		Repo: leadRepo,
 		PropertyRepo: propertyRepo,
+		Parser: requirementParser,
+		Analytics: analyticsService,
+		Activities: activityService,
+		Aadhar: aadharService,
 	}
 
+	notificationService := services.NewNotificationService(notificationRepo, redisClient)
+	savedSearchService := services.NewSavedSearchService(savedSearchRepo, propertyRepo, dealerClientRepo, notificationService)
+	leadRoutingService := services.NewLeadRoutingService(leadRoutingRepo, leadRepo, dealerRepo, notificationService)
+	leadService.Routing = leadRoutingService
+
 	propertyService := &services.PropertyService{
-		Repo:        propertyRepo,
-		RedisClient: redisClient,
+		Repo:          propertyRepo,
+		DealerRepo:    dealerRepo,
+		RedisClient:   redisClient,
+		SavedSearches: savedSearchService,
 	}
 	dealerClientService := &services.DealerClientService{
 		Repo: dealerClientRepo,
 		PropertyRepo: propertyRepo,
+		AccessLogRepo: documentAccessRepo,
+		Analytics: analyticsService,
+		Activities: activityService,
+	}
+
+	var mediaService *services.MediaService
+	var brochureService *services.BrochureService
+	if storage != nil {
+		mediaService = services.NewMediaService(mediaRepo, storage)
+		mediaService.References = mongo_repositories.NewMongoMediaReferenceRepository(propertyCollection, dealerClientCollection, dealerCollection, leadCollection)
+		propertyService.ImageProcessor = services.NewImageProcessingService(storage, publicURL)
+		propertyService.Media = mediaService
+		dealerClientService.Media = mediaService
+		brochureService = &services.BrochureService{PropertyRepo: propertyRepo, DealerRepo: dealerRepo, Storage: storage, Analytics: analyticsService}
+
+		// Abort multipart uploads abandoned for more than a day so their parts stop accruing storage
+		go mediaService.RunUploadCleanup(context.Background(), time.Hour, 24*time.Hour)
+		// The scheduled sweep only reports orphans unless deletion is switched on with ORPHAN_MEDIA_SWEEP_DELETE=true
+		if cfg.OrphanMediaRetainDays > 0 {
+			go mediaService.RunOrphanSweep(context.Background(), 24*time.Hour, time.Duration(cfg.OrphanMediaRetainDays)*24*time.Hour, !cfg.OrphanMediaSweepDelete)
+		}
+	}
+	inquiryService := services.NewInquiryService(inquiryRepo, requirementParser, leadService, dealerClientService)
+	// Share tokens are public, so their key must not also sign logins
+	if cfg.ShareLinkSecret == "" || cfg.ShareLinkSecret == cfg.JWTSecret {
+		log.Fatalf("SHARE_LINK_SECRET must be set and differ from JWT_SECRET")
+	}
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, propertyRepo, dealerRepo, analyticsService, cfg.ShareLinkSecret, publicURL)
+	// Feed links are public, so their key must not also sign logins
+	if cfg.CalendarFeedSecret == "" || cfg.CalendarFeedSecret == cfg.JWTSecret {
+		log.Fatalf("CALENDAR_FEED_SECRET must be set and differ from JWT_SECRET")
+	}
+	visitService := services.NewVisitService(visitRepo, propertyRepo, leadRepo, dealerClientRepo, dealerRepo, cfg.CalendarFeedSecret)
+	coBrokeService := services.NewCoBrokeService(coBrokeRepo, propertyRepo, dealerRepo, analyticsService, publicURL)
+	matchingService := services.NewMatchingService(propertyRepo, dealerRepo, leadRepo, inquiryRepo, dealerClientRepo)
+	offboardingService := services.NewDealerOffboardingService(dealerRepo, dealerTransferRepo, propertyService, redisClient)
+	taskService := services.NewTaskService(taskRepo, leadRepo, dealerClientRepo, propertyRepo, dealerRepo, notificationService, redisClient)
+
+	// Reminders queued before a Redis restart would otherwise never fire
+	if err := taskService.RestoreReminders(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to restore task reminders: %v", err)
+	}
+	go taskService.RunReminderScheduler(context.Background(), time.Minute)
+	contactService := services.NewContactService(contactRepo, activityService)
+
+	// Records saved before contact keys existed are linked once; later ones are keyed on save
+	if err := contactService.BackfillContactKeys(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to link existing records to contacts: %v", err)
+	}
+
+	// Tokens of offboarded dealers stay signed, so every request is checked against the disabled set
+	if err := offboardingService.RestoreDisabledDealers(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to restore disabled dealers: %v", err)
 	}
-	inquiryService := services.NewInquiryService(inquiryRepo)
+	middlewares.SetAccountCheck(offboardingService.IsAccountActive)
+
+	dealerHandler := &handlers.DealerHandler{Service: dealerService, PropertyService: propertyService, MediaService: mediaService, Offboarding: offboardingService}
 
 	leadHandler := &handlers.LeadHandler{
 		Service:         leadService,
 		PropertyService: propertyService,
+		Matching:        matchingService,
+		Routing:         leadRoutingService,
+		Contacts:        contactService,
+		Activities:      activityService,
+		Aadhar:          aadharService,
 	}
+	leadRoutingHandler := handlers.NewLeadRoutingHandler(leadRoutingService)
 
-	propertyHandler := &handlers.PropertyHandler{Service: propertyService, CloudflarePublicURL: cfg.CloudflarePublicURL, DealerService: dealerService}
+	propertyHandler := &handlers.PropertyHandler{Service: propertyService, CloudflarePublicURL: publicURL, DealerService: dealerService, Brochures: brochureService, Matching: matchingService, Analytics: analyticsService}
 
-	dealerClientHandler := &handlers.DealerClientHandler{Service: dealerClientService, CloudflarePublicURL: cfg.CloudflarePublicURL}
+	dealerClientHandler := &handlers.DealerClientHandler{Service: dealerClientService, Matching: matchingService, Activities: activityService}
 	inquiryHandler := handlers.NewInquiryHandler(inquiryService)
+	shareLinkHandler := handlers.NewShareLinkHandler(shareLinkService, cfg.AppURL)
+	visitHandler := handlers.NewVisitHandler(visitService, cfg.AppURL)
+	coBrokeHandler := handlers.NewCoBrokeHandler(coBrokeService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
+	taskHandler := handlers.NewTaskHandler(taskService)
+	contactHandler := handlers.NewContactHandler(contactService)
+
+	mediaHandler := &handlers.MediaHandler{Service: mediaService, OrphanRetainDays: cfg.OrphanMediaRetainDays}
 
 	cloudfareHandler := &handlers.CloudfareHandler{
-		Service: r2Service,
+		Service:      storage,
+		MediaService: mediaService,
 	}
 
 	r := mux.NewRouter()
@@ -127,10 +273,22 @@ func main() {
 
 	routes.RegisterDealerRoutes(r, dealerHandler, cfg.JWTSecret)
 	routes.RegisterLeadRoutes(r, leadHandler, cfg.JWTSecret)
+	routes.RegisterLeadRoutingRoutes(r, leadRoutingHandler, cfg.JWTSecret)
 	routes.RegisterPropertyRoutes(r, propertyHandler, cfg.JWTSecret)
 	routes.RegisterCloudFareRoutes(r, cloudfareHandler, cfg.JWTSecret)
+	routes.RegisterMediaRoutes(r, mediaHandler, cfg.JWTSecret)
 	routes.RegisterDealerClientRoutes(r, dealerClientHandler, cfg.JWTSecret)
 	routes.SetupInquiryRoutes(r, inquiryHandler, cfg.JWTSecret)
+	routes.RegisterShareLinkRoutes(r, shareLinkHandler, cfg.JWTSecret)
+	routes.RegisterVisitRoutes(r, visitHandler, cfg.JWTSecret)
+	routes.RegisterCoBrokeRoutes(r, coBrokeHandler, cfg.JWTSecret)
+	routes.RegisterNotificationRoutes(r, notificationHandler, cfg.JWTSecret)
+	routes.RegisterSavedSearchRoutes(r, savedSearchHandler, cfg.JWTSecret)
+	routes.RegisterTaskRoutes(r, taskHandler, cfg.JWTSecret)
+	routes.RegisterContactRoutes(r, contactHandler, cfg.JWTSecret)
+	if localStorage != nil {
+		routes.RegisterLocalStorageRoutes(r, &handlers.LocalStorageHandler{Storage: localStorage})
+	}
 
 	corsHandler := h.CORS(
 		h.AllowedOrigins([]string{"*"}),
@@ -141,3 +299,34 @@ func main() {
 	log.Printf("🚀 Server running on port %s\n", cfg.Port)
 	log.Fatal(http.ListenAndServe(":"+cfg.Port, corsHandler))
 }
+
+// newObjectStorage builds the backend named by STORAGE_BACKEND and the public URL prefix its objects are served from.
+// The local backend is also returned on its own since its routes must be mounted on the API.
+func newObjectStorage(cfg config.Config) (services.ObjectStorage, string, *services.LocalStorageService, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		// Download signatures must not be forgeable by anyone holding the JWT secret, and vice versa
+		if cfg.LocalStorageSecret == cfg.JWTSecret {
+			return nil, "", nil, fmt.Errorf("LOCAL_STORAGE_SECRET must be set and differ from JWT_SECRET")
+		}
+		local, err := services.NewLocalStorageService(cfg.LocalStorageDir, cfg.LocalStorageURL, cfg.LocalStorageSecret)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return local, local.PublicURL(), local, nil
+	case "s3":
+		s3Service, err := services.NewS3CompatibleService(cfg.S3Endpoint, cfg.S3Region, cfg.S3AccessKeyID, cfg.S3AccessKeySecret, cfg.S3BucketName, cfg.S3UsePathStyle)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return s3Service, cfg.S3PublicURL, nil, nil
+	case "r2":
+		r2Service, err := services.NewCloudflareR2Service(cfg.CloudflareAccountID, cfg.CloudflareAccessKeyID, cfg.CloudflareAccessKeySecret, cfg.CloudflareBucketName)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return r2Service, cfg.CloudflarePublicURL, nil, nil
+	default:
+		return nil, "", nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAadharInvalid       = errors.New("invalid Aadhaar number")
+	ErrAadharNotConfigured = errors.New("Aadhaar encryption is not configured")
+	ErrAadharNotFound      = errors.New("lead has no Aadhaar details")
+)
+
+const reencryptBatchSize = 100
+
+// AadharService keeps lead Aadhaar numbers encrypted at rest with AES-256-GCM. Every key has a
+// version stored next to the number it encrypted, so keys can be rotated: add a key with a new
+// version, make it current, and numbers under older keys are re-encrypted at the next start.
+// Old keys must stay configured until that has run. Numbers are also hashed with a separate
+// lookup key that does not change with rotation, so searches keep working while numbers move
+// between keys. Full numbers only leave through Reveal, which is audited.
+type AadharService struct {
+	keys       map[int][]byte
+	current    int
+	lookupKey  []byte
+	lookupID   string
+	leadRepo   repositories.LeadRepository
+	accessRepo repositories.AadharAccessLogRepository
+}
+
+// NewAadharService takes the keys as comma-separated version:key pairs, each key being 32 bytes
+// in base64, e.g. "1:3q2+7w...,2:yv66vg...". currentVersion picks the key for new numbers; 0
+// means the highest version. lookupKey is a further 32 bytes in base64 for the lookup hash and
+// is required with the keys. Without keys Aadhaar numbers cannot be saved.
+func NewAadharService(keys string, currentVersion int, lookupKey string, leadRepo repositories.LeadRepository, accessRepo repositories.AadharAccessLogRepository) (*AadharService, error) {
+	s := &AadharService{
+		keys:       make(map[int][]byte),
+		leadRepo:   leadRepo,
+		accessRepo: accessRepo,
+	}
+
+	for _, pair := range strings.Split(keys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		versionText, encoded, found := strings.Cut(pair, ":")
+		version, err := strconv.Atoi(strings.TrimSpace(versionText))
+		if !found || err != nil || version < 1 {
+			return nil, fmt.Errorf("Aadhaar key %q must look like <version>:<base64 key> with a version of 1 or more", versionText)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("Aadhaar key version %d must be 32 bytes in base64", version)
+		}
+		if _, exists := s.keys[version]; exists {
+			return nil, fmt.Errorf("Aadhaar key version %d is given twice", version)
+		}
+		s.keys[version] = key
+		if currentVersion == 0 && version > s.current {
+			s.current = version
+		}
+	}
+
+	if currentVersion != 0 {
+		if _, ok := s.keys[currentVersion]; !ok {
+			return nil, fmt.Errorf("current Aadhaar key version %d is not configured", currentVersion)
+		}
+		s.current = currentVersion
+	}
+
+	lookupKey = strings.TrimSpace(lookupKey)
+	if (lookupKey == "") != (s.current == 0) {
+		return nil, errors.New("the Aadhaar lookup key and encryption keys must be set together")
+	}
+	if lookupKey != "" {
+		key, err := base64.StdEncoding.DecodeString(lookupKey)
+		if err != nil || len(key) != 32 {
+			return nil, errors.New("the Aadhaar lookup key must be 32 bytes in base64")
+		}
+		for version, encryptionKey := range s.keys {
+			if hmac.Equal(key, encryptionKey) {
+				return nil, fmt.Errorf("the Aadhaar lookup key must differ from encryption key version %d", version)
+			}
+		}
+		s.lookupKey = key
+		// Stored with each hash so that a change of lookup key is picked up by ReencryptStale
+		id := hmac.New(sha256.New, key)
+		id.Write([]byte("aadhar-lookup-id"))
+		s.lookupID = hex.EncodeToString(id.Sum(nil))[:16]
+	}
+	return s, nil
+}
+
+func (s *AadharService) Configured() bool {
+	return s != nil && s.current != 0
+}
+
+// CheckStored fails when numbers are stored but the service has no keys to read or protect them
+func (s *AadharService) CheckStored(ctx context.Context) error {
+	if s.Configured() {
+		return nil
+	}
+	stored, err := s.leadRepo.HasAadharNumbers(ctx)
+	if err != nil {
+		return err
+	}
+	if stored {
+		return fmt.Errorf("%w: leads have Aadhaar numbers stored", ErrAadharNotConfigured)
+	}
+	return nil
+}
+
+// Seal validates an Aadhaar number as entered and returns it encrypted for storage
+func (s *AadharService) Seal(number string) (models.StoredAadhar, error) {
+	normalized := utils.NormalizeAadhar(number)
+	if normalized == "" {
+		return models.StoredAadhar{}, fmt.Errorf("%w: it must have 12 digits", ErrAadharInvalid)
+	}
+	if !utils.ValidAadhar(normalized) {
+		return models.StoredAadhar{}, fmt.Errorf("%w: the check digit does not match", ErrAadharInvalid)
+	}
+	if !s.Configured() {
+		return models.StoredAadhar{}, ErrAadharNotConfigured
+	}
+	return s.seal(normalized)
+}
+
+// LookupHash returns the keyed hash leads are searched by, or "" if the number is not valid
+func (s *AadharService) LookupHash(number string) string {
+	normalized := utils.NormalizeAadhar(number)
+	if normalized == "" || !s.Configured() {
+		return ""
+	}
+	return s.hash(normalized)
+}
+
+// Reveal returns a lead's full Aadhaar number and photo. The reveal is written to the audit log
+// first, and nothing is revealed if it cannot be.
+func (s *AadharService) Reveal(ctx context.Context, leadID string, adminID string, reason string, ip string, userAgent string) (models.AadharReveal, error) {
+	lead, err := s.getLead(ctx, leadID)
+	if err != nil {
+		return models.AadharReveal{}, err
+	}
+	if lead.StoredAadhar.Ciphertext == "" && lead.StoredAadhar.Photo == "" {
+		return models.AadharReveal{}, ErrAadharNotFound
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		reason = reason[:500]
+	}
+	now := time.Now()
+	err = s.accessRepo.Create(ctx, models.AadharAccessLog{
+		LeadID:     lead.ID,
+		RevealedBy: adminID,
+		Reason:     reason,
+		IP:         ip,
+		UserAgent:  userAgent,
+		RevealedAt: now,
+	})
+	if err != nil {
+		return models.AadharReveal{}, fmt.Errorf("failed to record Aadhaar access: %w", err)
+	}
+
+	number, err := s.open(lead.StoredAadhar)
+	if err != nil {
+		return models.AadharReveal{}, err
+	}
+	return models.AadharReveal{
+		LeadID:       lead.ID,
+		AadharNumber: number,
+		AadharPhoto:  lead.StoredAadhar.Photo,
+		RevealedAt:   now,
+	}, nil
+}
+
+// GetAccessLog returns the most recent reveals of a lead's Aadhaar details
+func (s *AadharService) GetAccessLog(ctx context.Context, leadID string) ([]models.AadharAccessLog, error) {
+	if _, err := s.getLead(ctx, leadID); err != nil {
+		return nil, err
+	}
+	return s.accessRepo.GetByLead(ctx, leadID, 100)
+}
+
+// ReencryptStale encrypts numbers saved before encryption, moves numbers under older keys to the
+// current one and re-hashes numbers hashed with another lookup key, returning how many were
+// updated. A number whose key is no longer configured
+// is logged and left as it is.
+func (s *AadharService) ReencryptStale(ctx context.Context) (int, error) {
+	if !s.Configured() {
+		return 0, nil
+	}
+
+	updated, afterID := 0, ""
+	for {
+		leads, err := s.leadRepo.GetStaleAadhar(ctx, s.current, s.lookupID, afterID, reencryptBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		for _, lead := range leads {
+			number, err := s.open(lead.StoredAadhar)
+			if err != nil {
+				log.Printf("⚠️  Cannot re-encrypt the Aadhaar number of lead %s: %v", lead.ID, err)
+				continue
+			}
+			if normalized := utils.NormalizeAadhar(number); normalized != "" {
+				number = normalized
+			}
+			stored, err := s.seal(number)
+			if err != nil {
+				return updated, err
+			}
+			// A number changed in the meantime is already under the current key
+			ok, err := s.leadRepo.SetAadharNumber(ctx, lead.ID, stored, lead.StoredAadhar.Ciphertext)
+			if err != nil {
+				return updated, err
+			}
+			if ok {
+				updated++
+			}
+		}
+		if len(leads) < reencryptBatchSize {
+			return updated, nil
+		}
+		afterID = leads[len(leads)-1].ID
+	}
+}
+
+func (s *AadharService) getLead(ctx context.Context, leadID string) (models.Lead, error) {
+	lead, err := s.leadRepo.GetByID(ctx, leadID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.Lead{}, ErrLeadNotFound
+		}
+		return models.Lead{}, err
+	}
+	return lead, nil
+}
+
+func (s *AadharService) seal(number string) (models.StoredAadhar, error) {
+	gcm, err := s.cipher(s.current)
+	if err != nil {
+		return models.StoredAadhar{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return models.StoredAadhar{}, err
+	}
+	// The version is authenticated too, so a number cannot be passed off as another key's
+	sealed := gcm.Seal(nonce, nonce, []byte(number), aadharAdditionalData(s.current))
+
+	last4 := number
+	if len(last4) > 4 {
+		last4 = last4[len(last4)-4:]
+	}
+	return models.StoredAadhar{
+		Ciphertext: base64.StdEncoding.EncodeToString(sealed),
+		KeyVersion: s.current,
+		Last4:      last4,
+		Hash:       s.hash(number),
+		HashKeyID:  s.lookupID,
+	}, nil
+}
+
+func (s *AadharService) open(stored models.StoredAadhar) (string, error) {
+	if stored.Ciphertext == "" || stored.KeyVersion == 0 {
+		// Not encrypted yet
+		return stored.Ciphertext, nil
+	}
+	gcm, err := s.cipher(stored.KeyVersion)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(stored.Ciphertext)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("stored Aadhaar number is corrupt")
+	}
+	number, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], aadharAdditionalData(stored.KeyVersion))
+	if err != nil {
+		return "", errors.New("stored Aadhaar number is corrupt")
+	}
+	return string(number), nil
+}
+
+func (s *AadharService) cipher(version int) (cipher.AEAD, error) {
+	key, ok := s.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("Aadhaar key version %d is not configured", version)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// hash is an HMAC of the number under the lookup key, so equal numbers can be found without
+// decrypting every lead. It does not depend on the encryption key, so it survives rotation.
+func (s *AadharService) hash(number string) string {
+	mac := hmac.New(sha256.New, s.lookupKey)
+	mac.Write([]byte(number))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func aadharAdditionalData(version int) []byte {
+	return []byte("aadhar:v" + strconv.Itoa(version))
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func randomAadharKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestAadharSealOpen(t *testing.T) {
+	service, err := NewAadharService("1:"+randomAadharKey(t), 0, randomAadharKey(t), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := service.Seal("2341 2341 2346")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.KeyVersion != 1 || stored.Last4 != "2346" || stored.Hash == "" || stored.HashKeyID == "" {
+		t.Errorf("stored = %+v, want version 1, last 4 digits and a hash", stored)
+	}
+	if stored.Ciphertext == "" || stored.Ciphertext == "234123412346" {
+		t.Errorf("ciphertext = %q, want the number encrypted", stored.Ciphertext)
+	}
+	if stored.Hash != service.LookupHash("2341-2341-2346") {
+		t.Error("lookup hash differs from the stored hash")
+	}
+
+	number, err := service.open(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != "234123412346" {
+		t.Errorf("opened %q, want 234123412346", number)
+	}
+
+	if _, err := service.Seal("234123412345"); !errors.Is(err, ErrAadharInvalid) {
+		t.Errorf("sealing a bad check digit: err = %v, want ErrAadharInvalid", err)
+	}
+}
+
+func TestAadharKeyRotation(t *testing.T) {
+	oldKey, newKey, lookupKey := randomAadharKey(t), randomAadharKey(t), randomAadharKey(t)
+
+	before, err := NewAadharService("1:"+oldKey, 0, lookupKey, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := before.Seal("234123412346")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewAadharService("1:"+oldKey+",2:"+newKey, 2, lookupKey, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, err := after.open(stored)
+	if err != nil {
+		t.Fatalf("opening a number under the old key: %v", err)
+	}
+	resealed, err := after.seal(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resealed.KeyVersion != 2 {
+		t.Errorf("re-sealed under version %d, want 2", resealed.KeyVersion)
+	}
+	if resealed.Hash != stored.Hash || resealed.HashKeyID != stored.HashKeyID {
+		t.Error("hash changed with the encryption key, want it stable across rotation")
+	}
+	if reopened, err := after.open(resealed); err != nil || reopened != number {
+		t.Errorf("reopened %q, %v, want %q", reopened, err, number)
+	}
+
+	// The old key alone cannot read what was re-sealed
+	if _, err := before.open(resealed); err == nil {
+		t.Error("opened a number under a key that is not configured")
+	}
+}
+
+func TestNewAadharServiceKeys(t *testing.T) {
+	key := randomAadharKey(t)
+	tests := []struct {
+		name      string
+		keys      string
+		lookupKey string
+		wantErr   bool
+	}{
+		{"not configured", "", "", false},
+		{"keys and lookup key", "1:" + key, randomAadharKey(t), false},
+		{"keys without lookup key", "1:" + key, "", true},
+		{"lookup key without keys", "", randomAadharKey(t), true},
+		{"lookup key reused as encryption key", "1:" + key, key, true},
+		{"short lookup key", "1:" + key, base64.StdEncoding.EncodeToString([]byte("short")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAadharService(tt.keys, 0, tt.lookupKey, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrActivityInvalid         = errors.New("invalid activity")
+	ErrActivitySubjectNotFound = errors.New("lead or client not found")
+	ErrActivityLeadNotVisible  = errors.New("lead is not assigned to you or interested in your properties")
+)
+
+// activitySorts are the fields a timeline can be ordered by
+var activitySorts = []string{"occurred_at", "created_at"}
+
+// Logged activities may be backdated, e.g. a call noted after the fact, but not dated ahead
+const activityClockSkew = 5 * time.Minute
+
+// ActivityService keeps the timeline of each lead and dealer client: what people logged, such as
+// calls and notes, and what the lead and dealer client services recorded as the record changed.
+type ActivityService struct {
+	repo             repositories.ActivityRepository
+	leadRepo         repositories.LeadRepository
+	dealerClientRepo repositories.DealerClientRepository
+}
+
+func NewActivityService(repo repositories.ActivityRepository, leadRepo repositories.LeadRepository, dealerClientRepo repositories.DealerClientRepository) *ActivityService {
+	return &ActivityService{
+		repo:             repo,
+		leadRepo:         leadRepo,
+		dealerClientRepo: dealerClientRepo,
+	}
+}
+
+// LogActivity adds a call, WhatsApp message, visit or note to the timeline of a lead or client the
+// caller may work on
+func (s *ActivityService) LogActivity(ctx context.Context, subjectType string, subjectID string, userID string, role string, req models.ActivityRequest) (models.Activity, error) {
+	if err := s.checkSubject(ctx, subjectType, subjectID, userID, role); err != nil {
+		return models.Activity{}, err
+	}
+
+	activity := models.Activity{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Type:        strings.ToLower(strings.TrimSpace(req.Type)),
+		Body:        strings.TrimSpace(req.Body),
+		PropertyID:  strings.TrimSpace(req.PropertyID),
+		AuthorID:    userID,
+		OccurredAt:  time.Now(),
+	}
+	if !containsFold(models.ActivityLoggedTypes, activity.Type) {
+		return models.Activity{}, fmt.Errorf("%w: type must be one of %s", ErrActivityInvalid, strings.Join(models.ActivityLoggedTypes, ", "))
+	}
+	if activity.Type == models.ActivityNote && activity.Body == "" {
+		return models.Activity{}, fmt.Errorf("%w: a note needs a body", ErrActivityInvalid)
+	}
+	if len(activity.Body) > 2000 {
+		return models.Activity{}, fmt.Errorf("%w: body must be at most 2000 characters", ErrActivityInvalid)
+	}
+	if activity.PropertyID != "" && !primitive.IsValidObjectID(activity.PropertyID) {
+		return models.Activity{}, fmt.Errorf("%w: property_id must be a valid ID", ErrActivityInvalid)
+	}
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(activity.OccurredAt.Add(activityClockSkew)) {
+			return models.Activity{}, fmt.Errorf("%w: occurred_at cannot be in the future", ErrActivityInvalid)
+		}
+		activity.OccurredAt = *req.OccurredAt
+	}
+
+	return s.repo.Create(ctx, activity)
+}
+
+// GetActivities pages through the timeline of a lead or client the caller may work on, newest first
+func (s *ActivityService) GetActivities(ctx context.Context, subjectType string, subjectID string, userID string, role string, params models.ActivityQueryParams) ([]models.Activity, error) {
+	if err := s.checkSubject(ctx, subjectType, subjectID, userID, role); err != nil {
+		return nil, err
+	}
+	params.SubjectType = &subjectType
+	params.SubjectID = &subjectID
+	params.SetDefaults()
+	if !utils.Contains(activitySorts, *params.Sort) {
+		return nil, fmt.Errorf("%w: sort must be one of %s", ErrActivityInvalid, strings.Join(activitySorts, ", "))
+	}
+	return s.repo.GetActivities(ctx, params)
+}
+
+// Record adds a system event to a timeline. The change it describes has already been saved, so a
+// failure is logged rather than returned.
+func (s *ActivityService) Record(ctx context.Context, activity models.Activity) {
+	if s == nil || activity.SubjectID == "" {
+		return
+	}
+	activity.System = true
+	if _, err := s.repo.Create(ctx, activity); err != nil {
+		log.Printf("⚠️  Failed to record %s activity for %s %s: %v", activity.Type, activity.SubjectType, activity.SubjectID, err)
+	}
+}
+
+// activityBody joins an event's summary with the note or reason given for it
+func activityBody(summary string, details ...string) string {
+	for _, detail := range details {
+		if detail = strings.TrimSpace(detail); detail != "" {
+			summary += "\n" + detail
+		}
+	}
+	return summary
+}
+
+// checkSubject makes sure the lead or client exists and the caller may work on it: admins any,
+// dealers the leads they can see and their own clients
+func (s *ActivityService) checkSubject(ctx context.Context, subjectType string, subjectID string, userID string, role string) error {
+	switch subjectType {
+	case models.ActivitySubjectLead:
+		lead, err := s.leadRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			return notFoundAs(err, ErrActivitySubjectNotFound)
+		}
+		if role == constants.Admin {
+			return nil
+		}
+		visible, err := dealerCanSeeLead(ctx, s.leadRepo, lead, userID)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			return ErrActivityLeadNotVisible
+		}
+		return nil
+	case models.ActivitySubjectDealerClient:
+		client, err := s.dealerClientRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			return notFoundAs(err, ErrActivitySubjectNotFound)
+		}
+		if role != constants.Admin && client.DealerID != userID {
+			return ErrDealerClientNotOwned
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown subject type %q", ErrActivityInvalid, subjectType)
+}
+
+// notFoundAs reports a missing document or malformed ID as notFound
+func notFoundAs(err error, notFound error) error {
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return notFound
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrStatsRangeInvalid = errors.New("days must be between 1 and 90")
+
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 90
+	// Unique-viewer sketches outlive the longest range that can be asked for
+	viewerSketchTTL = (maxStatsDays + 1) * 24 * time.Hour
+	// Recording runs after the request has been answered and must not pile up if storage is slow
+	recordTimeout = 5 * time.Second
+
+	statsDateLayout = "2006-01-02"
+)
+
+// Stats are dated in Indian time so a day matches the dealers' working day
+var statsZone = time.FixedZone("IST", 5*60*60+30*60)
+
+// AnalyticsService counts engagement with listings: daily counters in Mongo and unique viewers
+// in Redis HyperLogLogs, one per property, audience and day. Recording is best effort and never
+// slows down or fails the request that triggered it.
+type AnalyticsService struct {
+	repo         repositories.PropertyStatsRepository
+	propertyRepo repositories.PropertyRepository
+	redisClient  *redis.Client
+}
+
+func NewAnalyticsService(repo repositories.PropertyStatsRepository, propertyRepo repositories.PropertyRepository, redisClient *redis.Client) *AnalyticsService {
+	return &AnalyticsService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		redisClient:  redisClient,
+	}
+}
+
+// Record counts an event in the background. viewerID identifies the viewer for unique counts of
+// view events and may be empty.
+func (s *AnalyticsService) Record(propertyID string, dealerID string, event string, viewerID string) {
+	if s == nil || propertyID == "" || dealerID == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		defer cancel()
+
+		date := time.Now().In(statsZone).Format(statsDateLayout)
+		if err := s.repo.Increment(ctx, propertyID, dealerID, date, event); err != nil {
+			log.Printf("⚠️  Failed to record %s for property %s: %v", event, propertyID, err)
+		}
+
+		audience := viewerAudience(event)
+		if audience == "" || viewerID == "" || s.redisClient == nil {
+			return
+		}
+		key := viewerSketchKey(audience, propertyID, date)
+		pipe := s.redisClient.TxPipeline()
+		pipe.PFAdd(ctx, key, viewerID)
+		pipe.Expire(ctx, key, viewerSketchTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("⚠️  Failed to record viewer for property %s: %v", propertyID, err)
+		}
+	}()
+}
+
+// RecordDealerViews counts in-app views of the given listings by userID, skipping the owner's own
+func (s *AnalyticsService) RecordDealerViews(properties []models.Property, userID string) {
+	for _, property := range properties {
+		if property.DealerID != userID {
+			s.Record(property.ID, property.DealerID, models.PropertyEventDealerView, userID)
+		}
+	}
+}
+
+// PublicViewerID turns a visitor's address and browser into an opaque ID for unique counts,
+// so neither is stored
+func PublicViewerID(ip string, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+// GetPropertyStats returns a listing's engagement per day over the last days, today included.
+// Dealers may only see their own listings.
+func (s *AnalyticsService) GetPropertyStats(ctx context.Context, propertyID string, userID string, role string, days int) (models.PropertyStats, error) {
+	from, to, dates, err := statsRange(days)
+	if err != nil {
+		return models.PropertyStats{}, err
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return models.PropertyStats{}, err
+	}
+	if role == constants.Dealer && property.DealerID != userID {
+		return models.PropertyStats{}, ErrPropertyNotOwned
+	}
+
+	recorded, err := s.repo.GetByProperty(ctx, property.ID, from, to)
+	if err != nil {
+		return models.PropertyStats{}, err
+	}
+	byDate := make(map[string]models.PropertyDayStats, len(recorded))
+	for _, day := range recorded {
+		byDate[day.Date] = day
+	}
+
+	publicViewers := s.countViewersByDay(ctx, models.PropertyEventPublicView, property.ID, dates)
+	dealerViewers := s.countViewersByDay(ctx, models.PropertyEventDealerView, property.ID, dates)
+
+	stats := models.PropertyStats{PropertyID: property.ID, From: from, To: to, Days: make([]models.PropertyDayStats, 0, len(dates))}
+	for _, date := range dates {
+		day, ok := byDate[date]
+		if !ok {
+			day = models.PropertyDayStats{Date: date}
+		}
+		day.PropertyID = ""
+		day.UniquePublicViewers = publicViewers[date]
+		day.UniqueDealerViewers = dealerViewers[date]
+		addCounts(&stats.Totals, day.EngagementCounts)
+		stats.Days = append(stats.Days, day)
+	}
+	stats.Totals.UniquePublicViewers = s.countViewers(ctx, models.PropertyEventPublicView, property.ID, dates...)
+	stats.Totals.UniqueDealerViewers = s.countViewers(ctx, models.PropertyEventDealerView, property.ID, dates...)
+	return stats, nil
+}
+
+// GetDealerSummary totals engagement per listing of the dealer over the last days. Listings
+// with no engagement in the range are left out.
+func (s *AnalyticsService) GetDealerSummary(ctx context.Context, dealerID string, days int) (models.DealerStatsSummary, error) {
+	from, to, dates, err := statsRange(days)
+	if err != nil {
+		return models.DealerStatsSummary{}, err
+	}
+
+	recorded, err := s.repo.GetByDealer(ctx, dealerID, from, to)
+	if err != nil {
+		return models.DealerStatsSummary{}, err
+	}
+
+	totals := make(map[string]*models.PropertyStatsSummary)
+	var propertyIDs []string
+	for _, day := range recorded {
+		summary, ok := totals[day.PropertyID]
+		if !ok {
+			summary = &models.PropertyStatsSummary{PropertyID: day.PropertyID}
+			totals[day.PropertyID] = summary
+			propertyIDs = append(propertyIDs, day.PropertyID)
+		}
+		addCounts(&summary.Totals, day.EngagementCounts)
+	}
+
+	properties, err := s.propertiesByID(ctx, propertyIDs)
+	if err != nil {
+		return models.DealerStatsSummary{}, err
+	}
+
+	result := models.DealerStatsSummary{DealerID: dealerID, From: from, To: to, Properties: make([]models.PropertyStatsSummary, 0, len(propertyIDs))}
+	for _, id := range propertyIDs {
+		summary := totals[id]
+		if property, ok := properties[id]; ok {
+			summary.PropertyNumber = property.PropertyNumber
+			summary.Title = property.Title
+		}
+		summary.Totals.UniquePublicViewers = s.countViewers(ctx, models.PropertyEventPublicView, id, dates...)
+		summary.Totals.UniqueDealerViewers = s.countViewers(ctx, models.PropertyEventDealerView, id, dates...)
+		// Viewers are unique per listing; the same person viewing two listings counts twice here
+		addCounts(&result.Totals, summary.Totals)
+		result.Totals.UniquePublicViewers += summary.Totals.UniquePublicViewers
+		result.Totals.UniqueDealerViewers += summary.Totals.UniqueDealerViewers
+		result.Properties = append(result.Properties, *summary)
+	}
+
+	sort.SliceStable(result.Properties, func(i, j int) bool {
+		a, b := result.Properties[i].Totals, result.Properties[j].Totals
+		return a.PublicViews+a.DealerViews > b.PublicViews+b.DealerViews
+	})
+	return result, nil
+}
+
+// propertiesByID loads the number and title of each listing; listings removed since are missing
+func (s *AnalyticsService) propertiesByID(ctx context.Context, ids []string) (map[string]models.Property, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	properties := make(map[string]models.Property, len(ids))
+	if len(objectIDs) == 0 {
+		return properties, nil
+	}
+
+	found, err := s.propertyRepo.GetFilteredProperties(ctx, bson.M{"_id": bson.M{"$in": objectIDs}},
+		bson.M{"property_number": 1, "title": 1}, int64(len(objectIDs)), 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range found {
+		properties[property.ID] = property
+	}
+	return properties, nil
+}
+
+// countViewers counts distinct viewers across the given days; Redis merges the sketches
+func (s *AnalyticsService) countViewers(ctx context.Context, event string, propertyID string, dates ...string) int64 {
+	if s.redisClient == nil || len(dates) == 0 {
+		return 0
+	}
+	audience := viewerAudience(event)
+	keys := make([]string, 0, len(dates))
+	for _, date := range dates {
+		keys = append(keys, viewerSketchKey(audience, propertyID, date))
+	}
+	count, err := s.redisClient.PFCount(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("⚠️  Failed to count viewers for property %s: %v", propertyID, err)
+		return 0
+	}
+	return count
+}
+
+// countViewersByDay counts distinct viewers on each day in one round trip
+func (s *AnalyticsService) countViewersByDay(ctx context.Context, event string, propertyID string, dates []string) map[string]int64 {
+	counts := make(map[string]int64, len(dates))
+	if s.redisClient == nil {
+		return counts
+	}
+
+	audience := viewerAudience(event)
+	pipe := s.redisClient.Pipeline()
+	commands := make(map[string]*redis.IntCmd, len(dates))
+	for _, date := range dates {
+		commands[date] = pipe.PFCount(ctx, viewerSketchKey(audience, propertyID, date))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("⚠️  Failed to count viewers for property %s: %v", propertyID, err)
+		return counts
+	}
+	for date, command := range commands {
+		counts[date] = command.Val()
+	}
+	return counts
+}
+
+func viewerAudience(event string) string {
+	switch event {
+	case models.PropertyEventPublicView:
+		return "public"
+	case models.PropertyEventDealerView:
+		return "dealer"
+	}
+	return ""
+}
+
+func viewerSketchKey(audience string, propertyID string, date string) string {
+	return "property_viewers:" + audience + ":" + propertyID + ":" + date
+}
+
+// statsRange returns the first and last date of the last days, today included, and every date between
+func statsRange(days int) (string, string, []string, error) {
+	if days == 0 {
+		days = defaultStatsDays
+	}
+	if days < 1 || days > maxStatsDays {
+		return "", "", nil, ErrStatsRangeInvalid
+	}
+
+	today := time.Now().In(statsZone)
+	dates := make([]string, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		dates = append(dates, today.AddDate(0, 0, -i).Format(statsDateLayout))
+	}
+	return dates[0], dates[len(dates)-1], dates, nil
+}
+
+func addCounts(total *models.EngagementCounts, counts models.EngagementCounts) {
+	total.PublicViews += counts.PublicViews
+	total.DealerViews += counts.DealerViews
+	total.BrochureDownloads += counts.BrochureDownloads
+	total.Interests += counts.Interests
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"log"
+	"strconv"
+	"strings"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+)
+
+var ErrPropertyNotOwned = errors.New("property belongs to another dealer")
+
+const (
+	brochureKeyPrefix     = "brochures/"
+	maxBrochureBytes      = 20 << 20
+	maxBrochurePhotos     = 4
+	brochurePhotoSide     = 800
+	brochureMargin        = 40.0
+	brochureLayoutVersion = 1 // bump to regenerate every cached brochure after a layout change
+)
+
+// Brochure is a rendered property brochure ready to be served
+type Brochure struct {
+	FileName string
+	Data     []byte
+}
+
+// BrochureService renders one-page PDF brochures for walk-in clients and caches them in object storage
+type BrochureService struct {
+	PropertyRepo repositories.PropertyRepository
+	DealerRepo   repositories.DealerRepository
+	Storage      ObjectStorage
+	Analytics    *AnalyticsService
+}
+
+// GetBrochure returns the brochure for a property. Dealers can only fetch their own listings.
+// The cached copy is keyed by a fingerprint of everything printed on it, so any change to the
+// property, its photos or the dealer's contact details produces a fresh brochure.
+func (s *BrochureService) GetBrochure(ctx context.Context, propertyID string, userID string, role string) (Brochure, error) {
+	property, err := s.PropertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return Brochure{}, err
+	}
+	if role == constants.Dealer && property.DealerID != userID {
+		return Brochure{}, ErrPropertyNotOwned
+	}
+
+	dealer, err := s.DealerRepo.GetByID(ctx, property.DealerID)
+	if err != nil {
+		return Brochure{}, err
+	}
+
+	brochure, err := s.cachedBrochure(ctx, property, dealer)
+	if err != nil {
+		return Brochure{}, err
+	}
+	s.Analytics.Record(property.ID, property.DealerID, models.PropertyEventBrochureDownload, "")
+	return brochure, nil
+}
+
+// cachedBrochure serves the stored brochure, rendering and storing it when the listing or
+// dealer branding changed since
+func (s *BrochureService) cachedBrochure(ctx context.Context, property models.Property, dealer models.Dealer) (Brochure, error) {
+	brochure := Brochure{FileName: fmt.Sprintf("property-%d.pdf", property.PropertyNumber)}
+	key, err := brochureKey(property, dealer)
+	if err != nil {
+		return Brochure{}, err
+	}
+
+	data, _, err := s.Storage.DownloadObject(ctx, key, maxBrochureBytes)
+	if err == nil {
+		brochure.Data = data
+		return brochure, nil
+	}
+	if !errors.Is(err, ErrObjectNotFound) {
+		log.Printf("failed to read cached brochure %s: %v", key, err)
+	}
+
+	brochure.Data, err = s.render(ctx, property, dealer)
+	if err != nil {
+		return Brochure{}, err
+	}
+
+	if err := s.Storage.UploadObject(ctx, key, brochure.Data, "application/pdf"); err != nil {
+		log.Printf("failed to cache brochure %s: %v", key, err)
+		return brochure, nil
+	}
+	s.removeStaleBrochures(ctx, property.ID, key)
+
+	return brochure, nil
+}
+
+func brochureKey(property models.Property, dealer models.Dealer) (string, error) {
+	fingerprint, err := json.Marshal([]interface{}{
+		brochureLayoutVersion,
+		property.PropertyNumber, property.Title, property.Description, property.MinPrice, property.MaxPrice,
+		property.Photos, property.NearestLandmark, property.Sold, property.Area, property.Bedrooms,
+		property.Bathrooms, property.PropertyType, property.Furnishing, property.Floor, property.TotalFloors,
+		property.Facing, property.Parking, property.PropertyAge, property.Amenities,
+		dealer.Name, dealer.ShopName, dealer.Phone, dealer.OfficeAddress,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(fingerprint)
+	return fmt.Sprintf("%s%s/%s.pdf", brochureKeyPrefix, property.ID, hex.EncodeToString(sum[:12])), nil
+}
+
+// removeStaleBrochures deletes earlier versions of a property's brochure once a new one is cached
+func (s *BrochureService) removeStaleBrochures(ctx context.Context, propertyID string, currentKey string) {
+	page, err := s.Storage.ListObjects(ctx, brochureKeyPrefix+propertyID+"/", "", 100)
+	if err != nil {
+		log.Printf("failed to list cached brochures for property %s: %v", propertyID, err)
+		return
+	}
+	for _, object := range page.Objects {
+		if object.Key == currentKey {
+			continue
+		}
+		if err := s.Storage.DeleteObject(ctx, object.Key); err != nil {
+			log.Printf("failed to delete stale brochure %s: %v", object.Key, err)
+		}
+	}
+}
+
+func (s *BrochureService) render(ctx context.Context, property models.Property, dealer models.Dealer) ([]byte, error) {
+	doc := utils.NewPDFDocument(property.Title)
+	width := utils.PDFPageWidth - 2*brochureMargin
+	shopName := dealer.ShopName
+	if shopName == "" {
+		shopName = dealer.Name
+	}
+
+	// Dealer header band
+	doc.SetFillColor(33, 37, 41)
+	doc.Rect(0, 0, utils.PDFPageWidth, 72)
+	doc.SetFillColor(255, 255, 255)
+	doc.Text(brochureMargin, 34, 20, true, shopName)
+	doc.Text(brochureMargin, 54, 11, false, joinNonEmpty("  |  ", dealer.Name, dealer.Phone))
+	reference := fmt.Sprintf("Ref. #%d", property.PropertyNumber)
+	doc.Text(utils.PDFPageWidth-brochureMargin-utils.PDFTextWidth(reference, 11, false), 54, 11, false, reference)
+
+	y := 108.0
+	doc.SetFillColor(33, 37, 41)
+	for _, line := range utils.WrapPDFText(property.Title, 20, true, width, 2) {
+		doc.Text(brochureMargin, y, 20, true, line)
+		y += 24
+	}
+	doc.SetFillColor(0, 110, 70)
+	price := utils.FormatIndianPriceRange(property.MinPrice, property.MaxPrice)
+	if property.Sold {
+		price += "  (Sold)"
+	}
+	doc.Text(brochureMargin, y+2, 16, true, price)
+	y += 22
+
+	photos := s.loadBrochurePhotos(ctx, property.Photos)
+	if len(photos) > 0 {
+		const gap = 10.0
+		cellW, cellH := (width-gap)/2, (width-gap)/2*0.66
+		if len(photos) == 1 {
+			cellW, cellH = width, width*0.5
+		}
+		for i, data := range photos {
+			x := brochureMargin + float64(i%2)*(cellW+gap)
+			top := y + float64(i/2)*(cellH+gap)
+			doc.SetFillColor(240, 240, 240)
+			doc.Rect(x, top, cellW, cellH)
+			if err := doc.JPEG(data, x, top, cellW, cellH); err != nil {
+				return nil, err
+			}
+		}
+		y += float64((len(photos)+1)/2)*(cellH+gap) + 4
+	} else {
+		y += 10
+	}
+
+	y = brochureSection(doc, "Key details", y)
+	specs := brochureSpecs(property)
+	columnW := width / 2
+	for i, spec := range specs {
+		x := brochureMargin + float64(i%2)*columnW
+		doc.SetFillColor(110, 110, 110)
+		doc.Text(x, y, 10, false, spec[0])
+		doc.SetFillColor(33, 37, 41)
+		doc.Text(x+90, y, 10, true, spec[1])
+		if i%2 == 1 || i == len(specs)-1 {
+			y += 17
+		}
+	}
+
+	if property.NearestLandmark != "" {
+		doc.SetFillColor(110, 110, 110)
+		doc.Text(brochureMargin, y, 10, false, "Nearest landmark")
+		doc.SetFillColor(33, 37, 41)
+		for _, line := range utils.WrapPDFText(property.NearestLandmark, 10, true, width-90, 2) {
+			doc.Text(brochureMargin+90, y, 10, true, line)
+			y += 14
+		}
+		y += 3
+	}
+
+	if len(property.Amenities) > 0 {
+		amenities := make([]string, len(property.Amenities))
+		for i, amenity := range property.Amenities {
+			amenities[i] = humanize(amenity)
+		}
+		doc.SetFillColor(110, 110, 110)
+		doc.Text(brochureMargin, y, 10, false, "Amenities")
+		doc.SetFillColor(33, 37, 41)
+		for _, line := range utils.WrapPDFText(strings.Join(amenities, ", "), 10, false, width-90, 2) {
+			doc.Text(brochureMargin+90, y, 10, false, line)
+			y += 14
+		}
+		y += 3
+	}
+
+	// The description gets whatever room is left above the footer
+	const footerTop = 782.0
+	if property.Description != "" && y < footerTop-70 {
+		y = brochureSection(doc, "About this property", y+4)
+		maxLines := int((footerTop - 8 - y) / 13)
+		doc.SetFillColor(60, 60, 60)
+		for _, line := range utils.WrapPDFText(property.Description, 10, false, width, maxLines) {
+			doc.Text(brochureMargin, y, 10, false, line)
+			y += 13
+		}
+	}
+
+	doc.SetStrokeColor(200, 200, 200)
+	doc.Line(brochureMargin, footerTop, utils.PDFPageWidth-brochureMargin, footerTop, 0.75)
+	doc.SetFillColor(33, 37, 41)
+	doc.Text(brochureMargin, footerTop+20, 11, true, "For a site visit contact "+joinNonEmpty(", ", shopName, dealer.Phone))
+	if dealer.OfficeAddress != "" {
+		doc.SetFillColor(110, 110, 110)
+		for _, line := range utils.WrapPDFText(dealer.OfficeAddress, 9, false, width, 1) {
+			doc.Text(brochureMargin, footerTop+35, 9, false, line)
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// loadBrochurePhotos fetches up to maxBrochurePhotos listing photos as baseline JPEGs, preferring the
+// watermarked medium variant. Photos that were never uploaded to storage or fail to load are skipped.
+func (s *BrochureService) loadBrochurePhotos(ctx context.Context, photos []models.Photo) [][]byte {
+	var result [][]byte
+	for _, photo := range photos {
+		if len(result) == maxBrochurePhotos {
+			break
+		}
+		if photo.Key == "" {
+			continue
+		}
+
+		key := photo.Key
+		if _, ok := photo.Variants[models.PhotoVariantMedium]; ok {
+			key = PhotoVariantKey(photo.Key, models.PhotoVariantMedium)
+		}
+		data, _, err := s.Storage.DownloadObject(ctx, key, maxOriginalPhotoBytes)
+		if err != nil {
+			log.Printf("failed to load brochure photo %s: %v", key, err)
+			continue
+		}
+
+		// Re-encoding guarantees an 8-bit baseline JPEG the PDF can embed directly, whatever was uploaded
+		img, err := decodePhoto(data)
+		if err != nil {
+			log.Printf("failed to decode brochure photo %s: %v", key, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, utils.ResizeToFit(img, brochurePhotoSide), &jpeg.Options{Quality: 80}); err != nil {
+			log.Printf("failed to encode brochure photo %s: %v", key, err)
+			continue
+		}
+		result = append(result, buf.Bytes())
+	}
+	return result
+}
+
+// brochureSection draws a section heading with a rule under it and returns where its content starts
+func brochureSection(doc *utils.PDFDocument, title string, y float64) float64 {
+	doc.SetFillColor(33, 37, 41)
+	doc.Text(brochureMargin, y+12, 13, true, title)
+	doc.SetStrokeColor(200, 200, 200)
+	doc.Line(brochureMargin, y+18, utils.PDFPageWidth-brochureMargin, y+18, 0.75)
+	return y + 34
+}
+
+// brochureSpecs lists the label/value pairs the property actually has
+func brochureSpecs(property models.Property) [][2]string {
+	var specs [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			specs = append(specs, [2]string{label, value})
+		}
+	}
+
+	add("Type", humanize(property.PropertyType))
+	if property.Bedrooms > 0 {
+		add("Configuration", fmt.Sprintf("%d BHK", property.Bedrooms))
+	}
+	if property.Bathrooms > 0 {
+		add("Bathrooms", strconv.Itoa(property.Bathrooms))
+	}
+	if property.Area > 0 {
+		add("Area", strconv.FormatFloat(property.Area, 'f', -1, 64)+" sq ft")
+	}
+	add("Furnishing", humanize(property.Furnishing))
+	if property.TotalFloors > 0 {
+		add("Floor", fmt.Sprintf("%d of %d", property.Floor, property.TotalFloors))
+	} else if property.Floor > 0 {
+		add("Floor", strconv.Itoa(property.Floor))
+	}
+	add("Facing", humanize(property.Facing))
+	if property.Parking > 0 {
+		add("Parking", strconv.Itoa(property.Parking))
+	}
+	if property.PropertyAge > 0 {
+		add("Age", fmt.Sprintf("%d years", property.PropertyAge))
+	}
+	return specs
+}
+
+// humanize turns stored values such as "semi_furnished" into "Semi Furnished"
+func humanize(value string) string {
+	words := strings.Fields(strings.ReplaceAll(value, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -4,7 +4,9 @@ package services
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"time"
 
@@ -12,9 +14,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
-// CloudflareR2Service handles Cloudflare R2 operations
+// CloudflareR2Service handles Cloudflare R2 operations. It speaks plain S3, so the same client
+// also serves any S3-compatible endpoint such as MinIO via NewS3CompatibleService.
 type CloudflareR2Service struct {
 	client     *s3.Client
 	bucketName string
@@ -38,29 +43,49 @@ func NewCloudflareR2Service(AccountID string, AccessKeyID string, AccessKeySecre
 		return nil, fmt.Errorf("missing required Cloudflare R2 configuration")
 	}
 
+	service, err := NewS3CompatibleService(fmt.Sprintf("https://%s.r2.cloudflarestorage.com", AccountID), "auto", AccessKeyID, AccessKeySecret, BucketName, false)
+	if err != nil {
+		return nil, err
+	}
+	service.accountID = AccountID
+
+	return service, nil
+}
+
+// NewS3CompatibleService connects to a bucket on any S3 API endpoint. MinIO and most
+// self-hosted servers need path-style addressing since buckets are not DNS names there.
+func NewS3CompatibleService(endpoint string, region string, accessKeyID string, accessKeySecret string, bucketName string, usePathStyle bool) (*CloudflareR2Service, error) {
+	if endpoint == "" || accessKeyID == "" || accessKeySecret == "" || bucketName == "" {
+		return nil, fmt.Errorf("missing required S3 storage configuration")
+	}
+
+	if region == "" {
+		region = "us-east-1"
+	}
+
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(AccessKeyID, AccessKeySecret, "")),
-		config.WithRegion("auto"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyID, accessKeySecret, "")),
+		config.WithRegion(region),
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	// Create S3 client with R2 endpoint
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(fmt.Sprintf("https://%s.r2.cloudflarestorage.com", AccountID))
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = usePathStyle
 	})
 
 	return &CloudflareR2Service{
 		client:     client,
-		bucketName: BucketName,
-		accountID:  AccountID,
+		bucketName: bucketName,
 	}, nil
 }
 
-// GeneratePresignedURL generates a presigned URL for uploading objects
-func (s *CloudflareR2Service) GeneratePresignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+// GeneratePresignedURL signs a PUT for key. Content type and length are part of the signature,
+// so the upload is rejected by storage unless the client sends exactly those headers.
+func (s *CloudflareR2Service) GeneratePresignedURL(ctx context.Context, key string, contentType string, contentLength int64, expires time.Duration) (string, error) {
 	if key == "" {
 		return "", fmt.Errorf("key cannot be empty")
 	}
@@ -74,8 +99,10 @@ func (s *CloudflareR2Service) GeneratePresignedURL(ctx context.Context, key stri
 
 	// Generate presigned PUT URL for upload
 	presignResult, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(key),
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(contentLength),
 	}, func(opts *s3.PresignOptions) {
 		opts.Expires = expires
 	})
@@ -88,6 +115,141 @@ func (s *CloudflareR2Service) GeneratePresignedURL(ctx context.Context, key stri
 	return presignResult.URL, nil
 }
 
+// CreateMultipartUpload starts a multipart upload for key and returns its upload ID
+func (s *CloudflareR2Service) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("key cannot be empty")
+	}
+
+	result, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	return aws.ToString(result.UploadId), nil
+}
+
+// GeneratePresignedPartURL signs a PUT for one part; the part length is part of the signature
+func (s *CloudflareR2Service) GeneratePresignedPartURL(ctx context.Context, key string, uploadID string, partNumber int32, contentLength int64, expires time.Duration) (string, error) {
+	if key == "" || uploadID == "" {
+		return "", fmt.Errorf("key and upload ID cannot be empty")
+	}
+
+	if expires <= 0 {
+		expires = 1 * time.Hour
+	}
+
+	presignClient := s3.NewPresignClient(s.client)
+	presignResult, err := presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(partNumber),
+		ContentLength: aws.Int64(contentLength),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expires
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned part URL: %w", err)
+	}
+
+	return presignResult.URL, nil
+}
+
+// ListUploadedParts returns the parts storage holds for an upload, used to resume after a dropped connection
+func (s *CloudflareR2Service) ListUploadedParts(ctx context.Context, key string, uploadID string) ([]UploadedPart, error) {
+	paginator := s3.NewListPartsPaginator(s.client, &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+
+	var parts []UploadedPart
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+		for _, part := range page.Parts {
+			parts = append(parts, UploadedPart{
+				PartNumber: aws.ToInt32(part.PartNumber),
+				ETag:       aws.ToString(part.ETag),
+				Size:       aws.ToInt64(part.Size),
+			})
+		}
+	}
+
+	return parts, nil
+}
+
+// CompleteMultipartUpload assembles the given parts into the final object
+func (s *CloudflareR2Service) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []UploadedPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		completed[i] = types.CompletedPart{
+			PartNumber: aws.Int32(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		}
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucketName),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload discards an upload and every part stored for it
+func (s *CloudflareR2Service) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// ListIncompleteUploads returns every multipart upload in the bucket started before the cutoff
+func (s *CloudflareR2Service) ListIncompleteUploads(ctx context.Context, startedBefore time.Time) ([]IncompleteUpload, error) {
+	paginator := s3.NewListMultipartUploadsPaginator(s.client, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucketName),
+	})
+
+	var uploads []IncompleteUpload
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list multipart uploads: %w", err)
+		}
+		for _, upload := range page.Uploads {
+			initiated := aws.ToTime(upload.Initiated)
+			if initiated.Before(startedBefore) {
+				uploads = append(uploads, IncompleteUpload{
+					Key:       aws.ToString(upload.Key),
+					UploadID:  aws.ToString(upload.UploadId),
+					Initiated: initiated,
+				})
+			}
+		}
+	}
+
+	return uploads, nil
+}
+
 // GeneratePresignedGetURL generates a presigned URL for downloading objects
 func (s *CloudflareR2Service) GeneratePresignedGetURL(ctx context.Context, key string, expires time.Duration) (string, error) {
 	if key == "" {
@@ -148,6 +310,43 @@ func (s *CloudflareR2Service) UploadObject(ctx context.Context, key string, data
 	return nil
 }
 
+// DownloadObject reads an object from R2, refusing objects larger than maxBytes
+func (s *CloudflareR2Service) DownloadObject(ctx context.Context, key string, maxBytes int64) ([]byte, string, error) {
+	if key == "" {
+		return nil, "", fmt.Errorf("key cannot be empty")
+	}
+
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, "", fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return nil, "", fmt.Errorf("failed to get object: %w", err)
+	}
+	defer result.Body.Close()
+
+	if maxBytes > 0 && result.ContentLength != nil && *result.ContentLength > maxBytes {
+		return nil, "", fmt.Errorf("object exceeds %d bytes", maxBytes)
+	}
+
+	reader := io.Reader(result.Body)
+	if maxBytes > 0 {
+		reader = io.LimitReader(result.Body, maxBytes+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read object: %w", err)
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("object exceeds %d bytes", maxBytes)
+	}
+
+	return data, aws.ToString(result.ContentType), nil
+}
+
 // DeleteObject deletes an object from R2
 func (s *CloudflareR2Service) DeleteObject(ctx context.Context, key string) error {
 	if key == "" {
@@ -167,38 +366,48 @@ func (s *CloudflareR2Service) DeleteObject(ctx context.Context, key string) erro
 	return nil
 }
 
-// ListObjects lists objects in the bucket
-func (s *CloudflareR2Service) ListObjects(ctx context.Context, prefix string, maxKeys int32) ([]string, error) {
+// ListObjects lists one page of objects under prefix, continuing from a previous page's token
+func (s *CloudflareR2Service) ListObjects(ctx context.Context, prefix string, continuationToken string, maxKeys int32) (ObjectPage, error) {
 	if maxKeys <= 0 {
 		maxKeys = 1000 // Default max keys
 	}
 
 	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(s.bucketName),
-		// MaxKeys: maxKeys,
+		Bucket:  aws.String(s.bucketName),
+		MaxKeys: aws.Int32(maxKeys),
 	}
 
 	if prefix != "" {
 		input.Prefix = aws.String(prefix)
 	}
+	if continuationToken != "" {
+		input.ContinuationToken = aws.String(continuationToken)
+	}
 
 	result, err := s.client.ListObjectsV2(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %w", err)
+		return ObjectPage{}, fmt.Errorf("failed to list objects: %w", err)
 	}
 
-	var keys []string
+	page := ObjectPage{Objects: make([]ObjectInfo, 0, len(result.Contents))}
 	for _, object := range result.Contents {
-		keys = append(keys, *object.Key)
+		page.Objects = append(page.Objects, ObjectInfo{
+			Key:          aws.ToString(object.Key),
+			Size:         aws.ToInt64(object.Size),
+			LastModified: aws.ToTime(object.LastModified),
+		})
+	}
+	if aws.ToBool(result.IsTruncated) {
+		page.NextToken = aws.ToString(result.NextContinuationToken)
 	}
 
-	return keys, nil
+	return page, nil
 }
 
 // GetObjectInfo gets information about an object
-func (s *CloudflareR2Service) GetObjectInfo(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+func (s *CloudflareR2Service) GetObjectInfo(ctx context.Context, key string) (ObjectInfo, error) {
 	if key == "" {
-		return nil, fmt.Errorf("key cannot be empty")
+		return ObjectInfo{}, fmt.Errorf("key cannot be empty")
 	}
 
 	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
@@ -207,10 +416,18 @@ func (s *CloudflareR2Service) GetObjectInfo(ctx context.Context, key string) (*s
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get object info: %w", err)
+		if isS3NotFound(err) {
+			return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to get object info: %w", err)
 	}
 
-	return result, nil
+	return ObjectInfo{
+		Key:          key,
+		ContentType:  aws.ToString(result.ContentType),
+		Size:         aws.ToInt64(result.ContentLength),
+		LastModified: aws.ToTime(result.LastModified),
+	}, nil
 }
 
 // GetBucketInfo gets information about the bucket
@@ -225,3 +442,14 @@ func (s *CloudflareR2Service) GetBucketInfo(ctx context.Context) (*s3.HeadBucket
 
 	return result, nil
 }
+
+func isS3NotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey":
+			return true
+		}
+	}
+	return false
+}
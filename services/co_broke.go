@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"myapp/converters"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrCoBrokeInvalid       = errors.New("invalid co-broke request")
+	ErrCoBrokeNotFound      = errors.New("co-broke agreement not found")
+	ErrCoBrokeNotParty      = errors.New("you are not a party to this co-broke agreement")
+	ErrCoBrokeUnavailable   = errors.New("listing is not open for co-broking")
+	ErrCoBrokeDuplicate     = errors.New("you already have an open co-broke request for this listing")
+	ErrCoBrokeInvalidAction = errors.New("action is not allowed in the agreement's current status")
+)
+
+const defaultCommissionSplit = 50
+
+// coBrokePoolSorts are the fields the pool can be ordered by. Ordering on anything else, such as the
+// owner details hidden from partners, would reveal them through the order of the results.
+var coBrokePoolSorts = []string{"created_at", "updated_at", "min_price", "max_price", "area", "bedrooms", "property_number"}
+
+// Actions on an agreement, each allowed from one status by one side
+const (
+	CoBrokeActionAccept    = "accept"
+	CoBrokeActionDecline   = "decline"
+	CoBrokeActionWithdraw  = "withdraw"
+	CoBrokeActionComplete  = "complete"
+	CoBrokeActionTerminate = "terminate"
+)
+
+type coBrokeTransition struct {
+	from    string
+	to      string
+	listing bool // the listing dealer may take this action
+	partner bool // the partner dealer may take this action
+}
+
+var coBrokeTransitions = map[string]coBrokeTransition{
+	CoBrokeActionAccept:    {from: models.CoBrokeStatusPending, to: models.CoBrokeStatusAccepted, listing: true},
+	CoBrokeActionDecline:   {from: models.CoBrokeStatusPending, to: models.CoBrokeStatusDeclined, listing: true},
+	CoBrokeActionWithdraw:  {from: models.CoBrokeStatusPending, to: models.CoBrokeStatusWithdrawn, partner: true},
+	CoBrokeActionComplete:  {from: models.CoBrokeStatusAccepted, to: models.CoBrokeStatusCompleted, listing: true, partner: true},
+	CoBrokeActionTerminate: {from: models.CoBrokeStatusAccepted, to: models.CoBrokeStatusTerminated, listing: true, partner: true},
+}
+
+type CoBrokeService struct {
+	repo         repositories.CoBrokeRepository
+	propertyRepo repositories.PropertyRepository
+	dealerRepo   repositories.DealerRepository
+	analytics    *AnalyticsService
+	publicURL    string
+}
+
+// NewCoBrokeService takes publicURL to prefix stored object keys such as videos in the pool
+func NewCoBrokeService(repo repositories.CoBrokeRepository, propertyRepo repositories.PropertyRepository, dealerRepo repositories.DealerRepository, analytics *AnalyticsService, publicURL string) *CoBrokeService {
+	return &CoBrokeService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		dealerRepo:   dealerRepo,
+		analytics:    analytics,
+		publicURL:    publicURL,
+	}
+}
+
+// SetListingTerms opts one of the dealer's listings in or out of the pool. Requests already made
+// keep the terms they were made under.
+func (s *CoBrokeService) SetListingTerms(ctx context.Context, dealerID string, propertyID string, terms models.CoBrokeTerms) (models.CoBrokeTerms, error) {
+	if terms.Enabled {
+		if terms.CommissionSplit == 0 {
+			terms.CommissionSplit = defaultCommissionSplit
+		}
+		if terms.CommissionSplit < 0 || terms.CommissionSplit > 100 {
+			return models.CoBrokeTerms{}, fmt.Errorf("%w: commission_split must be a percentage between 0 and 100", ErrCoBrokeInvalid)
+		}
+	}
+	terms.Terms = strings.TrimSpace(terms.Terms)
+	terms.UpdatedAt = time.Now()
+
+	if err := s.propertyRepo.SetCoBroke(ctx, propertyID, dealerID, terms); err != nil {
+		return models.CoBrokeTerms{}, err
+	}
+	return terms, nil
+}
+
+// GetPool lists other dealers' pooled listings matching params
+func (s *CoBrokeService) GetPool(ctx context.Context, dealerID string, params models.PropertyQueryParams) ([]models.CoBrokeListing, error) {
+	// Filtering on owner details would let a dealer probe for them one guess at a time
+	params.OwnerName = nil
+	params.OwnerPhone = nil
+	params.Sold = nil
+	params.IsDeleted = nil
+	params.SetDefaults()
+	if !utils.Contains(coBrokePoolSorts, *params.Sort) {
+		return nil, fmt.Errorf("%w: sort must be one of %s", ErrCoBrokeInvalid, strings.Join(coBrokePoolSorts, ", "))
+	}
+
+	properties, err := s.propertyRepo.GetCoBrokePool(ctx, params, dealerID)
+	if err != nil {
+		return nil, err
+	}
+	// Opening one pooled listing is a view; browsing the pool is not
+	if params.ID != nil {
+		s.analytics.RecordDealerViews(properties, dealerID)
+	}
+
+	dealers := make(map[string]models.SharedDealer)
+	listings := make([]models.CoBrokeListing, 0, len(properties))
+	for _, property := range properties {
+		dealer, ok := dealers[property.DealerID]
+		if !ok {
+			full, err := s.dealerRepo.GetByID(ctx, property.DealerID)
+			if err != nil {
+				return nil, err
+			}
+			dealer = models.SharedDealer{
+				Name:          full.Name,
+				ShopName:      full.ShopName,
+				Phone:         full.Phone,
+				Email:         full.Email,
+				OfficeAddress: full.OfficeAddress,
+			}
+			dealers[property.DealerID] = dealer
+		}
+
+		listing := converters.ToCoBrokeListing(property, dealer)
+		listing.Property.Videos = publicMediaURLs(s.publicURL, property.Videos)
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
+// RequestCollaboration asks the listing dealer to co-broke a pooled listing
+func (s *CoBrokeService) RequestCollaboration(ctx context.Context, dealerID string, propertyID string, message string) (models.CoBrokeAgreement, error) {
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.CoBrokeAgreement{}, ErrCoBrokeUnavailable
+		}
+		return models.CoBrokeAgreement{}, err
+	}
+	if property.CoBroke == nil || !property.CoBroke.Enabled || property.Sold || property.IsDeleted {
+		return models.CoBrokeAgreement{}, ErrCoBrokeUnavailable
+	}
+	if property.DealerID == dealerID {
+		return models.CoBrokeAgreement{}, fmt.Errorf("%w: you cannot co-broke your own listing", ErrCoBrokeInvalid)
+	}
+
+	agreement, created, err := s.repo.Create(ctx, models.CoBrokeAgreement{
+		PropertyID:      property.ID,
+		PropertyNumber:  property.PropertyNumber,
+		PropertyTitle:   property.Title,
+		ListingDealerID: property.DealerID,
+		PartnerDealerID: dealerID,
+		CommissionSplit: property.CoBroke.CommissionSplit,
+		Terms:           property.CoBroke.Terms,
+		Message:         strings.TrimSpace(message),
+		Status:          models.CoBrokeStatusPending,
+	})
+	if err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+	if !created {
+		return models.CoBrokeAgreement{}, ErrCoBrokeDuplicate
+	}
+	return agreement, nil
+}
+
+// GetAgreements lists the dealer's agreements on either side, optionally narrowed by side and status
+func (s *CoBrokeService) GetAgreements(ctx context.Context, dealerID string, side string, status string) ([]models.CoBrokeAgreement, error) {
+	if side != "" && side != models.CoBrokeSideListing && side != models.CoBrokeSidePartner {
+		return nil, fmt.Errorf("%w: side must be %q or %q", ErrCoBrokeInvalid, models.CoBrokeSideListing, models.CoBrokeSidePartner)
+	}
+	return s.repo.GetByDealer(ctx, dealerID, side, status)
+}
+
+func (s *CoBrokeService) GetAgreement(ctx context.Context, id string, dealerID string) (models.CoBrokeAgreement, error) {
+	agreement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.CoBrokeAgreement{}, ErrCoBrokeNotFound
+		}
+		return models.CoBrokeAgreement{}, err
+	}
+	if agreement.ListingDealerID != dealerID && agreement.PartnerDealerID != dealerID {
+		return models.CoBrokeAgreement{}, ErrCoBrokeNotParty
+	}
+	return agreement, nil
+}
+
+// UpdateAgreement applies an action: the listing dealer accepts or declines a request, the partner
+// withdraws it, and either side closes an accepted agreement as completed or terminated
+func (s *CoBrokeService) UpdateAgreement(ctx context.Context, id string, dealerID string, action string, note string) (models.CoBrokeAgreement, error) {
+	transition, ok := coBrokeTransitions[action]
+	if !ok {
+		return models.CoBrokeAgreement{}, fmt.Errorf("%w: unknown action %q", ErrCoBrokeInvalid, action)
+	}
+
+	agreement, err := s.GetAgreement(ctx, id, dealerID)
+	if err != nil {
+		return models.CoBrokeAgreement{}, err
+	}
+	isListing := agreement.ListingDealerID == dealerID
+	if (isListing && !transition.listing) || (!isListing && !transition.partner) {
+		return models.CoBrokeAgreement{}, ErrCoBrokeNotParty
+	}
+	if agreement.Status != transition.from {
+		return models.CoBrokeAgreement{}, ErrCoBrokeInvalidAction
+	}
+
+	if action == CoBrokeActionAccept {
+		property, err := s.propertyRepo.GetByID(ctx, agreement.PropertyID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return models.CoBrokeAgreement{}, err
+		}
+		if err == mongo.ErrNoDocuments || property.Sold || property.IsDeleted || property.CoBroke == nil || !property.CoBroke.Enabled {
+			return models.CoBrokeAgreement{}, ErrCoBrokeUnavailable
+		}
+	}
+
+	now := time.Now()
+	note = strings.TrimSpace(note)
+	agreement.Status = transition.to
+	if transition.from == models.CoBrokeStatusPending {
+		agreement.ResponseNote = note
+		agreement.RespondedAt = &now
+	} else {
+		agreement.ClosingNote = note
+		agreement.ClosedAt = &now
+	}
+
+	updated, err := s.repo.UpdateStatus(ctx, id, transition.from, agreement)
+	if err == mongo.ErrNoDocuments {
+		return models.CoBrokeAgreement{}, ErrCoBrokeInvalidAction
+	}
+	return updated, err
+}
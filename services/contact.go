@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrContactInvalid   = errors.New("phone number is not valid")
+	ErrContactNotFound  = errors.New("no lead, inquiry or client has this phone number")
+	ErrLeadMergeInvalid = errors.New("invalid lead merge")
+)
+
+// ContactService ties together the records one person has across the app. Leads, inquiries and
+// dealer clients carry a contact key, their normalised phone number, so the same buyer entered as
+// "+91 98734 62385" and "9873462385" is recognised as one contact.
+type ContactService struct {
+	repo       repositories.ContactRepository
+	Activities *ActivityService
+}
+
+func NewContactService(repo repositories.ContactRepository, activities *ActivityService) *ContactService {
+	return &ContactService{repo: repo, Activities: activities}
+}
+
+// BackfillContactKeys links records saved before contact keys existed
+func (s *ContactService) BackfillContactKeys(ctx context.Context) error {
+	updated, err := s.repo.BackfillContactKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		log.Printf("contacts: linked %d existing records by phone", updated)
+	}
+	return nil
+}
+
+// GetDuplicates lists contacts with more than one record, or more than one lead when leadsOnly is set
+func (s *ContactService) GetDuplicates(ctx context.Context, leadsOnly bool, paging models.BaseQueryParams) ([]models.ContactSummary, error) {
+	paging.SetDefaults()
+	return s.repo.GetDuplicates(ctx, leadsOnly, *paging.Page, *paging.Limit)
+}
+
+// GetContact returns everything known about the person with this phone number, in any format:
+// their leads, inquiries and dealer clients, the visits and tasks about them, earlier lead merges,
+// and all of it as one timeline
+func (s *ContactService) GetContact(ctx context.Context, phone string) (models.Contact, error) {
+	key := utils.NormalizePhone(phone)
+	if key == "" {
+		return models.Contact{}, ErrContactInvalid
+	}
+
+	contact := models.Contact{Key: key}
+	var err error
+	if contact.Leads, err = s.repo.GetLeads(ctx, key); err != nil {
+		return models.Contact{}, err
+	}
+	if contact.Inquiries, err = s.repo.GetInquiries(ctx, key); err != nil {
+		return models.Contact{}, err
+	}
+	if contact.DealerClients, err = s.repo.GetDealerClients(ctx, key); err != nil {
+		return models.Contact{}, err
+	}
+	if len(contact.Leads) == 0 && len(contact.Inquiries) == 0 && len(contact.DealerClients) == 0 {
+		return models.Contact{}, ErrContactNotFound
+	}
+
+	leadIDs := make([]string, len(contact.Leads))
+	for i, lead := range contact.Leads {
+		leadIDs[i] = lead.ID
+		contact.Names = appendName(contact.Names, lead.Name)
+	}
+	for _, inquiry := range contact.Inquiries {
+		contact.Names = appendName(contact.Names, inquiry.Name)
+	}
+	clientIDs := make([]string, len(contact.DealerClients))
+	for i, client := range contact.DealerClients {
+		clientIDs[i] = client.ID
+		contact.Names = appendName(contact.Names, client.Name)
+	}
+
+	if contact.Visits, err = s.repo.GetVisits(ctx, leadIDs, clientIDs); err != nil {
+		return models.Contact{}, err
+	}
+	if contact.Tasks, err = s.repo.GetTasks(ctx, leadIDs, clientIDs); err != nil {
+		return models.Contact{}, err
+	}
+	if contact.Merges, err = s.repo.GetLeadMerges(ctx, leadIDs); err != nil {
+		return models.Contact{}, err
+	}
+
+	contact.History = contactHistory(contact)
+	return contact, nil
+}
+
+// MergeLeads folds a duplicate lead into leadID and deletes the duplicate. The lead keeps its
+// name and phone; interests, requirement text and anything the lead is missing are taken from
+// the duplicate, and the duplicate's visits, tasks, assignment history and activity timeline move
+// to the lead. Leads with different contact keys are only merged when the request allows it.
+func (s *ContactService) MergeLeads(ctx context.Context, leadID string, req models.LeadMergeRequest, mergedBy string) (models.LeadMergeResult, error) {
+	duplicateID := strings.TrimSpace(req.DuplicateLeadID)
+	if duplicateID == "" {
+		return models.LeadMergeResult{}, fmt.Errorf("%w: duplicate_lead_id is required", ErrLeadMergeInvalid)
+	}
+	if duplicateID == leadID {
+		return models.LeadMergeResult{}, fmt.Errorf("%w: a lead cannot be merged into itself", ErrLeadMergeInvalid)
+	}
+
+	lead, merge, err := s.repo.MergeLeads(ctx, leadID, duplicateID, func(lead models.Lead, duplicate models.Lead) (models.Lead, models.LeadMerge, error) {
+		if !req.AllowDifferentContact && (lead.ContactKey == "" || lead.ContactKey != duplicate.ContactKey) {
+			return models.Lead{}, models.LeadMerge{}, fmt.Errorf("%w: the leads do not have the same phone number; set allow_different_contact to merge them anyway", ErrLeadMergeInvalid)
+		}
+		merged, merge := combineLeads(lead, duplicate)
+		merge.MergedBy = mergedBy
+		return merged, merge, nil
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.LeadMergeResult{}, ErrLeadNotFound
+		}
+		return models.LeadMergeResult{}, err
+	}
+
+	summary := fmt.Sprintf("Merged with duplicate lead %s", merge.MergedName)
+	if merge.MergedPhone != lead.Phone {
+		summary += " (" + merge.MergedPhone + ")"
+	}
+	s.Activities.Record(ctx, models.Activity{
+		SubjectType: models.ActivitySubjectLead,
+		SubjectID:   lead.ID,
+		Type:        models.ActivityMerged,
+		Body:        activityBody(summary, fmt.Sprintf("%d interests added, %d combined", merge.InterestsAdded, merge.InterestsCombined)),
+		AuthorID:    mergedBy,
+	})
+	return models.LeadMergeResult{Lead: lead, Merge: merge}, nil
+}
+
+// combineLeads merges duplicate into lead. An interest both leads have keeps the status of
+// whichever changed last, with both notes and both histories.
+func combineLeads(lead models.Lead, duplicate models.Lead) (models.Lead, models.LeadMerge) {
+	merge := models.LeadMerge{
+		LeadID:            lead.ID,
+		MergedLeadID:      duplicate.ID,
+		MergedName:        duplicate.Name,
+		MergedPhone:       duplicate.Phone,
+		MergedRequirement: duplicate.Requirement,
+	}
+
+	lead.Requirement = combineText(lead.Requirement, duplicate.Requirement)
+	if lead.Criteria == nil {
+		lead.Criteria = duplicate.Criteria
+	}
+	if lead.StoredAadhar.Ciphertext == "" {
+		photo := lead.StoredAadhar.Photo
+		lead.StoredAadhar, lead.AadharNumber = duplicate.StoredAadhar, duplicate.AadharNumber
+		lead.StoredAadhar.Photo = photo
+	}
+	if lead.StoredAadhar.Photo == "" {
+		lead.StoredAadhar.Photo, lead.HasAadharPhoto = duplicate.StoredAadhar.Photo, duplicate.HasAadharPhoto
+	}
+	if lead.AssignedDealerID == "" {
+		lead.AssignedDealerID, lead.AssignedAt = duplicate.AssignedDealerID, duplicate.AssignedAt
+	}
+
+	existing := make(map[string]int, len(lead.Properties))
+	for i, interest := range lead.Properties {
+		existing[interest.PropertyID] = i
+	}
+	for _, interest := range duplicate.Properties {
+		interest.LeadID = lead.ID
+		i, ok := existing[interest.PropertyID]
+		if !ok {
+			existing[interest.PropertyID] = len(lead.Properties)
+			lead.Properties = append(lead.Properties, interest)
+			merge.InterestsAdded++
+			continue
+		}
+
+		kept := lead.Properties[i]
+		if interest.UpdatedAt.After(kept.UpdatedAt) {
+			kept.Status, kept.SoldPrice, kept.LostReason = interest.Status, interest.SoldPrice, interest.LostReason
+			kept.StatusChangedAt, kept.UpdatedAt = interest.StatusChangedAt, interest.UpdatedAt
+		}
+		if interest.CreatedAt.Before(kept.CreatedAt) {
+			kept.CreatedAt = interest.CreatedAt
+		}
+		kept.Note = combineText(kept.Note, interest.Note)
+		kept.History = append(append([]models.PropertyInterestStatusChange{}, kept.History...), interest.History...)
+		sort.SliceStable(kept.History, func(a, b int) bool { return kept.History[a].ChangedAt.Before(kept.History[b].ChangedAt) })
+		lead.Properties[i] = kept
+		merge.InterestsCombined++
+	}
+	return lead, merge
+}
+
+// combineText joins two free-text fields, leaving out the second when the first already has it
+func combineText(first string, second string) string {
+	first, second = strings.TrimSpace(first), strings.TrimSpace(second)
+	switch {
+	case second == "" || strings.Contains(first, second):
+		return first
+	case first == "" || strings.Contains(second, first):
+		return second
+	}
+	return first + "\n" + second
+}
+
+func appendName(names []string, name string) []string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return names
+	}
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// contactHistory lays out a contact's records as events, newest first
+func contactHistory(contact models.Contact) []models.ContactEvent {
+	var events []models.ContactEvent
+	add := func(at time.Time, entityType string, entityID string, dealerID string, summary string) {
+		if !at.IsZero() {
+			events = append(events, models.ContactEvent{At: at, EntityType: entityType, EntityID: entityID, DealerID: dealerID, Summary: summary})
+		}
+	}
+
+	for _, lead := range contact.Leads {
+		// Leads are not timestamped, but their IDs are
+		if objectID, err := primitive.ObjectIDFromHex(lead.ID); err == nil {
+			add(objectID.Timestamp(), models.ContactEntityLead, lead.ID, "", "Lead created as "+lead.Name)
+		}
+		for _, interest := range lead.Properties {
+			for _, change := range interest.History {
+				summary := fmt.Sprintf("Property #%d marked %s", interest.PropertyNumber, change.To)
+				if change.From == "" {
+					summary = fmt.Sprintf("Interested in property #%d", interest.PropertyNumber)
+				}
+				add(change.ChangedAt, models.ContactEntityLead, lead.ID, interest.DealerID, summary)
+			}
+			if len(interest.History) == 0 {
+				add(interest.CreatedAt, models.ContactEntityLead, lead.ID, interest.DealerID, fmt.Sprintf("Interested in property #%d", interest.PropertyNumber))
+			}
+		}
+	}
+	for _, inquiry := range contact.Inquiries {
+		dealerID := ""
+		if inquiry.DealerID != nil {
+			dealerID = *inquiry.DealerID
+		}
+		add(inquiry.CreatedAt, models.ContactEntityInquiry, inquiry.ID, dealerID, "Inquiry from "+inquiry.Source)
+	}
+	for _, client := range contact.DealerClients {
+		add(client.CreatedAt, models.ContactEntityDealerClient, client.ID, client.DealerID, "Added as a client of a dealer")
+	}
+	for _, visit := range contact.Visits {
+		add(visit.StartsAt, models.ContactEntityVisit, visit.ID, visit.DealerID, fmt.Sprintf("Site visit to property #%d (%s)", visit.PropertyNumber, visit.Status))
+	}
+	for _, task := range contact.Tasks {
+		add(task.CreatedAt, models.ContactEntityTask, task.ID, "", "Task: "+task.Title)
+		if task.CompletedAt != nil {
+			add(*task.CompletedAt, models.ContactEntityTask, task.ID, "", "Task completed: "+task.Title)
+		}
+	}
+	for _, merge := range contact.Merges {
+		add(merge.CreatedAt, models.ContactEntityLeadMerge, merge.ID, "", fmt.Sprintf("Duplicate lead %s (%s) merged", merge.MergedName, merge.MergedPhone))
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.After(events[j].At) })
+	return events
+}
@@ -60,6 +60,9 @@ func (s *DealerService) LoginDealer(ctx context.Context, phone, password string)
 	if err != nil {
 		return "", errors.New("invalid password")
 	}
+	if dbUser.DeactivatedAt != nil {
+		return "", errors.New("account is disabled")
+	}
 
 	claims := &models.Claims{
 		ID:    dbUser.ID,
@@ -96,6 +99,14 @@ func (s *DealerService) GetLocationsWithSubLocations(ctx context.Context) ([]mod
 	return s.DealerRepo.GetLocationsWithSubLocations(ctx)
 }
 
+func (s *DealerService) GetDealerByID(ctx context.Context, dealerID string) (models.Dealer, error) {
+	return s.DealerRepo.GetByID(ctx, dealerID)
+}
+
+func (s *DealerService) UpdateWatermark(ctx context.Context, dealerID string, watermark models.WatermarkSettings) error {
+	return s.DealerRepo.UpdateWatermark(ctx, dealerID, watermark)
+}
+
 func (s *DealerService) DealerExists(ctx context.Context, dealerID string) (bool, error) {
 	return s.DealerRepo.Exists(ctx, dealerID)
 }
@@ -110,10 +121,6 @@ func (s *DealerService) UpdateDealer(ctx context.Context, id string, updates map
 	return s.DealerRepo.Update(ctx, id, updates)
 }
 
-func (s *DealerService) DeleteDealer(ctx context.Context, id string) error {
-	return s.DealerRepo.Delete(ctx, id)
-}
-
 func (s *DealerService) ResetPasswordDealer(ctx context.Context, dealerID string, newPassword string) error {
 	// Hash the new password
 	hash, err := utils.HashPassword(newPassword)
@@ -3,35 +3,70 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"myapp/constants"
 	"myapp/models"
 	"myapp/repositories"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	ErrDealerClientNotOwned    = errors.New("client belongs to another dealer")
+	ErrDocumentNotFound        = errors.New("document not found")
+	ErrDealerClientPhoneExists = errors.New("phone number already exists")
+)
+
+const documentLinkExpiry = 5 * time.Minute
+
 type DealerClientService struct {
 	Repo repositories.DealerClientRepository
 	PropertyRepo repositories.PropertyRepository
+	Media        *MediaService
+	AccessLogRepo repositories.DocumentAccessLogRepository
+	Analytics     *AnalyticsService
+	Activities    *ActivityService
 }
 
 func (s *DealerClientService) CheckPhoneExistsForDealer(ctx context.Context, dealerID string, phone string) (bool, error) {
 	return s.Repo.CheckPhoneExistsForDealer(ctx, dealerID, phone)
 }
 
-func (s *DealerClientService) CreateDealerClient(ctx context.Context, dealerClient models.DealerClient) (string, error) {
+// CreateDealerClient stores the client; userID is who added it, for the client's timeline
+func (s *DealerClientService) CreateDealerClient(ctx context.Context, dealerClient models.DealerClient, userID string) (string, error) {
 	
 	exists, err := s.CheckPhoneExistsForDealer(ctx, dealerClient.DealerID, dealerClient.Phone)
 	if err != nil {
 		return "", err
 	}
 	if exists {
-		return "", errors.New("phone number already exists")
+		return "", ErrDealerClientPhoneExists
 	}
 
-	
+	if err := s.verifyDocs(ctx, dealerClient.DealerID, dealerClient.Docs, nil); err != nil {
+		return "", err
+	}
 
-	return s.Repo.Create(ctx, dealerClient)
+	criteria, err := NormalizeRequirementCriteria(dealerClient.Criteria)
+	if err != nil {
+		return "", err
+	}
+	dealerClient.Criteria = criteria
+
+	id, err := s.Repo.Create(ctx, dealerClient)
+	if err != nil {
+		return "", err
+	}
+	summary := "Client added"
+	if dealerClient.SourceInquiryID != "" {
+		summary = "Client added from an inquiry"
+	}
+	s.Activities.Record(ctx, models.Activity{SubjectType: models.ActivitySubjectDealerClient, SubjectID: id, Type: models.ActivityCreated, Body: activityBody(summary, dealerClient.Note), AuthorID: userID})
+	s.recordDocsAdded(ctx, id, dealerClient.Docs, nil, userID)
+	return id, nil
 }
 
 func (s *DealerClientService) GetDealerClients(ctx context.Context, params models.DealerClientQueryParams, fields []string) ([]models.DealerClient, error) {
@@ -111,8 +146,143 @@ func (s *DealerClientService) GetDealerClients(ctx context.Context, params model
 
 
 
-func (s *DealerClientService) UpdateDealerClient(ctx context.Context, id string, updates models.DealerClientUpdate) error {
-	return s.Repo.Update(ctx, id, updates)
+func (s *DealerClientService) UpdateDealerClient(ctx context.Context, id string, updates models.DealerClientUpdate, userID string) error {
+	if updates.Criteria != nil {
+		criteria, err := NormalizeRequirementCriteria(updates.Criteria)
+		if err != nil {
+			return err
+		}
+		updates.Criteria = criteria
+	}
+	var existing models.DealerClient
+	if updates.Docs != nil {
+		var err error
+		existing, err = s.Repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.verifyDocs(ctx, existing.DealerID, *updates.Docs, existing.Docs); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.Update(ctx, id, updates); err != nil {
+		return err
+	}
+	if updates.Docs != nil {
+		s.recordDocsAdded(ctx, id, *updates.Docs, existing.Docs, userID)
+	}
+	return nil
+}
+
+// recordDocsAdded puts the documents in docs that are not in existing on the client's timeline
+func (s *DealerClientService) recordDocsAdded(ctx context.Context, clientID string, docs []models.Document, existing []models.Document, userID string) {
+	attached := make(map[string]bool)
+	for _, doc := range existing {
+		attached[doc.Key+"|"+doc.URL] = true
+	}
+	for _, doc := range docs {
+		if attached[doc.Key+"|"+doc.URL] {
+			continue
+		}
+		summary := "Document added"
+		if doc.Type != "" {
+			summary = fmt.Sprintf("Document added (%s)", doc.Type)
+		}
+		s.Activities.Record(ctx, models.Activity{SubjectType: models.ActivitySubjectDealerClient, SubjectID: clientID, Type: models.ActivityDocumentAdded, Body: summary, AuthorID: userID})
+	}
+}
+
+// verifyDocs rejects documents that are not confirmed uploads of the client's dealer.
+// Documents already attached to the client are accepted unchanged.
+func (s *DealerClientService) verifyDocs(ctx context.Context, dealerID string, docs []models.Document, existing []models.Document) error {
+	if s.Media == nil {
+		return nil
+	}
+
+	attached := make(map[string]bool)
+	for _, doc := range existing {
+		attached[doc.Key] = true
+		attached[doc.URL] = true
+	}
+
+	var keys []string
+	for _, doc := range docs {
+		if doc.Key == "" {
+			if !attached[doc.URL] {
+				return fmt.Errorf("%w: documents must reference uploaded files", ErrMediaNotConfirmed)
+			}
+			continue
+		}
+		if !attached[doc.Key] {
+			keys = append(keys, doc.Key)
+		}
+	}
+
+	return s.Media.VerifyOwnedMedia(ctx, dealerID, keys, constants.MediaPurposeClientDoc, constants.MediaPurposeKYC)
+}
+
+// GetDocumentDownloadURL issues a short-lived link to one of the client's documents after
+// recording the access. No link is issued if the access cannot be recorded.
+func (s *DealerClientService) GetDocumentDownloadURL(ctx context.Context, dealerID string, clientID string, key string, ip string, userAgent string) (string, time.Time, error) {
+	if s.Media == nil || s.AccessLogRepo == nil {
+		return "", time.Time{}, errors.New("document storage is not configured")
+	}
+
+	client, err := s.getOwnedClient(ctx, dealerID, clientID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	found := false
+	for _, doc := range client.Docs {
+		if doc.Key != "" && doc.Key == key {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", time.Time{}, ErrDocumentNotFound
+	}
+
+	err = s.AccessLogRepo.Create(ctx, models.DocumentAccessLog{
+		DealerID:   dealerID,
+		ClientID:   clientID,
+		Key:        key,
+		IP:         ip,
+		UserAgent:  userAgent,
+		AccessedAt: time.Now(),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to record document access: %w", err)
+	}
+
+	url, err := s.Media.Storage.GeneratePresignedGetURL(ctx, key, documentLinkExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, time.Now().Add(documentLinkExpiry), nil
+}
+
+// GetDocumentAccessLog returns the most recent document downloads for one of the dealer's clients
+func (s *DealerClientService) GetDocumentAccessLog(ctx context.Context, dealerID string, clientID string) ([]models.DocumentAccessLog, error) {
+	if s.AccessLogRepo == nil {
+		return nil, errors.New("document storage is not configured")
+	}
+	if _, err := s.getOwnedClient(ctx, dealerID, clientID); err != nil {
+		return nil, err
+	}
+	return s.AccessLogRepo.GetByClient(ctx, clientID, 100)
+}
+
+func (s *DealerClientService) getOwnedClient(ctx context.Context, dealerID string, clientID string) (models.DealerClient, error) {
+	client, err := s.Repo.GetByID(ctx, clientID)
+	if err != nil {
+		return models.DealerClient{}, err
+	}
+	if client.DealerID != dealerID {
+		return models.DealerClient{}, ErrDealerClientNotOwned
+	}
+	return client, nil
 }
 
 func (s *DealerClientService) DeleteDealerClient(ctx context.Context, id string) error {
@@ -127,7 +297,7 @@ func (s *DealerClientService) UpdateDealerClientStatus(ctx context.Context, id s
 	return s.Repo.UpdateStatus(ctx, id, status)
 }
 
-func (s *DealerClientService) CreateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, dealerClientPropertyInterest models.DealerClientPropertyInterest) error {
+func (s *DealerClientService) CreateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, dealerClientPropertyInterest models.DealerClientPropertyInterest, userID string) error {
 	
 	exists, err := s.Repo.CheckPropertyInterestExists(ctx, dealerClientID, dealerClientPropertyInterest.PropertyID)
 	if err != nil {
@@ -137,11 +307,69 @@ func (s *DealerClientService) CreateDealerClientPropertyInterest(ctx context.Con
 		return errors.New("client is already added to this property")
 	}
 
-	return s.Repo.CreateDealerClientPropertyInterest(ctx, dealerClientID, dealerClientPropertyInterest)
+	if err := s.Repo.CreateDealerClientPropertyInterest(ctx, dealerClientID, dealerClientPropertyInterest); err != nil {
+		return err
+	}
+
+	// Counted against the listing's dealer, who may not be the client's
+	summary := "Added to a property"
+	if property, err := s.PropertyRepo.GetByID(ctx, dealerClientPropertyInterest.PropertyID); err == nil {
+		s.Analytics.Record(property.ID, property.DealerID, models.PropertyEventInterest, "")
+		summary = fmt.Sprintf("Added to property #%d", property.PropertyNumber)
+	}
+	s.Activities.Record(ctx, models.Activity{
+		SubjectType: models.ActivitySubjectDealerClient,
+		SubjectID:   dealerClientID,
+		Type:        models.ActivityPropertyAdded,
+		Body:        activityBody(summary, dealerClientPropertyInterest.Note),
+		PropertyID:  dealerClientPropertyInterest.PropertyID,
+		AuthorID:    userID,
+	})
+	return nil
+}
+
+func (s *DealerClientService) UpdateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, propertyInterestID string, update models.DealerClientPropertyInterestUpdate, userID string) error {
+	if err := s.Repo.UpdateDealerClientPropertyInterest(ctx, dealerClientID, propertyInterestID, update); err != nil {
+		return err
+	}
+	s.recordInterestUpdate(ctx, dealerClientID, propertyInterestID, update, userID)
+	return nil
 }
 
-func (s *DealerClientService) UpdateDealerClientPropertyInterest(ctx context.Context, dealerClientID string, propertyInterestID string, update models.DealerClientPropertyInterestUpdate) error {
-	return s.Repo.UpdateDealerClientPropertyInterest(ctx, dealerClientID, propertyInterestID, update)
+// recordInterestUpdate puts a status change or new note on a client's property interest on the
+// client's timeline, so earlier notes are not lost when the interest's note is overwritten
+func (s *DealerClientService) recordInterestUpdate(ctx context.Context, dealerClientID string, propertyInterestID string, update models.DealerClientPropertyInterestUpdate, userID string) {
+	if s.Activities == nil || (update.Status == nil && update.Note == nil) {
+		return
+	}
+
+	activity := models.Activity{SubjectType: models.ActivitySubjectDealerClient, SubjectID: dealerClientID, AuthorID: userID}
+	property := "a property"
+	if client, err := s.Repo.GetByID(ctx, dealerClientID); err == nil {
+		for _, interest := range client.PropertyInterests {
+			if interest.ID == propertyInterestID {
+				activity.PropertyID = interest.PropertyID
+				property = fmt.Sprintf("property #%d", interest.PropertyNumber)
+				break
+			}
+		}
+	}
+
+	note := ""
+	if update.Note != nil {
+		note = *update.Note
+	}
+	if update.Status != nil {
+		activity.Type = models.ActivityStatusChange
+		activity.Body = activityBody(fmt.Sprintf("Marked %s for %s", *update.Status, property), note)
+	} else {
+		if strings.TrimSpace(note) == "" {
+			return
+		}
+		activity.Type = models.ActivityNote
+		activity.Body = activityBody(fmt.Sprintf("Note on %s", property), note)
+	}
+	s.Activities.Record(ctx, activity)
 }
 
 func (s *DealerClientService) DeleteDealerClientPropertyInterest(ctx context.Context, dealerClientID string, propertyInterestID string) error {
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrOffboardInvalid       = errors.New("invalid offboarding request")
+	ErrDealerDeactivated     = errors.New("dealer is already deactivated")
+	ErrDealerHasRecords      = errors.New("dealer still has listings, clients, leads, open tasks, visits or co-broke agreements; offboard them to a successor instead")
+	ErrSuccessorNotAvailable = errors.New("successor dealer does not exist or is deactivated")
+)
+
+// disabledDealersKey is a Redis set of deactivated dealer IDs. Tokens outlive the account, so
+// every authenticated request is checked against it.
+const disabledDealersKey = "dealers:disabled"
+
+// DealerOffboardingService retires dealer accounts. Everything a departing dealer holds moves
+// to a successor the admin chooses, and the account is disabled rather than deleted so the
+// history stays intact.
+type DealerOffboardingService struct {
+	dealerRepo      repositories.DealerRepository
+	transferRepo    repositories.DealerTransferRepository
+	propertyService *PropertyService
+	redisClient     *redis.Client
+}
+
+func NewDealerOffboardingService(dealerRepo repositories.DealerRepository, transferRepo repositories.DealerTransferRepository, propertyService *PropertyService, redisClient *redis.Client) *DealerOffboardingService {
+	return &DealerOffboardingService{
+		dealerRepo:      dealerRepo,
+		transferRepo:    transferRepo,
+		propertyService: propertyService,
+		redisClient:     redisClient,
+	}
+}
+
+// Offboard transfers the dealer's listings, clients, inquiries, saved searches, lead interests,
+// assigned leads, open tasks, visits and co-broke agreements to the successor and deactivates
+// the dealer. The successor is checked inside the same transaction.
+func (s *DealerOffboardingService) Offboard(ctx context.Context, dealerID string, request models.DealerOffboardRequest, adminID string) (models.DealerTransfer, error) {
+	request.SuccessorDealerID = strings.TrimSpace(request.SuccessorDealerID)
+	request.Reason = strings.TrimSpace(request.Reason)
+	if _, err := primitive.ObjectIDFromHex(request.SuccessorDealerID); err != nil {
+		return models.DealerTransfer{}, fmt.Errorf("%w: successor_dealer_id must be a valid dealer ID", ErrOffboardInvalid)
+	}
+	if request.SuccessorDealerID == dealerID {
+		return models.DealerTransfer{}, fmt.Errorf("%w: a dealer cannot be their own successor", ErrOffboardInvalid)
+	}
+	if len(request.Reason) > 500 {
+		return models.DealerTransfer{}, fmt.Errorf("%w: reason must be at most 500 characters", ErrOffboardInvalid)
+	}
+
+	dealer, err := s.dealerRepo.GetByID(ctx, dealerID)
+	if err != nil {
+		return models.DealerTransfer{}, err
+	}
+	if dealer.DeactivatedAt != nil {
+		return models.DealerTransfer{}, ErrDealerDeactivated
+	}
+
+	transfer, err := s.transferRepo.Transfer(ctx, models.DealerTransfer{
+		FromDealerID: dealerID,
+		ToDealerID:   request.SuccessorDealerID,
+		Reason:       request.Reason,
+		PerformedBy:  adminID,
+	})
+	if err == mongo.ErrNoDocuments {
+		return models.DealerTransfer{}, ErrDealerDeactivated
+	}
+	if err == repositories.ErrTransferSuccessorUnavailable {
+		return models.DealerTransfer{}, ErrSuccessorNotAvailable
+	}
+	if err != nil {
+		return models.DealerTransfer{}, err
+	}
+
+	s.markDisabled(ctx, dealerID)
+	if s.propertyService != nil && s.propertyService.RedisClient != nil {
+		s.propertyService.InvalidateDealerPropertyCache(dealerID)
+		s.propertyService.InvalidateDealerPropertyCache(request.SuccessorDealerID)
+	}
+	return transfer, nil
+}
+
+func (s *DealerOffboardingService) GetTransfers(ctx context.Context, dealerID string) ([]models.DealerTransfer, error) {
+	return s.transferRepo.GetByDealer(ctx, dealerID)
+}
+
+// DeleteDealer removes a dealer who holds nothing. Dealers with records must be offboarded
+// so nothing is orphaned.
+func (s *DealerOffboardingService) DeleteDealer(ctx context.Context, dealerID string) error {
+	owned, err := s.transferRepo.CountOwned(ctx, dealerID)
+	if err != nil {
+		return err
+	}
+	if owned > 0 {
+		return ErrDealerHasRecords
+	}
+
+	if err := s.dealerRepo.Delete(ctx, dealerID); err != nil {
+		return err
+	}
+	s.markDisabled(ctx, dealerID)
+	return nil
+}
+
+// IsAccountActive reports whether a signed-in user may still use the API. Only dealers are
+// ever disabled. It fails open when Redis is unavailable; logging in checks the database.
+func (s *DealerOffboardingService) IsAccountActive(ctx context.Context, userID string, role string) bool {
+	if role != constants.Dealer || s.redisClient == nil {
+		return true
+	}
+	disabled, err := s.redisClient.SIsMember(ctx, disabledDealersKey, userID).Result()
+	if err != nil {
+		log.Printf("⚠️  Failed to check whether dealer %s is disabled: %v", userID, err)
+		return true
+	}
+	return !disabled
+}
+
+// RestoreDisabledDealers rebuilds the disabled set from the database, in case Redis lost it
+func (s *DealerOffboardingService) RestoreDisabledDealers(ctx context.Context) error {
+	if s.redisClient == nil {
+		return nil
+	}
+	dealers, err := s.dealerRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, dealer := range dealers {
+		if dealer.DeactivatedAt != nil {
+			s.markDisabled(ctx, dealer.ID)
+		}
+	}
+	return nil
+}
+
+func (s *DealerOffboardingService) markDisabled(ctx context.Context, dealerID string) {
+	if s.redisClient == nil {
+		return
+	}
+	if err := s.redisClient.SAdd(ctx, disabledDealersKey, dealerID).Err(); err != nil {
+		log.Printf("⚠️  Failed to disable sessions of dealer %s: %v", dealerID, err)
+	}
+}
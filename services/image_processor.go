@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"path"
+	"strings"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/utils"
+)
+
+const (
+	maxOriginalPhotoBytes  = 25 << 20
+	maxOriginalPhotoPixels = 50_000_000
+	maxWatermarkLogoBytes  = 2 << 20
+	// Photos decoded and encoded at once across all jobs; each can hold a full-size original in memory
+	maxConcurrentPhotoJobs = 4
+)
+
+type photoVariantSpec struct {
+	Name      string
+	MaxSide   int
+	Quality   int
+	Watermark bool
+}
+
+// Thumbnails are too small for a legible watermark and are left clean
+var photoVariantSpecs = []photoVariantSpec{
+	{Name: models.PhotoVariantThumbnail, MaxSide: 320, Quality: 75},
+	{Name: models.PhotoVariantMedium, MaxSide: 800, Quality: 80, Watermark: true},
+	{Name: models.PhotoVariantLarge, MaxSide: 1600, Quality: 85, Watermark: true},
+}
+
+// Watermark is a dealer's branding resolved and ready to be drawn
+type Watermark struct {
+	Settings models.WatermarkSettings
+	Text     string
+	Logo     image.Image
+}
+
+// ImageProcessingService turns uploaded originals into resized, metadata-free JPEG variants
+type ImageProcessingService struct {
+	Storage   ObjectStorage
+	PublicURL string
+
+	slots chan struct{}
+}
+
+func NewImageProcessingService(storage ObjectStorage, publicURL string) *ImageProcessingService {
+	return &ImageProcessingService{
+		Storage:   storage,
+		PublicURL: publicURL,
+		slots:     make(chan struct{}, maxConcurrentPhotoJobs),
+	}
+}
+
+// LoadWatermark resolves a dealer's watermark settings, returning nil when watermarking is off
+func (s *ImageProcessingService) LoadWatermark(ctx context.Context, dealer models.Dealer) (*Watermark, error) {
+	if dealer.Watermark == nil || !dealer.Watermark.Enabled {
+		return nil, nil
+	}
+
+	watermark := &Watermark{Settings: *dealer.Watermark}
+	switch dealer.Watermark.Type {
+	case constants.WatermarkTypeLogo:
+		data, _, err := s.Storage.DownloadObject(ctx, dealer.Watermark.LogoKey, maxWatermarkLogoBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load watermark logo: %w", err)
+		}
+		logo, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode watermark logo: %w", err)
+		}
+		watermark.Logo = logo
+	default:
+		if dealer.ShopName == "" {
+			return nil, nil
+		}
+		watermark.Text = dealer.ShopName
+	}
+
+	return watermark, nil
+}
+
+// ProcessPhoto downloads the original at key and stores one variant per size under a derived key.
+// Re-encoding drops the EXIF block entirely, so GPS coordinates never reach the public variants.
+// A nil watermark produces unbranded variants. Calls beyond maxConcurrentPhotoJobs wait for a free slot.
+func (s *ImageProcessingService) ProcessPhoto(ctx context.Context, key string, watermark *Watermark) (map[string]string, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	data, _, err := s.Storage.DownloadObject(ctx, key, maxOriginalPhotoBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodePhoto(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variant keys are stable across re-renders, so the URL carries a version to bust CDN caches
+	version := time.Now().Unix()
+	variants := make(map[string]string, len(photoVariantSpecs))
+	for _, spec := range photoVariantSpecs {
+		var buf bytes.Buffer
+		resized := utils.ResizeToFit(img, spec.MaxSide)
+		if spec.Watermark && watermark != nil {
+			resized = applyWatermark(resized, watermark)
+		}
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: spec.Quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode %s variant: %w", spec.Name, err)
+		}
+
+		variantKey := PhotoVariantKey(key, spec.Name)
+		if err := s.Storage.UploadObject(ctx, variantKey, buf.Bytes(), "image/jpeg"); err != nil {
+			return nil, err
+		}
+		variants[spec.Name] = fmt.Sprintf("%s%s?v=%d", s.PublicURL, variantKey, version)
+	}
+
+	return variants, nil
+}
+
+// PhotoVariantKey derives the storage key of a variant, e.g. "a/b/photo.jpg" -> "a/b/photo_medium.jpg"
+func PhotoVariantKey(key string, variant string) string {
+	base := strings.TrimSuffix(key, path.Ext(key))
+	return fmt.Sprintf("%s_%s.jpg", base, variant)
+}
+
+func applyWatermark(img image.Image, watermark *Watermark) image.Image {
+	if watermark.Logo != nil {
+		return utils.DrawImageWatermark(img, watermark.Logo, watermark.Settings.Position, watermark.Settings.Opacity)
+	}
+	return utils.DrawTextWatermark(img, watermark.Text, watermark.Settings.Position, watermark.Settings.Opacity)
+}
+
+func decodePhoto(data []byte) (image.Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	if config.Width*config.Height > maxOriginalPhotoPixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are too large", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img = utils.ApplyOrientation(img, utils.JPEGOrientation(data))
+	}
+
+	return img, nil
+}
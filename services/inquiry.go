@@ -2,21 +2,55 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"myapp/constants"
 	"myapp/models"
 	"myapp/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	ErrInquiryNotFound          = errors.New("inquiry not found")
+	ErrInquiryNotOwned          = errors.New("inquiry belongs to another dealer")
+	ErrInquiryConversionInvalid = errors.New("invalid inquiry conversion")
+	ErrInquiryAlreadyConverted  = errors.New("inquiry has already been converted")
+)
+
+// A conversion claimed this long ago that never completed was interrupted, and may be retried
+const staleConversionClaim = 10 * time.Minute
+
 type InquiryService struct {
-	inquiryRepo repositories.InquiryRepository
+	inquiryRepo   repositories.InquiryRepository
+	parser        *RequirementParser
+	leads         *LeadService
+	dealerClients *DealerClientService
 }
 
-func NewInquiryService(inquiryRepo repositories.InquiryRepository) *InquiryService {
+func NewInquiryService(inquiryRepo repositories.InquiryRepository, parser *RequirementParser, leads *LeadService, dealerClients *DealerClientService) *InquiryService {
 	return &InquiryService{
-		inquiryRepo: inquiryRepo,
+		inquiryRepo:   inquiryRepo,
+		parser:        parser,
+		leads:         leads,
+		dealerClients: dealerClients,
 	}
 }
 
 func (s *InquiryService) CreateInquiry(ctx context.Context, inquiry models.Inquiry) (models.Inquiry, error) {
+	criteria, err := NormalizeRequirementCriteria(inquiry.Criteria)
+	if err != nil {
+		return models.Inquiry{}, err
+	}
+	// The free-text requirement is kept as written; criteria are only inferred when none were sent
+	inquiry.Criteria = s.parser.fillCriteria(ctx, criteria, inquiry.Requirement)
+
 	return s.inquiryRepo.Create(ctx, inquiry)
 }
 
@@ -29,9 +63,158 @@ func (s *InquiryService) GetAllInquiries(ctx context.Context, params models.Inqu
 }
 
 func (s *InquiryService) UpdateInquiry(ctx context.Context, id string, updates models.InquiryUpdate) error {
+	if updates.Criteria != nil {
+		criteria, err := NormalizeRequirementCriteria(updates.Criteria)
+		if err != nil {
+			return err
+		}
+		updates.Criteria = criteria
+	}
 	return s.inquiryRepo.Update(ctx, id, updates)
 }
 
 func (s *InquiryService) DeleteInquiry(ctx context.Context, id string) error {
 	return s.inquiryRepo.Delete(ctx, id)
 }
+
+// ConvertInquiry turns an inquiry into a lead, or into a client of the dealer it came from, carrying
+// over the name, phone and requirement. An inquiry from someone who already has a lead is linked
+// to that lead. The inquiry is claimed before anything is created, so two people converting it at
+// once end up with one record and an ErrInquiryAlreadyConverted; a claim left behind by a failed
+// conversion can be retried after staleConversionClaim.
+func (s *InquiryService) ConvertInquiry(ctx context.Context, id string, convertTo string, userID string, role string) (models.Inquiry, error) {
+	inquiry, err := s.inquiryRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.Inquiry{}, ErrInquiryNotFound
+		}
+		return models.Inquiry{}, err
+	}
+
+	// Inquiries entered by an admin carry the admin's ID, which is not a dealer
+	dealerID := ""
+	if inquiry.DealerID != nil && primitive.IsValidObjectID(*inquiry.DealerID) {
+		dealerID = *inquiry.DealerID
+	}
+	if role != constants.Admin && (dealerID == "" || dealerID != userID) {
+		return models.Inquiry{}, ErrInquiryNotOwned
+	}
+	now := time.Now()
+	staleBefore := now.Add(-staleConversionClaim)
+	if inquiry.Converted && (inquiry.ConvertedID != "" || inquiry.ConvertedAt == nil || inquiry.ConvertedAt.After(staleBefore)) {
+		return models.Inquiry{}, ErrInquiryAlreadyConverted
+	}
+
+	convertTo = strings.TrimSpace(convertTo)
+	if convertTo == "" {
+		convertTo = models.InquiryConvertedToLead
+		if dealerID != "" {
+			convertTo = models.InquiryConvertedToDealerClient
+		}
+	}
+	switch convertTo {
+	case models.InquiryConvertedToLead:
+		if role != constants.Admin {
+			return models.Inquiry{}, fmt.Errorf("%w: only admins can turn an inquiry into a lead", ErrInquiryConversionInvalid)
+		}
+	case models.InquiryConvertedToDealerClient:
+		if dealerID == "" {
+			return models.Inquiry{}, fmt.Errorf("%w: only inquiries from a dealer can become dealer clients", ErrInquiryConversionInvalid)
+		}
+	default:
+		return models.Inquiry{}, fmt.Errorf("%w: convert_to must be %q or %q", ErrInquiryConversionInvalid, models.InquiryConvertedToLead, models.InquiryConvertedToDealerClient)
+	}
+	if strings.TrimSpace(inquiry.Name) == "" || strings.TrimSpace(inquiry.Phone) == "" {
+		return models.Inquiry{}, fmt.Errorf("%w: the inquiry needs a name and phone", ErrInquiryConversionInvalid)
+	}
+
+	if err := s.inquiryRepo.ClaimConversion(ctx, id, convertTo, userID, now, staleBefore); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Inquiry{}, ErrInquiryAlreadyConverted
+		}
+		return models.Inquiry{}, err
+	}
+
+	var convertedID string
+	if convertTo == models.InquiryConvertedToLead {
+		convertedID, err = s.leadForInquiry(ctx, inquiry, userID)
+	} else {
+		convertedID, err = s.interruptedDealerClient(ctx, inquiry, dealerID)
+		if err == nil && convertedID == "" {
+			convertedID, err = s.dealerClients.CreateDealerClient(ctx, models.DealerClient{
+				DealerID:        dealerID,
+				Name:            inquiry.Name,
+				Phone:           inquiry.Phone,
+				Note:            inquiry.Requirement,
+				Criteria:        inquiry.Criteria,
+				SourceInquiryID: inquiry.ID,
+			}, userID)
+		}
+	}
+	if err != nil {
+		if releaseErr := s.inquiryRepo.ReleaseConversion(ctx, id); releaseErr != nil {
+			log.Printf("⚠️  Failed to release conversion of inquiry %s: %v", id, releaseErr)
+		}
+		return models.Inquiry{}, err
+	}
+
+	return s.inquiryRepo.CompleteConversion(ctx, id, convertedID)
+}
+
+// leadForInquiry creates a lead from the inquiry, or links the inquiry to the lead that already
+// has its phone number and notes it on that lead's timeline
+func (s *InquiryService) leadForInquiry(ctx context.Context, inquiry models.Inquiry, userID string) (string, error) {
+	existing, err := s.leads.Repo.FindByPhone(ctx, inquiry.Phone)
+	if err == mongo.ErrNoDocuments {
+		leadID, err := s.leads.CreateLead(ctx, models.Lead{
+			Name:            inquiry.Name,
+			Phone:           inquiry.Phone,
+			Requirement:     inquiry.Requirement,
+			Criteria:        inquiry.Criteria,
+			SourceInquiryID: inquiry.ID,
+		}, userID)
+		if err != ErrLeadPhoneExists {
+			return leadID, err
+		}
+		// Created by someone else since the lookup
+		existing, err = s.leads.Repo.FindByPhone(ctx, inquiry.Phone)
+	}
+	if err != nil {
+		return "", err
+	}
+	// An interrupted conversion of this inquiry already created the lead
+	if existing.SourceInquiryID == inquiry.ID {
+		return existing.ID, nil
+	}
+
+	s.leads.Activities.Record(ctx, models.Activity{
+		SubjectType: models.ActivitySubjectLead,
+		SubjectID:   existing.ID,
+		Type:        models.ActivityNote,
+		Body:        activityBody("Inquiry from "+inquiry.Name+" linked to this lead", inquiry.Requirement),
+		AuthorID:    userID,
+	})
+	return existing.ID, nil
+}
+
+// interruptedDealerClient finds the client an interrupted conversion of the inquiry created before
+// it could be completed, so a retry links to it instead of failing on the duplicate phone
+func (s *InquiryService) interruptedDealerClient(ctx context.Context, inquiry models.Inquiry, dealerID string) (string, error) {
+	if !inquiry.Converted {
+		return "", nil
+	}
+	inquiryObjectID, err := primitive.ObjectIDFromHex(inquiry.ID)
+	if err != nil {
+		return "", err
+	}
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return "", err
+	}
+
+	clients, err := s.dealerClients.Repo.GetFilteredDealerClients(ctx, bson.M{"dealer_id": dealerObjectID, "source_inquiry_id": inquiryObjectID}, 1)
+	if err != nil || len(clients) == 0 {
+		return "", err
+	}
+	return clients[0].ID, nil
+}
@@ -2,31 +2,77 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"myapp/converters"
 	"myapp/models"
 	"myapp/repositories"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrPropertyInterestNotFound = errors.New("property interest not found for this lead")
+	ErrLeadStatusInvalid        = errors.New("invalid status change")
+	ErrLeadStatusConflict       = errors.New("the interest's status was changed by someone else; reload and try again")
+	ErrLeadPhoneExists          = errors.New("lead with phone already exists")
 )
 
 type LeadService struct {
 	Repo repositories.LeadRepository
 	PropertyRepo repositories.PropertyRepository
+	Parser       *RequirementParser
+	Analytics    *AnalyticsService
+	Routing      *LeadRoutingService
+	Activities   *ActivityService
+	Aadhar       *AadharService
 }
 
-func (s *LeadService) CreateLead(ctx context.Context, lead models.Lead) (string, error) {
+// CreateLead stores the lead and routes it; userID is who created it, for the lead's timeline
+func (s *LeadService) CreateLead(ctx context.Context, lead models.Lead, userID string) (string, error) {
 	exists, err := s.Repo.CheckPhoneExists(ctx, lead.Phone)
 	if err != nil {
 		return "", errors.New("database error checking phone")
 	}
 	if exists {
-		return "", errors.New("lead with phone already exists")
+		return "", ErrLeadPhoneExists
+	}
+
+	criteria, err := NormalizeRequirementCriteria(lead.Criteria)
+	if err != nil {
+		return "", err
+	}
+	// The free-text requirement is kept as written; criteria are only inferred when none were sent
+	lead.Criteria = s.Parser.fillCriteria(ctx, criteria, lead.Requirement)
+	lead.StoredAadhar = models.StoredAadhar{}
+	if strings.TrimSpace(lead.AadharNumber) != "" {
+		if lead.StoredAadhar, err = s.Aadhar.Seal(lead.AadharNumber); err != nil {
+			return "", err
+		}
 	}
+	lead.StoredAadhar.Photo = strings.TrimSpace(lead.AadharPhoto)
+	// Ownership only changes through routing so that it is always in the assignment history
+	lead.AssignedDealerID = ""
+	lead.AssignedAt = nil
 
-	return s.Repo.Create(ctx, lead)
+	id, err := s.Repo.Create(ctx, lead)
+	if err != nil {
+		return "", err
+	}
+	lead.ID = id
+	summary := "Lead created"
+	if lead.SourceInquiryID != "" {
+		summary = "Lead created from an inquiry"
+	}
+	s.Activities.Record(ctx, models.Activity{SubjectType: models.ActivitySubjectLead, SubjectID: id, Type: models.ActivityCreated, Body: activityBody(summary, lead.Requirement), AuthorID: userID})
+	s.Routing.AssignNewLead(ctx, lead)
+	return id, nil
 }
 
 func (s *LeadService) GetLeadByID(ctx context.Context, id string) (models.Lead, error) {
@@ -39,23 +85,118 @@ func (s *LeadService) GetAllLeadsByDealerID(ctx context.Context, dealerID string
 	return s.Repo.GetByDealerID(ctx, dealerID)
 }
 
-func (s *LeadService) UpdateLead(ctx context.Context, id string, updateData map[string]interface{}) error {
-	return s.Repo.Update(ctx, id, updateData)
+func (s *LeadService) UpdateLead(ctx context.Context, id string, updateData map[string]interface{}, userID string) error {
+	for _, key := range []string{"assigned_dealer_id", "assigned_at"} {
+		if _, ok := updateData[key]; ok {
+			return fmt.Errorf("%w: use the assignment endpoint to change the lead's dealer", ErrLeadAssignInvalid)
+		}
+	}
+	// The link to the inquiry a lead was converted from is set once, by the conversion
+	delete(updateData, "source_inquiry_id")
+	// The Aadhaar number is only ever stored encrypted, alongside fields derived from it
+	for _, key := range []string{"aadhar_key_version", "aadhar_last4", "aadhar_hash", "aadhar_hash_key"} {
+		delete(updateData, key)
+	}
+	if raw, ok := updateData["aadhar_number"]; ok {
+		number, isString := raw.(string)
+		if !isString && raw != nil {
+			return fmt.Errorf("%w: aadhar_number must be a string", ErrAadharInvalid)
+		}
+		stored := models.StoredAadhar{}
+		if strings.TrimSpace(number) != "" {
+			var err error
+			if stored, err = s.Aadhar.Seal(number); err != nil {
+				return err
+			}
+		}
+		for key, value := range converters.ToMongoAadharNumberUpdate(stored) {
+			updateData[key] = value
+		}
+	}
+	if raw, ok := updateData["criteria"]; ok {
+		// Updates arrive as decoded JSON, so round-trip the criteria to validate them
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRequirementInvalid, err)
+		}
+		var criteria *models.RequirementCriteria
+		if err := json.Unmarshal(encoded, &criteria); err != nil {
+			return fmt.Errorf("%w: %v", ErrRequirementInvalid, err)
+		}
+		criteria, err = NormalizeRequirementCriteria(criteria)
+		if err != nil {
+			return err
+		}
+		updateData["criteria"] = converters.ToMongoRequirementCriteria(criteria)
+	}
+
+	// A new Aadhaar photo goes on the timeline like a client's documents do
+	photo, _ := updateData["aadhar_photo"].(string)
+	photo = strings.TrimSpace(photo)
+	previousPhoto := ""
+	if photo != "" {
+		existing, err := s.Repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		previousPhoto = existing.StoredAadhar.Photo
+	}
+	if err := s.Repo.Update(ctx, id, updateData); err != nil {
+		return err
+	}
+	if photo != "" && photo != previousPhoto {
+		s.Activities.Record(ctx, models.Activity{SubjectType: models.ActivitySubjectLead, SubjectID: id, Type: models.ActivityDocumentAdded, Body: "Document added (Aadhaar photo)", AuthorID: userID})
+	}
+	return nil
 }
 
 func (s *LeadService) DeleteLead(ctx context.Context, id string) error {
 	return s.Repo.Delete(ctx, id)
 }
 
-func (s *LeadService) AddPropertyInterest(ctx context.Context, leadID string, propertyInterest models.PropertyInterest) error {
-	// Set status
-	propertyInterest.Status = "view"
-	propertyInterest.CreatedAt = time.Now()
+func (s *LeadService) AddPropertyInterest(ctx context.Context, leadID string, propertyInterest models.PropertyInterest, userID string) error {
+	// Every interest enters the pipeline at view
+	now := time.Now()
+	propertyInterest.Status = models.LeadStatusView
+	propertyInterest.CreatedAt = now
+	propertyInterest.UpdatedAt = now
+	propertyInterest.StatusChangedAt = &now
+	propertyInterest.History = []models.PropertyInterestStatusChange{{To: models.LeadStatusView, Note: propertyInterest.Note, ChangedAt: now}}
 
-	return s.Repo.AddPropertyInterest(ctx, leadID, propertyInterest)
+	if err := s.Repo.AddPropertyInterest(ctx, leadID, propertyInterest); err != nil {
+		return err
+	}
+	// Counted against the listing's dealer as stored, not the dealer the request names
+	if property, err := s.PropertyRepo.GetByID(ctx, propertyInterest.PropertyID); err == nil {
+		s.Analytics.Record(property.ID, property.DealerID, models.PropertyEventInterest, "")
+	}
+	s.Activities.Record(ctx, models.Activity{
+		SubjectType: models.ActivitySubjectLead,
+		SubjectID:   leadID,
+		Type:        models.ActivityPropertyAdded,
+		Body:        activityBody(fmt.Sprintf("Interested in property #%d", propertyInterest.PropertyNumber), propertyInterest.Note),
+		PropertyID:  propertyInterest.PropertyID,
+		AuthorID:    userID,
+		OccurredAt:  now,
+	})
+	return nil
+}
+
+// GetAssignedLeads lists the leads the dealer owns, whether or not they have shown interest in
+// any of the dealer's listings
+func (s *LeadService) GetAssignedLeads(ctx context.Context, dealerID string, params models.LeadQueryParams) ([]models.Lead, error) {
+	params.AssignedDealerID = &dealerID
+	params.DealerID = nil
+	if !s.hashAadharFilter(&params) {
+		return nil, nil
+	}
+	return s.Repo.GetLeads(ctx, params)
 }
 
 func (s *LeadService) GetLeads(ctx context.Context, params models.LeadQueryParams) ([]models.Lead, error) {
+	if !s.hashAadharFilter(&params) {
+		return nil, nil
+	}
 	leads, err := s.Repo.GetLeads(ctx, params)
 	if err != nil {
 		return nil, err
@@ -113,7 +254,108 @@ func (s *LeadService) GetLeads(ctx context.Context, params models.LeadQueryParam
 }
 
 
-func (s *LeadService) UpdatePropertyInterest(ctx context.Context, leadID string, propertyID string, status string, note string) error {
-	return s.Repo.UpdatePropertyInterest(ctx, leadID, propertyID, status, note)
+// ChangePropertyInterestStatus moves the lead's interest in a property along the pipeline.
+// Conversion needs the sold price and loss needs a reason; anything else is refused.
+func (s *LeadService) ChangePropertyInterestStatus(ctx context.Context, leadID string, propertyID string, update models.PropertyInterestStatusUpdate, changedBy string) (models.PropertyInterest, error) {
+	to := strings.ToLower(strings.TrimSpace(update.Status))
+	note := strings.TrimSpace(update.Note)
+	reason := strings.TrimSpace(update.Reason)
+	if _, known := models.LeadStatusTransitions[to]; !known || to == models.LeadStatusClosed {
+		return models.PropertyInterest{}, fmt.Errorf("%w: unknown status %q", ErrLeadStatusInvalid, update.Status)
+	}
+	if len(note) > 1000 {
+		return models.PropertyInterest{}, fmt.Errorf("%w: note must be at most 1000 characters", ErrLeadStatusInvalid)
+	}
+
+	switch {
+	case to == models.LeadStatusConverted && update.SoldPrice <= 0:
+		return models.PropertyInterest{}, fmt.Errorf("%w: sold_price is required to convert", ErrLeadStatusInvalid)
+	case to != models.LeadStatusConverted && update.SoldPrice != 0:
+		return models.PropertyInterest{}, fmt.Errorf("%w: sold_price is only accepted when converting", ErrLeadStatusInvalid)
+	case to == models.LeadStatusLost && reason == "":
+		return models.PropertyInterest{}, fmt.Errorf("%w: reason is required when the lead is lost", ErrLeadStatusInvalid)
+	case to != models.LeadStatusLost && reason != "":
+		return models.PropertyInterest{}, fmt.Errorf("%w: reason is only accepted when the lead is lost", ErrLeadStatusInvalid)
+	case len(reason) > 500:
+		return models.PropertyInterest{}, fmt.Errorf("%w: reason must be at most 500 characters", ErrLeadStatusInvalid)
+	}
+
+	interest, err := s.Repo.GetPropertyInterest(ctx, leadID, propertyID)
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return models.PropertyInterest{}, ErrPropertyInterestNotFound
+	}
+	if err != nil {
+		return models.PropertyInterest{}, err
+	}
+	if !models.CanTransitionLeadStatus(interest.Status, to) {
+		return models.PropertyInterest{}, fmt.Errorf("%w: cannot move from %s to %s", ErrLeadStatusInvalid, interest.Status, to)
+	}
+
+	change := models.PropertyInterestStatusChange{
+		From:      interest.Status,
+		To:        to,
+		Note:      note,
+		Reason:    reason,
+		SoldPrice: update.SoldPrice,
+		ChangedBy: changedBy,
+		ChangedAt: time.Now(),
+	}
+	if err := s.Repo.ChangePropertyInterestStatus(ctx, leadID, propertyID, change); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.PropertyInterest{}, ErrLeadStatusConflict
+		}
+		return models.PropertyInterest{}, err
+	}
+
+	interest.Status = to
+	interest.StatusChangedAt = &change.ChangedAt
+	interest.UpdatedAt = change.ChangedAt
+	interest.LostReason = reason
+	if note != "" {
+		interest.Note = note
+	}
+	if update.SoldPrice > 0 {
+		interest.SoldPrice = update.SoldPrice
+	}
+	interest.History = append(interest.History, change)
+	s.Activities.Record(ctx, models.Activity{
+		SubjectType: models.ActivitySubjectLead,
+		SubjectID:   leadID,
+		Type:        models.ActivityStatusChange,
+		Body:        activityBody(fmt.Sprintf("Property #%d moved from %s to %s", interest.PropertyNumber, change.From, change.To), reason, note),
+		PropertyID:  propertyID,
+		AuthorID:    changedBy,
+		OccurredAt:  change.ChangedAt,
+	})
+	return interest, nil
+}
+
+// hashAadharFilter swaps an Aadhaar number being searched for with its lookup hash. It returns
+// false when no lead can match.
+func (s *LeadService) hashAadharFilter(params *models.LeadQueryParams) bool {
+	if params.AadharNumber == nil {
+		return true
+	}
+	hash := s.Aadhar.LookupHash(*params.AadharNumber)
+	if hash == "" {
+		return false
+	}
+	params.AadharNumber = &hash
+	return true
 }
 
+// dealerCanSeeLead reports whether a dealer may work a lead: it is assigned to them or interested
+// in one of their listings
+func dealerCanSeeLead(ctx context.Context, leadRepo repositories.LeadRepository, lead models.Lead, dealerID string) (bool, error) {
+	if lead.AssignedDealerID == dealerID {
+		return true, nil
+	}
+	// GetByID does not load interests, so check them through the dealer-scoped query
+	params := models.LeadQueryParams{ID: &lead.ID, DealerID: &dealerID}
+	params.AddArrayFilter("dealer_id")
+	visible, err := leadRepo.GetLeads(ctx, params)
+	if err != nil {
+		return false, err
+	}
+	return len(visible) > 0, nil
+}
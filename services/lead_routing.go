@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrRoutingRuleInvalid   = errors.New("invalid routing rule")
+	ErrRoutingRuleNotFound  = errors.New("routing rule not found")
+	ErrLeadNotFound         = errors.New("lead not found")
+	ErrLeadAssignInvalid    = errors.New("invalid lead assignment")
+	ErrAssigneeNotAvailable = errors.New("dealer does not exist or is deactivated")
+	ErrLeadNotRouted        = errors.New("no routing rule could place the lead")
+	ErrLeadAssignConflict   = errors.New("lead was reassigned meanwhile; reload it and try again")
+)
+
+// LeadRoutingService decides which dealer owns each lead. New leads are placed by the first
+// active routing rule that has a dealer with room for them; admins can reassign by hand. Every
+// change of owner is kept in the lead's assignment history.
+type LeadRoutingService struct {
+	repo          repositories.LeadRoutingRepository
+	leadRepo      repositories.LeadRepository
+	dealerRepo    repositories.DealerRepository
+	notifications *NotificationService
+}
+
+func NewLeadRoutingService(repo repositories.LeadRoutingRepository, leadRepo repositories.LeadRepository, dealerRepo repositories.DealerRepository, notifications *NotificationService) *LeadRoutingService {
+	return &LeadRoutingService{
+		repo:          repo,
+		leadRepo:      leadRepo,
+		dealerRepo:    dealerRepo,
+		notifications: notifications,
+	}
+}
+
+func (s *LeadRoutingService) CreateRule(ctx context.Context, req models.LeadRoutingRuleRequest) (models.LeadRoutingRule, error) {
+	rule := models.LeadRoutingRule{Active: true}
+	if err := s.applyRule(ctx, &rule, req); err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+	return s.repo.CreateRule(ctx, rule)
+}
+
+func (s *LeadRoutingService) GetRules(ctx context.Context) ([]models.LeadRoutingRule, error) {
+	return s.repo.GetRules(ctx, false)
+}
+
+func (s *LeadRoutingService) GetRule(ctx context.Context, id string) (models.LeadRoutingRule, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return models.LeadRoutingRule{}, ErrRoutingRuleNotFound
+	}
+	return rule, err
+}
+
+func (s *LeadRoutingService) UpdateRule(ctx context.Context, id string, req models.LeadRoutingRuleRequest) (models.LeadRoutingRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+	if err := s.applyRule(ctx, &rule, req); err != nil {
+		return models.LeadRoutingRule{}, err
+	}
+
+	updated, err := s.repo.UpdateRule(ctx, rule)
+	if err == mongo.ErrNoDocuments {
+		return models.LeadRoutingRule{}, ErrRoutingRuleNotFound
+	}
+	return updated, err
+}
+
+func (s *LeadRoutingService) DeleteRule(ctx context.Context, id string) error {
+	err := s.repo.DeleteRule(ctx, id)
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return ErrRoutingRuleNotFound
+	}
+	return err
+}
+
+// applyRule validates the request and copies it onto the rule
+func (s *LeadRoutingService) applyRule(ctx context.Context, rule *models.LeadRoutingRule, req models.LeadRoutingRuleRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrRoutingRuleInvalid)
+	}
+
+	strategy := strings.ToLower(strings.TrimSpace(req.Strategy))
+	if strategy == "" {
+		strategy = models.LeadRoutingRoundRobin
+	}
+	if strategy != models.LeadRoutingRoundRobin && strategy != models.LeadRoutingLeastLoaded {
+		return fmt.Errorf("%w: strategy must be %s or %s", ErrRoutingRuleInvalid, models.LeadRoutingRoundRobin, models.LeadRoutingLeastLoaded)
+	}
+
+	locations := cleanList(req.Locations, false)
+	for _, location := range locations {
+		if !constants.IsValidLocation(location) {
+			return fmt.Errorf("%w: unknown location %q", ErrRoutingRuleInvalid, location)
+		}
+	}
+
+	if req.MinBudget < 0 || req.MaxBudget < 0 {
+		return fmt.Errorf("%w: budgets cannot be negative", ErrRoutingRuleInvalid)
+	}
+	if req.MaxBudget > 0 && req.MinBudget > req.MaxBudget {
+		return fmt.Errorf("%w: min_budget cannot exceed max_budget", ErrRoutingRuleInvalid)
+	}
+	if req.DealerCapacity < 0 {
+		return fmt.Errorf("%w: dealer_capacity cannot be negative", ErrRoutingRuleInvalid)
+	}
+
+	dealerIDs := cleanList(req.DealerIDs, true)
+	for _, dealerID := range dealerIDs {
+		dealer, err := s.dealerRepo.GetByID(ctx, dealerID)
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return fmt.Errorf("%w: dealer %s not found", ErrRoutingRuleInvalid, dealerID)
+		}
+		if err != nil {
+			return err
+		}
+		if dealer.DeactivatedAt != nil {
+			return fmt.Errorf("%w: dealer %s is deactivated", ErrRoutingRuleInvalid, dealerID)
+		}
+	}
+
+	rule.Name = name
+	rule.Priority = req.Priority
+	rule.Locations = locations
+	rule.SubLocations = cleanList(req.SubLocations, false)
+	rule.MinBudget = req.MinBudget
+	rule.MaxBudget = req.MaxBudget
+	rule.DealerIDs = dealerIDs
+	rule.Strategy = strategy
+	rule.DealerCapacity = req.DealerCapacity
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return nil
+}
+
+// AssignNewLead routes a lead that has just been created. A lead no rule can place is left
+// unassigned for an admin to pick up, so failures are only logged.
+func (s *LeadRoutingService) AssignNewLead(ctx context.Context, lead models.Lead) {
+	if s == nil {
+		return
+	}
+	if _, err := s.route(ctx, lead, ""); err != nil && err != ErrLeadNotRouted {
+		log.Printf("⚠️  Failed to route lead %s: %v", lead.ID, err)
+	}
+}
+
+// RouteLead runs the routing rules for an existing lead, such as one created before the rules
+// or one whose dealer had no room at the time
+func (s *LeadRoutingService) RouteLead(ctx context.Context, leadID string, adminID string) (models.LeadAssignment, error) {
+	lead, err := s.getLead(ctx, leadID)
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	return s.route(ctx, lead, adminID)
+}
+
+// AssignLead hands the lead to the dealer in the request, or unassigns it when no dealer is given
+func (s *LeadRoutingService) AssignLead(ctx context.Context, leadID string, req models.LeadAssignRequest, adminID string) (models.LeadAssignment, error) {
+	req.DealerID = strings.TrimSpace(req.DealerID)
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > 500 {
+		return models.LeadAssignment{}, fmt.Errorf("%w: note must be at most 500 characters", ErrLeadAssignInvalid)
+	}
+
+	lead, err := s.getLead(ctx, leadID)
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	if req.DealerID == lead.AssignedDealerID {
+		if req.DealerID == "" {
+			return models.LeadAssignment{}, fmt.Errorf("%w: lead is not assigned", ErrLeadAssignInvalid)
+		}
+		return models.LeadAssignment{}, fmt.Errorf("%w: lead is already assigned to this dealer", ErrLeadAssignInvalid)
+	}
+
+	if req.DealerID != "" {
+		dealer, err := s.dealerRepo.GetByID(ctx, req.DealerID)
+		if err != nil && err != mongo.ErrNoDocuments && !errors.Is(err, primitive.ErrInvalidHex) {
+			return models.LeadAssignment{}, err
+		}
+		if err != nil || dealer.DeactivatedAt != nil {
+			return models.LeadAssignment{}, ErrAssigneeNotAvailable
+		}
+	}
+
+	// Admins may hand a dealer more leads than the routing rules would
+	return s.assign(ctx, lead, models.LeadAssignment{
+		ToDealerID: req.DealerID,
+		Method:     models.LeadAssignmentManual,
+		AssignedBy: adminID,
+		Note:       req.Note,
+	}, 0)
+}
+
+func (s *LeadRoutingService) GetAssignments(ctx context.Context, leadID string) ([]models.LeadAssignment, error) {
+	if _, err := s.getLead(ctx, leadID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAssignments(ctx, leadID)
+}
+
+func (s *LeadRoutingService) getLead(ctx context.Context, leadID string) (models.Lead, error) {
+	lead, err := s.leadRepo.GetByID(ctx, leadID)
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return models.Lead{}, ErrLeadNotFound
+	}
+	return lead, err
+}
+
+// route tries the active rules in order and assigns the lead with the first that finds a dealer
+func (s *LeadRoutingService) route(ctx context.Context, lead models.Lead, assignedBy string) (models.LeadAssignment, error) {
+	rules, err := s.repo.GetRules(ctx, true)
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	if len(rules) == 0 {
+		return models.LeadAssignment{}, ErrLeadNotRouted
+	}
+
+	dealers, err := s.dealerRepo.GetAll(ctx)
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+	active := make([]models.Dealer, 0, len(dealers))
+	for _, dealer := range dealers {
+		if dealer.DeactivatedAt == nil {
+			active = append(active, dealer)
+		}
+	}
+
+	var criteria models.RequirementCriteria
+	if lead.Criteria != nil {
+		criteria = *lead.Criteria
+	}
+	locations := leadLocations(criteria, active)
+
+	for _, rule := range rules {
+		if !ruleMatches(rule, criteria, locations) {
+			continue
+		}
+		dealerID, err := s.pickDealer(ctx, rule, ruleCandidates(rule, criteria, locations, active))
+		if err != nil {
+			return models.LeadAssignment{}, err
+		}
+		if dealerID == "" || dealerID == lead.AssignedDealerID {
+			continue
+		}
+
+		assignment, err := s.assign(ctx, lead, models.LeadAssignment{
+			ToDealerID: dealerID,
+			Method:     models.LeadAssignmentRule,
+			RuleID:     rule.ID,
+			AssignedBy: assignedBy,
+			Note:       rule.Name,
+		}, rule.DealerCapacity)
+		// The dealer filled up after the loads were counted; later rules may still place the lead
+		if err == repositories.ErrDealerAtCapacity {
+			continue
+		}
+		return assignment, err
+	}
+	return models.LeadAssignment{}, ErrLeadNotRouted
+}
+
+// pickDealer chooses among the candidates by the rule's strategy, passing over dealers at
+// capacity. It returns an empty ID when every candidate is full. The loads are only a guide;
+// assign checks the capacity again as it moves the lead.
+func (s *LeadRoutingService) pickDealer(ctx context.Context, rule models.LeadRoutingRule, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	loads, err := s.leadRepo.CountAssigned(ctx, candidates)
+	if err != nil {
+		return "", err
+	}
+	hasRoom := func(dealerID string) bool {
+		return rule.DealerCapacity == 0 || loads[dealerID] < int64(rule.DealerCapacity)
+	}
+
+	if rule.Strategy == models.LeadRoutingLeastLoaded {
+		picked := ""
+		for _, dealerID := range candidates {
+			if hasRoom(dealerID) && (picked == "" || loads[dealerID] < loads[picked]) {
+				picked = dealerID
+			}
+		}
+		return picked, nil
+	}
+
+	// Full dealers are skipped without giving up the turn of the next dealer in line
+	turn, err := s.repo.NextTurn(ctx, rule.ID)
+	if err != nil {
+		return "", err
+	}
+	for i := range candidates {
+		dealerID := candidates[(int(turn%int64(len(candidates)))+i)%len(candidates)]
+		if hasRoom(dealerID) {
+			return dealerID, nil
+		}
+	}
+	return "", nil
+}
+
+// assign makes the change of owner and records it, provided the lead still has the owner it was
+// read with and, when capacity is above zero, the new dealer has room. It then lets the new
+// dealer know.
+func (s *LeadRoutingService) assign(ctx context.Context, lead models.Lead, assignment models.LeadAssignment, capacity int) (models.LeadAssignment, error) {
+	assignment.LeadID = lead.ID
+	assignment.FromDealerID = lead.AssignedDealerID
+	assignment.CreatedAt = time.Now()
+
+	recorded, err := s.repo.Assign(ctx, assignment, capacity)
+	if err == mongo.ErrNoDocuments {
+		return models.LeadAssignment{}, ErrLeadNotFound
+	}
+	if err == repositories.ErrLeadOwnerChanged {
+		return models.LeadAssignment{}, ErrLeadAssignConflict
+	}
+	if err != nil {
+		return models.LeadAssignment{}, err
+	}
+
+	if s.notifications != nil && assignment.ToDealerID != "" {
+		_, err := s.notifications.Notify(ctx, models.Notification{
+			RecipientID: assignment.ToDealerID,
+			Type:        models.NotificationLeadAssigned,
+			Title:       "New lead assigned",
+			Body:        fmt.Sprintf("%s has been assigned to you", lead.Name),
+			Data:        map[string]string{"lead_id": lead.ID},
+		})
+		if err != nil {
+			log.Printf("⚠️  Failed to notify dealer %s of lead %s: %v", assignment.ToDealerID, lead.ID, err)
+		}
+	}
+	return recorded, nil
+}
+
+// leadLocations is where the lead wants to be: the locations it names and the locations of the
+// sub-locations it names
+func leadLocations(criteria models.RequirementCriteria, dealers []models.Dealer) []string {
+	locations := append([]string(nil), criteria.Locations...)
+	for _, subLocation := range criteria.SubLocations {
+		for _, dealer := range dealers {
+			if dealer.Location != "" && strings.EqualFold(dealer.SubLocation, subLocation) {
+				locations = appendUnique(locations, dealer.Location)
+			}
+		}
+	}
+	return locations
+}
+
+func ruleMatches(rule models.LeadRoutingRule, criteria models.RequirementCriteria, locations []string) bool {
+	if len(rule.Locations) > 0 && len(intersectFold(locations, rule.Locations)) == 0 {
+		return false
+	}
+	if len(rule.SubLocations) > 0 && len(intersectFold(criteria.SubLocations, rule.SubLocations)) == 0 {
+		return false
+	}
+
+	if rule.MinBudget > 0 || rule.MaxBudget > 0 {
+		budget := criteria.MaxBudget
+		if budget == 0 {
+			budget = criteria.MinBudget
+		}
+		if budget == 0 || budget < rule.MinBudget || (rule.MaxBudget > 0 && budget > rule.MaxBudget) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleCandidates lists the active dealers the rule may assign the lead to, in a stable order so
+// round-robin turns stay fair
+func ruleCandidates(rule models.LeadRoutingRule, criteria models.RequirementCriteria, locations []string, dealers []models.Dealer) []string {
+	var candidates []string
+	if len(rule.DealerIDs) > 0 {
+		listed := make(map[string]bool, len(rule.DealerIDs))
+		for _, id := range rule.DealerIDs {
+			listed[id] = true
+		}
+		for _, dealer := range dealers {
+			if listed[dealer.ID] {
+				candidates = append(candidates, dealer.ID)
+			}
+		}
+	} else {
+		subLocations := criteria.SubLocations
+		if len(rule.SubLocations) > 0 {
+			subLocations = intersectFold(subLocations, rule.SubLocations)
+		}
+		if len(rule.Locations) > 0 {
+			locations = intersectFold(locations, rule.Locations)
+		}
+
+		for _, dealer := range dealers {
+			if containsFold(subLocations, dealer.SubLocation) {
+				candidates = append(candidates, dealer.ID)
+			}
+		}
+		if len(candidates) == 0 {
+			for _, dealer := range dealers {
+				if containsFold(locations, dealer.Location) {
+					candidates = append(candidates, dealer.ID)
+				}
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	return candidates
+}
+
+func intersectFold(values []string, allowed []string) []string {
+	var common []string
+	for _, value := range values {
+		if containsFold(allowed, value) {
+			common = append(common, value)
+		}
+	}
+	return common
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,505 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("invalid or expired signature")
+	ErrInvalidKey       = errors.New("invalid object key")
+)
+
+// LocalStorageService keeps objects on disk and signs URLs that the API serves itself,
+// so the whole upload flow works offline. It is meant for development, not production.
+//
+// Layout under root: objects/<key>, meta/<key>.json and uploads/<uploadID>/ for multipart parts.
+type LocalStorageService struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// SignedRequest is what a verified URL allows its holder to do
+type SignedRequest struct {
+	ContentType   string
+	ContentLength int64
+	UploadID      string
+	PartNumber    int32
+}
+
+type localObjectMeta struct {
+	ContentType string `json:"content_type"`
+}
+
+type localUploadMeta struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Initiated   time.Time `json:"initiated"`
+}
+
+// NewLocalStorageService stores objects under root; baseURL is where the storage routes are mounted
+func NewLocalStorageService(root string, baseURL string, secret string) (*LocalStorageService, error) {
+	if root == "" || baseURL == "" || secret == "" {
+		return nil, fmt.Errorf("missing required local storage configuration")
+	}
+
+	for _, dir := range []string{"objects", "meta", "uploads"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	return &LocalStorageService{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		secret:  []byte(secret),
+	}, nil
+}
+
+// PublicURL is the prefix that, followed by a key, serves the object without a signature
+func (s *LocalStorageService) PublicURL() string {
+	return s.baseURL
+}
+
+func (s *LocalStorageService) GeneratePresignedURL(ctx context.Context, key string, contentType string, contentLength int64, expires time.Duration) (string, error) {
+	return s.signURL("PUT", key, expires, SignedRequest{ContentType: contentType, ContentLength: contentLength})
+}
+
+func (s *LocalStorageService) GeneratePresignedGetURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.signURL("GET", key, expires, SignedRequest{})
+}
+
+func (s *LocalStorageService) GeneratePresignedPartURL(ctx context.Context, key string, uploadID string, partNumber int32, contentLength int64, expires time.Duration) (string, error) {
+	return s.signURL("PUT", key, expires, SignedRequest{ContentLength: contentLength, UploadID: uploadID, PartNumber: partNumber})
+}
+
+// VerifySignedRequest checks a URL produced by one of the Generate methods against the request it arrived on
+func (s *LocalStorageService) VerifySignedRequest(method string, key string, query url.Values) (SignedRequest, error) {
+	expires, err := strconv.ParseInt(query.Get("X-Expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return SignedRequest{}, ErrSignatureInvalid
+	}
+
+	req := SignedRequest{
+		ContentType: query.Get("X-Content-Type"),
+		UploadID:    query.Get("X-Upload-Id"),
+	}
+	if value := query.Get("X-Content-Length"); value != "" {
+		if req.ContentLength, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return SignedRequest{}, ErrSignatureInvalid
+		}
+	}
+	if value := query.Get("X-Part-Number"); value != "" {
+		partNumber, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return SignedRequest{}, ErrSignatureInvalid
+		}
+		req.PartNumber = int32(partNumber)
+	}
+
+	expected := s.signature(method, key, expires, req)
+	signature, err := hex.DecodeString(query.Get("X-Signature"))
+	if err != nil || !hmac.Equal(signature, expected) {
+		return SignedRequest{}, ErrSignatureInvalid
+	}
+
+	return req, nil
+}
+
+func (s *LocalStorageService) UploadObject(ctx context.Context, key string, data []byte, contentType string) error {
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	return s.WriteObject(key, contentType, bytes.NewReader(data), int64(len(data)))
+}
+
+// WriteObject stores exactly size bytes from body under key, replacing any existing object
+func (s *LocalStorageService) WriteObject(key string, contentType string, body io.Reader, size int64) error {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if err := writeFileExactly(objectPath, body, size); err != nil {
+		return err
+	}
+	return s.writeMeta(key, localObjectMeta{ContentType: contentType})
+}
+
+// OpenObject opens an object for serving; the caller closes the file
+func (s *LocalStorageService) OpenObject(key string) (*os.File, ObjectInfo, error) {
+	info, err := s.GetObjectInfo(context.Background(), key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	objectPath, _ := s.objectPath(key)
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("failed to open object: %w", err)
+	}
+	return file, info, nil
+}
+
+func (s *LocalStorageService) DownloadObject(ctx context.Context, key string, maxBytes int64) ([]byte, string, error) {
+	file, info, err := s.OpenObject(key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	if maxBytes > 0 && info.Size > maxBytes {
+		return nil, "", fmt.Errorf("object exceeds %d bytes", maxBytes)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, info.ContentType, nil
+}
+
+func (s *LocalStorageService) DeleteObject(ctx context.Context, key string) error {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	if err := os.Remove(s.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object metadata: %w", err)
+	}
+	return nil
+}
+
+// ListObjects pages through keys in lexical order; the token is the last key of the previous page
+func (s *LocalStorageService) ListObjects(ctx context.Context, prefix string, continuationToken string, maxKeys int32) (ObjectPage, error) {
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	objectsDir := filepath.Join(s.root, "objects")
+	var keys []string
+	err := filepath.WalkDir(objectsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return err
+		}
+		rel, err := filepath.Rel(objectsDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) && key > continuationToken {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return ObjectPage{}, fmt.Errorf("failed to list objects: %w", err)
+	}
+	sort.Strings(keys)
+
+	page := ObjectPage{Objects: make([]ObjectInfo, 0, len(keys))}
+	for _, key := range keys {
+		if int32(len(page.Objects)) == maxKeys {
+			page.NextToken = page.Objects[len(page.Objects)-1].Key
+			break
+		}
+		info, err := s.GetObjectInfo(ctx, key)
+		if err != nil {
+			return ObjectPage{}, err
+		}
+		page.Objects = append(page.Objects, info)
+	}
+
+	return page, nil
+}
+
+func (s *LocalStorageService) GetObjectInfo(ctx context.Context, key string) (ObjectInfo, error) {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	stat, err := os.Stat(objectPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to get object info: %w", err)
+	}
+
+	meta := localObjectMeta{ContentType: "application/octet-stream"}
+	if data, err := os.ReadFile(s.metaPath(key)); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		ContentType:  meta.ContentType,
+		Size:         stat.Size(),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorageService) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.NewString()
+	dir := filepath.Join(s.root, "uploads", uploadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	data, err := json.Marshal(localUploadMeta{Key: key, ContentType: contentType, Initiated: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "upload.json"), data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	return uploadID, nil
+}
+
+// WritePart stores one part of an open multipart upload
+func (s *LocalStorageService) WritePart(key string, uploadID string, partNumber int32, body io.Reader, size int64) error {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return err
+	}
+	return writeFileExactly(s.partPath(uploadID, partNumber), body, size)
+}
+
+// ListUploadedParts reports parts with an ETag derived from size and write time, which is enough
+// for CompleteMultipartUpload to detect a part rewritten after it was listed
+func (s *LocalStorageService) ListUploadedParts(ctx context.Context, key string, uploadID string) ([]UploadedPart, error) {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.root, "uploads", uploadID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+	}
+
+	var parts []UploadedPart
+	for _, entry := range entries {
+		partNumber, err := strconv.ParseInt(strings.TrimPrefix(entry.Name(), "part-"), 10, 32)
+		if err != nil || !strings.HasPrefix(entry.Name(), "part-") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+		parts = append(parts, UploadedPart{
+			PartNumber: int32(partNumber),
+			ETag:       localPartETag(info),
+			Size:       info.Size(),
+		})
+	}
+
+	return parts, nil
+}
+
+func (s *LocalStorageService) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []UploadedPart) error {
+	upload, err := s.loadUpload(key, uploadID)
+	if err != nil {
+		return err
+	}
+
+	readers := make([]io.Reader, 0, len(parts))
+	var size int64
+	for _, part := range parts {
+		file, err := os.Open(s.partPath(uploadID, part.PartNumber))
+		if err != nil {
+			return fmt.Errorf("failed to complete multipart upload: part %d: %w", part.PartNumber, err)
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || localPartETag(info) != part.ETag {
+			return fmt.Errorf("failed to complete multipart upload: part %d changed", part.PartNumber)
+		}
+		readers = append(readers, file)
+		size += info.Size()
+	}
+
+	if err := s.WriteObject(key, upload.ContentType, io.MultiReader(readers...), size); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(s.root, "uploads", uploadID))
+}
+
+func (s *LocalStorageService) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(s.root, "uploads", uploadID)); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorageService) ListIncompleteUploads(ctx context.Context, startedBefore time.Time) ([]IncompleteUpload, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, "uploads"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list multipart uploads: %w", err)
+	}
+
+	var uploads []IncompleteUpload
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(s.root, "uploads", entry.Name(), "upload.json"))
+		if err != nil {
+			continue
+		}
+		var meta localUploadMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			continue
+		}
+		if meta.Initiated.Before(startedBefore) {
+			uploads = append(uploads, IncompleteUpload{Key: meta.Key, UploadID: entry.Name(), Initiated: meta.Initiated})
+		}
+	}
+
+	return uploads, nil
+}
+
+func (s *LocalStorageService) signURL(method string, key string, expires time.Duration, req SignedRequest) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+
+	if expires <= 0 {
+		expires = 1 * time.Hour
+	}
+	expiresAt := time.Now().Add(expires).Unix()
+
+	query := url.Values{}
+	query.Set("X-Expires", strconv.FormatInt(expiresAt, 10))
+	if req.ContentType != "" {
+		query.Set("X-Content-Type", req.ContentType)
+	}
+	if req.ContentLength > 0 {
+		query.Set("X-Content-Length", strconv.FormatInt(req.ContentLength, 10))
+	}
+	if req.UploadID != "" {
+		query.Set("X-Upload-Id", req.UploadID)
+		query.Set("X-Part-Number", strconv.FormatInt(int64(req.PartNumber), 10))
+	}
+	query.Set("X-Signature", hex.EncodeToString(s.signature(method, key, expiresAt, req)))
+
+	return s.baseURL + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorageService) signature(method string, key string, expiresAt int64, req SignedRequest) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n%d\n%s\n%d", method, key, expiresAt, req.ContentType, req.ContentLength, req.UploadID, req.PartNumber)
+	return mac.Sum(nil)
+}
+
+func (s *LocalStorageService) objectPath(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return filepath.Join(s.root, "objects", filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorageService) metaPath(key string) string {
+	return filepath.Join(s.root, "meta", filepath.FromSlash(key)+".json")
+}
+
+func (s *LocalStorageService) partPath(uploadID string, partNumber int32) string {
+	return filepath.Join(s.root, "uploads", uploadID, fmt.Sprintf("part-%05d", partNumber))
+}
+
+func (s *LocalStorageService) writeMeta(key string, meta localObjectMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	metaPath := s.metaPath(key)
+	if err := os.MkdirAll(filepath.Dir(metaPath), 0o755); err != nil {
+		return fmt.Errorf("failed to write object metadata: %w", err)
+	}
+	return os.WriteFile(metaPath, data, 0o644)
+}
+
+func (s *LocalStorageService) loadUpload(key string, uploadID string) (localUploadMeta, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return localUploadMeta{}, fmt.Errorf("%w: upload %s", ErrObjectNotFound, uploadID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.root, "uploads", uploadID, "upload.json"))
+	if err != nil {
+		return localUploadMeta{}, fmt.Errorf("%w: upload %s", ErrObjectNotFound, uploadID)
+	}
+	var meta localUploadMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Key != key {
+		return localUploadMeta{}, fmt.Errorf("%w: upload %s", ErrObjectNotFound, uploadID)
+	}
+	return meta, nil
+}
+
+// writeFileExactly writes through a temp file so readers never see a partial object,
+// and fails unless body holds exactly size bytes
+func writeFileExactly(target string, body io.Reader, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(body, size+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if written != size {
+		return fmt.Errorf("body is %d bytes, expected %d", written, size)
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+func localPartETag(info fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.Size(), info.ModTime().UnixNano())
+}
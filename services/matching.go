@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+	"myapp/validate"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrRequirementInvalid    = errors.New("invalid requirement")
+	ErrRequirementMissing    = errors.New("no structured requirement is set")
+	ErrRequirementNotVisible = errors.New("you do not have access to this requirement")
+)
+
+// How much each part of a requirement counts towards a match score. Only the parts a
+// requirement sets are counted, so scores stay comparable between vague and precise requirements.
+const (
+	budgetWeight       = 35
+	locationWeight     = 25
+	bedroomsWeight     = 20
+	propertyTypeWeight = 15
+	listingTypeWeight  = 5
+	furnishingWeight   = 5
+)
+
+const (
+	// Listings priced up to this fraction over budget are still offered, with a lower score
+	overBudgetTolerance = 0.10
+	// Candidates scored per request; the best of them are returned
+	matchCandidateLimit = 500
+	defaultMatchLimit   = 20
+	// How long dealer locations are reused before they are read again
+	dealerCacheTTL = 5 * time.Minute
+)
+
+type MatchingService struct {
+	propertyRepo     repositories.PropertyRepository
+	dealerRepo       repositories.DealerRepository
+	leadRepo         repositories.LeadRepository
+	inquiryRepo      repositories.InquiryRepository
+	dealerClientRepo repositories.DealerClientRepository
+
+	dealersMu       sync.Mutex
+	dealers         map[string]models.Dealer
+	dealersLoadedAt time.Time
+}
+
+func NewMatchingService(propertyRepo repositories.PropertyRepository, dealerRepo repositories.DealerRepository, leadRepo repositories.LeadRepository, inquiryRepo repositories.InquiryRepository, dealerClientRepo repositories.DealerClientRepository) *MatchingService {
+	return &MatchingService{
+		propertyRepo:     propertyRepo,
+		dealerRepo:       dealerRepo,
+		leadRepo:         leadRepo,
+		inquiryRepo:      inquiryRepo,
+		dealerClientRepo: dealerClientRepo,
+	}
+}
+
+// NormalizeRequirementCriteria trims and validates criteria. Criteria with nothing set come
+// back nil so the record is stored without a structured requirement.
+func NormalizeRequirementCriteria(criteria *models.RequirementCriteria) (*models.RequirementCriteria, error) {
+	if criteria == nil {
+		return nil, nil
+	}
+
+	normalized := *criteria
+	normalized.ListingType = strings.ToLower(strings.TrimSpace(normalized.ListingType))
+	normalized.Furnishing = strings.ToLower(strings.TrimSpace(normalized.Furnishing))
+	normalized.PropertyTypes = cleanList(normalized.PropertyTypes, true)
+	normalized.Locations = cleanList(normalized.Locations, false)
+	normalized.SubLocations = cleanList(normalized.SubLocations, false)
+
+	if err := validate.ValidateRequirementCriteria(normalized); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequirementInvalid, err)
+	}
+	if normalized.IsEmpty() {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+// MatchesForLead ranks listings for a lead. Dealers only see leads assigned to them or interested
+// in one of their listings, and only their own listings are offered to them.
+func (s *MatchingService) MatchesForLead(ctx context.Context, leadID string, userID string, role string, limit int) ([]models.PropertyMatch, error) {
+	lead, err := s.leadRepo.GetByID(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeDealerID := ""
+	if role == constants.Dealer {
+		visible, err := dealerCanSeeLead(ctx, s.leadRepo, lead, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, ErrRequirementNotVisible
+		}
+		scopeDealerID = userID
+	}
+
+	if lead.Criteria == nil {
+		return nil, ErrRequirementMissing
+	}
+	return s.MatchProperties(ctx, *lead.Criteria, scopeDealerID, limit)
+}
+
+// MatchesForDealerClient ranks the client's dealer's listings for the client
+func (s *MatchingService) MatchesForDealerClient(ctx context.Context, dealerClientID string, userID string, role string, limit int) ([]models.PropertyMatch, error) {
+	client, err := s.dealerClientRepo.GetByID(ctx, dealerClientID)
+	if err != nil {
+		return nil, err
+	}
+	if role == constants.Dealer && client.DealerID != userID {
+		return nil, ErrDealerClientNotOwned
+	}
+
+	if client.Criteria == nil {
+		return nil, ErrRequirementMissing
+	}
+	return s.MatchProperties(ctx, *client.Criteria, client.DealerID, limit)
+}
+
+// MatchProperties ranks unsold listings against criteria, best first. Budget, bedrooms, listing
+// type and preferred areas narrow the candidates; everything set contributes to the score.
+// A non-empty scopeDealerID limits the search to that dealer's listings.
+func (s *MatchingService) MatchProperties(ctx context.Context, criteria models.RequirementCriteria, scopeDealerID string, limit int) ([]models.PropertyMatch, error) {
+	if criteria.IsEmpty() {
+		return nil, ErrRequirementMissing
+	}
+
+	dealers, err := s.loadDealers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"sold":       bson.M{"$ne": true},
+		"is_deleted": bson.M{"$ne": true},
+	}
+	if criteria.MaxBudget > 0 {
+		filter["min_price"] = bson.M{"$lte": int64(float64(criteria.MaxBudget) * (1 + overBudgetTolerance))}
+	}
+	bedrooms := bson.M{}
+	if criteria.MinBedrooms > 0 {
+		bedrooms["$gte"] = criteria.MinBedrooms - 1
+	}
+	if criteria.MaxBedrooms > 0 {
+		bedrooms["$lte"] = criteria.MaxBedrooms + 1
+	}
+	if len(bedrooms) > 0 {
+		filter["bedrooms"] = bedrooms
+	}
+	if criteria.ListingType != "" {
+		// Listings that do not say whether they are for sale or rent stay in
+		var others []string
+		for _, listingType := range constants.ListingTypes {
+			if listingType != criteria.ListingType {
+				others = append(others, listingType)
+			}
+		}
+		filter["listing_type"] = bson.M{"$nin": others}
+	}
+
+	var dealerIDs []primitive.ObjectID
+	for id, dealer := range dealers {
+		if scopeDealerID != "" && id != scopeDealerID {
+			continue
+		}
+		if (len(criteria.Locations) > 0 || len(criteria.SubLocations) > 0) && locationScore(criteria, dealer) == 0 {
+			continue
+		}
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			dealerIDs = append(dealerIDs, objectID)
+		}
+	}
+	if len(dealerIDs) == 0 {
+		return []models.PropertyMatch{}, nil
+	}
+	filter["dealer_id"] = bson.M{"$in": dealerIDs}
+
+	// Past the cap the newest listings are the ones scored
+	candidates, err := s.propertyRepo.GetFilteredProperties(ctx, filter, nil, matchCandidateLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]models.PropertyMatch, 0, len(candidates))
+	for _, property := range candidates {
+		score, reasons, ok := scoreMatch(criteria, property, dealers[property.DealerID])
+		if !ok {
+			continue
+		}
+		matches = append(matches, models.PropertyMatch{Property: property, Score: score, Reasons: reasons})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Property.CreatedAt.After(matches[j].Property.CreatedAt)
+	})
+
+	limit = matchLimit(limit)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// MatchingRequirements ranks the requirements a listing meets, best first. Admins see leads and
+// inquiries; the listing's dealer sees their own clients and the inquiries sent to them.
+func (s *MatchingService) MatchingRequirements(ctx context.Context, propertyID string, userID string, role string, limit int) ([]models.RequirementMatch, error) {
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if role == constants.Dealer && property.DealerID != userID {
+		return nil, ErrPropertyNotOwned
+	}
+	dealer, err := s.dealerRepo.GetByID(ctx, property.DealerID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	filter := requirementCandidateFilter(property)
+	var matches []models.RequirementMatch
+	add := func(source, id, name, phone string, criteria *models.RequirementCriteria) {
+		if criteria == nil {
+			return
+		}
+		score, reasons, ok := scoreMatch(*criteria, property, dealer)
+		if !ok {
+			return
+		}
+		matches = append(matches, models.RequirementMatch{
+			Source:   source,
+			ID:       id,
+			Name:     name,
+			Phone:    phone,
+			Criteria: *criteria,
+			Score:    score,
+			Reasons:  reasons,
+		})
+	}
+
+	if role == constants.Dealer {
+		dealerObjectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		clients, err := s.dealerClientRepo.GetFilteredDealerClients(ctx, withDealer(filter, dealerObjectID), matchCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, client := range clients {
+			add(models.RequirementSourceDealerClient, client.ID, client.Name, client.Phone, client.Criteria)
+		}
+
+		inquiries, err := s.inquiryRepo.GetFilteredInquiries(ctx, withDealer(filter, dealerObjectID), matchCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, inquiry := range inquiries {
+			add(models.RequirementSourceInquiry, inquiry.ID, inquiry.Name, inquiry.Phone, inquiry.Criteria)
+		}
+	} else {
+		leads, err := s.leadRepo.GetFilteredLeads(ctx, filter, matchCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, lead := range leads {
+			add(models.RequirementSourceLead, lead.ID, lead.Name, lead.Phone, lead.Criteria)
+		}
+
+		inquiries, err := s.inquiryRepo.GetFilteredInquiries(ctx, filter, matchCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, inquiry := range inquiries {
+			add(models.RequirementSourceInquiry, inquiry.ID, inquiry.Name, inquiry.Phone, inquiry.Criteria)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+
+	limit = matchLimit(limit)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []models.RequirementMatch{}
+	}
+	return matches, nil
+}
+
+// loadDealers maps dealer IDs to dealers, whose location is where their listings are. The map
+// is cached for dealerCacheTTL and must not be modified by callers.
+func (s *MatchingService) loadDealers(ctx context.Context) (map[string]models.Dealer, error) {
+	s.dealersMu.Lock()
+	cached, loadedAt := s.dealers, s.dealersLoadedAt
+	s.dealersMu.Unlock()
+	if cached != nil && time.Since(loadedAt) < dealerCacheTTL {
+		return cached, nil
+	}
+
+	dealers, err := s.dealerRepo.GetAll(ctx)
+	if err != nil {
+		if cached != nil {
+			log.Printf("⚠️  Failed to refresh dealers for matching, using cached list: %v", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	byID := make(map[string]models.Dealer, len(dealers))
+	for _, dealer := range dealers {
+		byID[dealer.ID] = dealer
+	}
+
+	s.dealersMu.Lock()
+	s.dealers, s.dealersLoadedAt = byID, time.Now()
+	s.dealersMu.Unlock()
+	return byID, nil
+}
+
+// requirementCandidateFilter narrows stored requirements to those the listing could meet,
+// mirroring the hard limits scoreMatch applies
+func requirementCandidateFilter(property models.Property) bson.M {
+	unset := func(field string) bson.M { return bson.M{field: bson.M{"$exists": false}} }
+
+	conditions := []bson.M{
+		{"$or": []bson.M{
+			unset("criteria.max_budget"),
+			{"criteria.max_budget": bson.M{"$gte": int64(float64(property.MinPrice) / (1 + overBudgetTolerance))}},
+		}},
+		{"$or": []bson.M{
+			unset("criteria.min_bedrooms"),
+			{"criteria.min_bedrooms": bson.M{"$lte": property.Bedrooms + 1}},
+		}},
+		{"$or": []bson.M{
+			unset("criteria.max_bedrooms"),
+			{"criteria.max_bedrooms": bson.M{"$gte": property.Bedrooms - 1}},
+		}},
+	}
+	if property.ListingType != "" {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			unset("criteria.listing_type"),
+			{"criteria.listing_type": property.ListingType},
+		}})
+	}
+
+	return bson.M{
+		"criteria": bson.M{"$exists": true, "$ne": nil},
+		"$and":     conditions,
+	}
+}
+
+func withDealer(filter bson.M, dealerID primitive.ObjectID) bson.M {
+	scoped := bson.M{"dealer_id": dealerID}
+	for key, value := range filter {
+		scoped[key] = value
+	}
+	return scoped
+}
+
+// scoreMatch scores how well a listing meets criteria from 0 to 100 and explains why. ok is
+// false when the listing is ruled out: the wrong listing type, well over budget, more than one
+// bedroom off, or outside every preferred area.
+func scoreMatch(criteria models.RequirementCriteria, property models.Property, dealer models.Dealer) (score int, reasons []string, ok bool) {
+	earned, possible := 0, 0
+
+	if criteria.ListingType != "" {
+		possible += listingTypeWeight
+		switch property.ListingType {
+		case criteria.ListingType:
+			earned += listingTypeWeight
+			reasons = append(reasons, "For "+property.ListingType)
+		case "":
+		default:
+			return 0, nil, false
+		}
+	}
+
+	if criteria.MinBudget > 0 || criteria.MaxBudget > 0 {
+		possible += budgetWeight
+		low, high := property.MinPrice, property.MaxPrice
+		if high < low {
+			high = low
+		}
+		price := utils.FormatIndianPriceRange(low, high)
+		switch {
+		case criteria.MaxBudget > 0 && low > criteria.MaxBudget:
+			over := float64(low-criteria.MaxBudget) / float64(criteria.MaxBudget)
+			if over > overBudgetTolerance {
+				return 0, nil, false
+			}
+			earned += budgetWeight / 3
+			reasons = append(reasons, fmt.Sprintf("%s, %.0f%% over budget", price, over*100))
+		case high < criteria.MinBudget:
+			earned += budgetWeight * 2 / 3
+			reasons = append(reasons, price+", below budget")
+		default:
+			earned += budgetWeight
+			reasons = append(reasons, price+", within budget")
+		}
+	}
+
+	if criteria.MinBedrooms > 0 || criteria.MaxBedrooms > 0 {
+		possible += bedroomsWeight
+		off := 0
+		if criteria.MinBedrooms > 0 && property.Bedrooms < criteria.MinBedrooms {
+			off = criteria.MinBedrooms - property.Bedrooms
+		}
+		if criteria.MaxBedrooms > 0 && property.Bedrooms > criteria.MaxBedrooms {
+			off = property.Bedrooms - criteria.MaxBedrooms
+		}
+		switch off {
+		case 0:
+			earned += bedroomsWeight
+			reasons = append(reasons, fmt.Sprintf("%d BHK", property.Bedrooms))
+		case 1:
+			earned += bedroomsWeight / 2
+			reasons = append(reasons, fmt.Sprintf("%d BHK, one bedroom off", property.Bedrooms))
+		default:
+			return 0, nil, false
+		}
+	}
+
+	if len(criteria.Locations) > 0 || len(criteria.SubLocations) > 0 {
+		possible += locationWeight
+		points := locationScore(criteria, dealer)
+		if points == 0 {
+			return 0, nil, false
+		}
+		earned += points
+		if points == locationWeight {
+			reasons = append(reasons, "In "+dealer.SubLocation)
+		} else {
+			reasons = append(reasons, "In the "+dealer.Location+" area")
+		}
+	}
+
+	if len(criteria.PropertyTypes) > 0 {
+		possible += propertyTypeWeight
+		for _, propertyType := range criteria.PropertyTypes {
+			if strings.EqualFold(propertyType, property.PropertyType) {
+				earned += propertyTypeWeight
+				reasons = append(reasons, humanize(property.PropertyType))
+				break
+			}
+		}
+	}
+
+	if criteria.Furnishing != "" {
+		possible += furnishingWeight
+		if property.Furnishing == criteria.Furnishing {
+			earned += furnishingWeight
+			reasons = append(reasons, humanize(property.Furnishing))
+		}
+	}
+
+	if possible == 0 {
+		return 0, nil, false
+	}
+	return earned * 100 / possible, reasons, true
+}
+
+// locationScore gives full marks for a preferred sub-location, partial marks for a preferred
+// location and nothing otherwise
+func locationScore(criteria models.RequirementCriteria, dealer models.Dealer) int {
+	for _, subLocation := range criteria.SubLocations {
+		if dealer.SubLocation != "" && strings.EqualFold(subLocation, dealer.SubLocation) {
+			return locationWeight
+		}
+	}
+	for _, location := range criteria.Locations {
+		if dealer.Location != "" && strings.EqualFold(location, dealer.Location) {
+			return locationWeight * 3 / 5
+		}
+	}
+	return 0
+}
+
+func matchLimit(limit int) int {
+	if limit < 1 || limit > 100 {
+		return defaultMatchLimit
+	}
+	return limit
+}
+
+// cleanList trims entries and drops blanks and duplicates, optionally lower-casing them
+func cleanList(values []string, lower bool) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		cleaned = append(cleaned, value)
+	}
+	return cleaned
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// scopedLeadRepo holds one lead that is interested only in the listings of interestedDealer.
+// GetLeads applies the filter the way Mongo would, so a query that drops the dealer scope
+// returns the lead to anyone.
+type scopedLeadRepo struct {
+	repositories.LeadRepository
+	lead             models.Lead
+	interestedDealer string
+}
+
+func (r *scopedLeadRepo) GetByID(ctx context.Context, id string) (models.Lead, error) {
+	return r.lead, nil
+}
+
+func (r *scopedLeadRepo) GetLeads(ctx context.Context, params models.LeadQueryParams) ([]models.Lead, error) {
+	params.SetDefaults()
+	filter := utils.BuildMongoFilter(params)
+	if properties, ok := filter["properties"].(bson.M); ok {
+		elemMatch, _ := properties["$elemMatch"].(bson.M)
+		interested, _ := primitive.ObjectIDFromHex(r.interestedDealer)
+		if dealerID, ok := elemMatch["dealer_id"]; ok && dealerID != interested {
+			return nil, nil
+		}
+	}
+	return []models.Lead{r.lead}, nil
+}
+
+func TestMatchesForLeadHidesOtherDealersLeads(t *testing.T) {
+	const owner, other = "64b000000000000000000001", "64b000000000000000000002"
+	repo := &scopedLeadRepo{
+		// No criteria: a leaked lead reports ErrRequirementMissing instead of reaching the property search
+		lead:             models.Lead{ID: "64b0000000000000000000aa"},
+		interestedDealer: owner,
+	}
+	service := NewMatchingService(nil, nil, repo, nil, nil)
+
+	_, err := service.MatchesForLead(context.Background(), repo.lead.ID, other, constants.Dealer, 10)
+	if !errors.Is(err, ErrRequirementNotVisible) {
+		t.Fatalf("dealer without an interest on the lead: got %v, want %v", err, ErrRequirementNotVisible)
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"strconv"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMediaNotFound      = errors.New("uploaded file not found")
+	ErrMediaInvalid       = errors.New("uploaded file is not allowed")
+	ErrMediaNotOwned      = errors.New("uploaded file belongs to another user")
+	ErrMediaNotConfirmed  = errors.New("uploaded file has not been confirmed")
+	ErrMediaQuotaExceeded = errors.New("upload quota exceeded")
+)
+
+const (
+	uploadURLExpiry   = 15 * time.Minute
+	maxFilesPerUpload = 20
+	// How long after its URL expires an unconfirmed single-PUT reservation keeps its quota
+	pendingUploadGrace = time.Hour
+)
+
+// UploadFile is a file the client declares before uploading it
+type UploadFile struct {
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// PreparedUpload is a signed URL for one file; the PUT must send exactly the listed headers
+type PreparedUpload struct {
+	PresignedURL string            `json:"presignedUrl"`
+	FileKey      string            `json:"fileKey"`
+	Headers      map[string]string `json:"headers"`
+}
+
+type MediaService struct {
+	Repo       repositories.MediaRepository
+	Storage    ObjectStorage
+	References repositories.MediaReferenceRepository
+}
+
+func NewMediaService(repo repositories.MediaRepository, storage ObjectStorage) *MediaService {
+	return &MediaService{
+		Repo:    repo,
+		Storage: storage,
+	}
+}
+
+// PrepareUploads reserves a key per file under the purpose's prefix and signs an upload URL for it.
+// Every file must pass the purpose's type and size rules, and the batch must fit the owner's quota.
+func (s *MediaService) PrepareUploads(ctx context.Context, ownerID string, purpose string, files []UploadFile) ([]PreparedUpload, error) {
+	if len(files) == 0 || len(files) > maxFilesPerUpload {
+		return nil, fmt.Errorf("%w: between 1 and %d files can be uploaded at once", ErrMediaInvalid, maxFilesPerUpload)
+	}
+	rule, batchBytes, err := s.reserveUploads(ctx, ownerID, purpose, files)
+	if err != nil {
+		return nil, err
+	}
+	uploads, err := s.prepareUploads(ctx, rule, ownerID, purpose, files)
+	if err != nil {
+		s.releaseUploads(ctx, ownerID, purpose, int64(len(files)), batchBytes)
+		return nil, err
+	}
+	return uploads, nil
+}
+
+func (s *MediaService) prepareUploads(ctx context.Context, rule constants.MediaRule, ownerID string, purpose string, files []UploadFile) ([]PreparedUpload, error) {
+
+	uploads := make([]PreparedUpload, len(files))
+	records := make([]models.Media, len(files))
+	for i, file := range files {
+		key := newMediaKey(rule, ownerID, file.ContentType)
+
+		url, err := s.Storage.GeneratePresignedURL(ctx, key, file.ContentType, file.Size, uploadURLExpiry)
+		if err != nil {
+			return nil, err
+		}
+
+		uploads[i] = PreparedUpload{
+			PresignedURL: url,
+			FileKey:      key,
+			Headers: map[string]string{
+				"Content-Type":   file.ContentType,
+				"Content-Length": strconv.FormatInt(file.Size, 10),
+			},
+		}
+		records[i] = models.Media{
+			Key:         key,
+			OwnerID:     ownerID,
+			Purpose:     purpose,
+			ContentType: file.ContentType,
+			Size:        file.Size,
+			Status:      models.MediaStatusPending,
+		}
+	}
+
+	if err := s.Repo.Create(ctx, records); err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// reserveUploads applies the purpose's type and size rules to each file and takes the batch out of
+// the owner's quota in one atomic step. Callers release the reservation if the upload is not recorded.
+func (s *MediaService) reserveUploads(ctx context.Context, ownerID string, purpose string, files []UploadFile) (constants.MediaRule, int64, error) {
+	rule, ok := constants.MediaRules[purpose]
+	if !ok {
+		return rule, 0, fmt.Errorf("%w: unknown purpose %q", ErrMediaInvalid, purpose)
+	}
+
+	var batchBytes int64
+	for i, file := range files {
+		if !constants.IsAllowedMediaType(purpose, file.ContentType) {
+			return rule, 0, fmt.Errorf("%w: file %d content type %q is not accepted for %s", ErrMediaInvalid, i+1, file.ContentType, purpose)
+		}
+		if file.Size <= 0 || file.Size > rule.MaxBytes {
+			return rule, 0, fmt.Errorf("%w: file %d size must be between 1 and %d bytes for %s", ErrMediaInvalid, i+1, rule.MaxBytes, purpose)
+		}
+		batchBytes += file.Size
+	}
+
+	quotaErr := fmt.Errorf("%w: %s is limited to %d files and %d bytes in total", ErrMediaQuotaExceeded, purpose, rule.MaxFiles, rule.MaxTotalBytes)
+	if int64(len(files)) > rule.MaxFiles || batchBytes > rule.MaxTotalBytes {
+		return rule, 0, quotaErr
+	}
+	reserved, err := s.Repo.Reserve(ctx, ownerID, purpose, int64(len(files)), batchBytes, rule.MaxFiles, rule.MaxTotalBytes)
+	if err != nil {
+		return rule, 0, err
+	}
+	if !reserved {
+		return rule, 0, quotaErr
+	}
+
+	return rule, batchBytes, nil
+}
+
+func (s *MediaService) releaseUploads(ctx context.Context, ownerID string, purpose string, files int64, bytes int64) {
+	if err := s.Repo.Release(ctx, ownerID, purpose, files, bytes); err != nil {
+		log.Printf("⚠️  Failed to release upload quota for %s (%s): %v", ownerID, purpose, err)
+	}
+}
+
+// ConfirmUpload checks an uploaded object against the reservation made for it and marks it confirmed
+func (s *MediaService) ConfirmUpload(ctx context.Context, ownerID string, key string, purpose string) (models.Media, error) {
+	if !constants.IsValidMediaPurpose(purpose) {
+		return models.Media{}, fmt.Errorf("%w: unknown purpose %q", ErrMediaInvalid, purpose)
+	}
+
+	media, err := s.Repo.GetByKey(ctx, key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Media{}, fmt.Errorf("%w: no upload was prepared for %s", ErrMediaNotFound, key)
+		}
+		return models.Media{}, err
+	}
+	if media.OwnerID != ownerID {
+		return models.Media{}, ErrMediaNotOwned
+	}
+	if media.Purpose != purpose {
+		return models.Media{}, fmt.Errorf("%w: %s was prepared for %s", ErrMediaInvalid, key, media.Purpose)
+	}
+	if media.Status == models.MediaStatusConfirmed {
+		return media, nil
+	}
+
+	info, err := s.Storage.GetObjectInfo(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return models.Media{}, fmt.Errorf("%w: %s has not been uploaded", ErrMediaNotFound, key)
+		}
+		return models.Media{}, err
+	}
+
+	contentType, _, err := mime.ParseMediaType(info.ContentType)
+	if err != nil || contentType != media.ContentType {
+		return models.Media{}, fmt.Errorf("%w: content type %q does not match the declared %q", ErrMediaInvalid, info.ContentType, media.ContentType)
+	}
+	size := info.Size
+	if size != media.Size {
+		return models.Media{}, fmt.Errorf("%w: size %d bytes does not match the declared %d", ErrMediaInvalid, size, media.Size)
+	}
+
+	if err := s.Repo.Confirm(ctx, key, contentType, size); err != nil {
+		return models.Media{}, err
+	}
+	media.Status = models.MediaStatusConfirmed
+
+	return media, nil
+}
+
+// VerifyOwnedMedia ensures every key was confirmed by ownerID for one of the given purposes
+func (s *MediaService) VerifyOwnedMedia(ctx context.Context, ownerID string, keys []string, purposes ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	records, err := s.Repo.GetByKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string]models.Media, len(records))
+	for _, media := range records {
+		byKey[media.Key] = media
+	}
+
+	for _, key := range keys {
+		media, ok := byKey[key]
+		if !ok || media.Status != models.MediaStatusConfirmed {
+			return fmt.Errorf("%w: %s", ErrMediaNotConfirmed, key)
+		}
+		if media.OwnerID != ownerID {
+			return fmt.Errorf("%w: %s", ErrMediaNotOwned, key)
+		}
+		if len(purposes) > 0 && !utils.Contains(purposes, media.Purpose) {
+			return fmt.Errorf("%w: %s was uploaded as %s", ErrMediaInvalid, key, media.Purpose)
+		}
+	}
+
+	return nil
+}
+
+func newMediaKey(rule constants.MediaRule, ownerID string, contentType string) string {
+	return fmt.Sprintf("%s/%s/%s%s", rule.KeyPrefix, ownerID, uuid.NewString(), constants.MediaExtensions[contentType])
+}
+
+// IsMediaError reports whether err came from media verification rather than infrastructure
+func IsMediaError(err error) bool {
+	return errors.Is(err, ErrMediaNotFound) || errors.Is(err, ErrMediaInvalid) ||
+		errors.Is(err, ErrMediaNotOwned) || errors.Is(err, ErrMediaNotConfirmed) ||
+		errors.Is(err, ErrMediaQuotaExceeded)
+}
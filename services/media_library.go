@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+)
+
+const (
+	orphanSweepBatchSize  = 200
+	maxOrphanReportLength = 1000
+)
+
+// MediaObject is a stored object together with its upload record, if it has one
+type MediaObject struct {
+	ObjectInfo
+	Media *models.Media `json:"media,omitempty"`
+}
+
+// MediaObjectPage is one page of the admin media browser
+type MediaObjectPage struct {
+	Objects   []MediaObject `json:"objects"`
+	NextToken string        `json:"next_token,omitempty"`
+}
+
+// OrphanSweepReport describes what a sweep found and, unless it was a dry run, removed.
+// Orphans lists at most maxOrphanReportLength entries; the totals cover everything.
+type OrphanSweepReport struct {
+	DryRun      bool           `json:"dry_run"`
+	Cutoff      time.Time      `json:"cutoff"`
+	Scanned     int            `json:"scanned"`
+	OrphanCount int            `json:"orphan_count"`
+	OrphanBytes int64          `json:"orphan_bytes"`
+	Deleted     int            `json:"deleted"`
+	Failed      int            `json:"failed"`
+	Orphans     []models.Media `json:"orphans"`
+}
+
+// ListObjects pages through storage under prefix and attaches each object's upload record
+func (s *MediaService) ListObjects(ctx context.Context, prefix string, token string, limit int32) (MediaObjectPage, error) {
+	page, err := s.Storage.ListObjects(ctx, prefix, token, limit)
+	if err != nil {
+		return MediaObjectPage{}, err
+	}
+
+	keys := make([]string, len(page.Objects))
+	for i, object := range page.Objects {
+		keys[i] = object.Key
+	}
+	records, err := s.Repo.GetByKeys(ctx, keys)
+	if err != nil {
+		return MediaObjectPage{}, err
+	}
+	byKey := make(map[string]models.Media, len(records))
+	for _, media := range records {
+		byKey[media.Key] = media
+	}
+
+	result := MediaObjectPage{Objects: make([]MediaObject, len(page.Objects)), NextToken: page.NextToken}
+	for i, object := range page.Objects {
+		result.Objects[i] = MediaObject{ObjectInfo: object}
+		if media, ok := byKey[object.Key]; ok {
+			result.Objects[i].Media = &media
+		}
+	}
+
+	return result, nil
+}
+
+// GetUsage reports confirmed storage per owner and purpose; an empty ownerID covers every dealer
+func (s *MediaService) GetUsage(ctx context.Context, ownerID string) ([]models.MediaUsage, error) {
+	return s.Repo.GetUsageByOwner(ctx, ownerID)
+}
+
+// FindReferences lists the records that point at key or, for a photo, at one of its variants
+func (s *MediaService) FindReferences(ctx context.Context, key string) ([]models.MediaReference, error) {
+	if s.References == nil {
+		return nil, errors.New("media references are not configured")
+	}
+	return s.findReferences(ctx, []string{key})
+}
+
+// findReferences looks up keys together with the variant keys derived from them, reporting a
+// reference to a variant as a reference to its original
+func (s *MediaService) findReferences(ctx context.Context, keys []string) ([]models.MediaReference, error) {
+	originals := make(map[string]string, len(keys)*(len(photoVariantSpecs)+1))
+	lookup := make([]string, 0, len(keys)*(len(photoVariantSpecs)+1))
+	for _, key := range keys {
+		originals[key] = key
+		lookup = append(lookup, key)
+		for _, spec := range photoVariantSpecs {
+			variantKey := PhotoVariantKey(key, spec.Name)
+			originals[variantKey] = key
+			lookup = append(lookup, variantKey)
+		}
+	}
+
+	references, err := s.References.FindReferences(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+	for i := range references {
+		references[i].Key = originals[references[i].Key]
+	}
+	return references, nil
+}
+
+// SweepOrphans finds uploads older than olderThan that no record references and, unless dryRun,
+// deletes the object, its photo variants and the upload record
+func (s *MediaService) SweepOrphans(ctx context.Context, olderThan time.Duration, dryRun bool) (OrphanSweepReport, error) {
+	if s.References == nil {
+		return OrphanSweepReport{}, errors.New("media references are not configured")
+	}
+
+	report := OrphanSweepReport{DryRun: dryRun, Cutoff: time.Now().Add(-olderThan), Orphans: []models.Media{}}
+	afterID := ""
+	for {
+		batch, err := s.Repo.GetCreatedBefore(ctx, report.Cutoff, afterID, orphanSweepBatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+		report.Scanned += len(batch)
+
+		keys := make([]string, len(batch))
+		for i, media := range batch {
+			keys[i] = media.Key
+		}
+		references, err := s.findReferences(ctx, keys)
+		if err != nil {
+			return report, err
+		}
+		referenced := make(map[string]bool, len(references))
+		for _, reference := range references {
+			referenced[reference.Key] = true
+		}
+
+		for _, media := range batch {
+			if referenced[media.Key] {
+				continue
+			}
+			report.OrphanCount++
+			report.OrphanBytes += media.Size
+			if len(report.Orphans) < maxOrphanReportLength {
+				report.Orphans = append(report.Orphans, media)
+			}
+			if dryRun {
+				continue
+			}
+			if err := s.deleteMedia(ctx, media); err != nil {
+				log.Printf("failed to delete orphaned media %s: %v", media.Key, err)
+				report.Failed++
+				continue
+			}
+			report.Deleted++
+		}
+	}
+
+	return report, nil
+}
+
+// RunOrphanSweep calls SweepOrphans every interval until ctx is cancelled, logging each report
+func (s *MediaService) RunOrphanSweep(ctx context.Context, interval time.Duration, olderThan time.Duration, dryRun bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.SweepOrphans(ctx, olderThan, dryRun)
+			if err != nil {
+				log.Printf("orphan sweep failed: %v", err)
+				continue
+			}
+			log.Printf("orphan sweep (dry run: %t) scanned %d uploads, found %d orphans (%d bytes), deleted %d, failed %d",
+				report.DryRun, report.Scanned, report.OrphanCount, report.OrphanBytes, report.Deleted, report.Failed)
+		}
+	}
+}
+
+func (s *MediaService) deleteMedia(ctx context.Context, media models.Media) error {
+	if err := s.Storage.DeleteObject(ctx, media.Key); err != nil {
+		return err
+	}
+	if media.Purpose == constants.MediaPurposePropertyPhoto {
+		for _, spec := range photoVariantSpecs {
+			if err := s.Storage.DeleteObject(ctx, PhotoVariantKey(media.Key, spec.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.Repo.DeleteByKey(ctx, media.Key)
+}
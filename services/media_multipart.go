@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"myapp/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	multipartPartSize  = 10 << 20
+	multipartMaxParts  = 10000
+	partURLExpiry      = 1 * time.Hour
+	maxPartsPerRequest = 100
+)
+
+// MultipartUpload describes how the client must split a file: every part is PartSize bytes except the last
+type MultipartUpload struct {
+	FileKey   string `json:"fileKey"`
+	UploadID  string `json:"uploadId"`
+	PartSize  int64  `json:"partSize"`
+	PartCount int32  `json:"partCount"`
+}
+
+// PresignedPart is a signed URL for one part; the PUT must send exactly ContentLength bytes
+type PresignedPart struct {
+	PartNumber    int32  `json:"partNumber"`
+	PresignedURL  string `json:"presignedUrl"`
+	ContentLength int64  `json:"contentLength"`
+}
+
+// StartMultipartUpload reserves a key for a large file and opens a multipart upload for it in storage
+func (s *MediaService) StartMultipartUpload(ctx context.Context, ownerID string, purpose string, file UploadFile) (MultipartUpload, error) {
+	rule, _, err := s.reserveUploads(ctx, ownerID, purpose, []UploadFile{file})
+	if err != nil {
+		return MultipartUpload{}, err
+	}
+
+	key := newMediaKey(rule, ownerID, file.ContentType)
+	uploadID, err := s.Storage.CreateMultipartUpload(ctx, key, file.ContentType)
+	if err != nil {
+		s.releaseUploads(ctx, ownerID, purpose, 1, file.Size)
+		return MultipartUpload{}, err
+	}
+
+	err = s.Repo.Create(ctx, []models.Media{{
+		Key:         key,
+		OwnerID:     ownerID,
+		Purpose:     purpose,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		Status:      models.MediaStatusPending,
+		UploadID:    uploadID,
+	}})
+	if err != nil {
+		if abortErr := s.Storage.AbortMultipartUpload(ctx, key, uploadID); abortErr != nil {
+			log.Printf("failed to abort multipart upload %s after reservation error: %v", key, abortErr)
+		}
+		s.releaseUploads(ctx, ownerID, purpose, 1, file.Size)
+		return MultipartUpload{}, err
+	}
+
+	partSize, partCount := multipartLayout(file.Size)
+	return MultipartUpload{
+		FileKey:   key,
+		UploadID:  uploadID,
+		PartSize:  partSize,
+		PartCount: partCount,
+	}, nil
+}
+
+// PresignParts signs upload URLs for the requested part numbers. Clients resuming an upload
+// request only the parts missing from GetUploadedParts.
+func (s *MediaService) PresignParts(ctx context.Context, ownerID string, key string, partNumbers []int32) ([]PresignedPart, error) {
+	if len(partNumbers) == 0 || len(partNumbers) > maxPartsPerRequest {
+		return nil, fmt.Errorf("%w: between 1 and %d parts can be requested at once", ErrMediaInvalid, maxPartsPerRequest)
+	}
+
+	media, err := s.getMultipartUpload(ctx, ownerID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	partSize, partCount := multipartLayout(media.Size)
+	parts := make([]PresignedPart, len(partNumbers))
+	for i, partNumber := range partNumbers {
+		if partNumber < 1 || partNumber > partCount {
+			return nil, fmt.Errorf("%w: part number must be between 1 and %d", ErrMediaInvalid, partCount)
+		}
+
+		length := partSize
+		if partNumber == partCount {
+			length = media.Size - partSize*int64(partCount-1)
+		}
+
+		url, err := s.Storage.GeneratePresignedPartURL(ctx, key, media.UploadID, partNumber, length, partURLExpiry)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = PresignedPart{
+			PartNumber:    partNumber,
+			PresignedURL:  url,
+			ContentLength: length,
+		}
+	}
+
+	return parts, nil
+}
+
+// GetUploadedParts lists the parts storage already holds so an interrupted upload can resume
+func (s *MediaService) GetUploadedParts(ctx context.Context, ownerID string, key string) ([]UploadedPart, error) {
+	media, err := s.getMultipartUpload(ctx, ownerID, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.Storage.ListUploadedParts(ctx, key, media.UploadID)
+}
+
+// CompleteMultipartUpload assembles the uploaded parts once every one of them is present,
+// then confirms the resulting object like a single-PUT upload
+func (s *MediaService) CompleteMultipartUpload(ctx context.Context, ownerID string, key string) (models.Media, error) {
+	media, err := s.getMultipartUpload(ctx, ownerID, key)
+	if err != nil {
+		return models.Media{}, err
+	}
+
+	parts, err := s.Storage.ListUploadedParts(ctx, key, media.UploadID)
+	if err != nil {
+		return models.Media{}, err
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	_, partCount := multipartLayout(media.Size)
+	var total int64
+	for i, part := range parts {
+		if part.PartNumber != int32(i+1) {
+			return models.Media{}, fmt.Errorf("%w: part %d is missing", ErrMediaInvalid, i+1)
+		}
+		total += part.Size
+	}
+	if int32(len(parts)) != partCount || total != media.Size {
+		return models.Media{}, fmt.Errorf("%w: %d of %d parts uploaded", ErrMediaInvalid, len(parts), partCount)
+	}
+
+	if err := s.Storage.CompleteMultipartUpload(ctx, key, media.UploadID, parts); err != nil {
+		return models.Media{}, err
+	}
+
+	return s.ConfirmUpload(ctx, ownerID, key, media.Purpose)
+}
+
+// AbortMultipartUpload discards an upload the client gave up on and releases its reservation
+func (s *MediaService) AbortMultipartUpload(ctx context.Context, ownerID string, key string) error {
+	media, err := s.getMultipartUpload(ctx, ownerID, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Storage.AbortMultipartUpload(ctx, key, media.UploadID); err != nil {
+		return err
+	}
+	return s.Repo.DeletePending(ctx, key)
+}
+
+// CleanupStaleUploads aborts multipart uploads started more than maxAge ago and releases their reservations.
+// Storage keeps the parts of an incomplete upload indefinitely, so these would otherwise be billed forever.
+func (s *MediaService) CleanupStaleUploads(ctx context.Context, maxAge time.Duration) (int, error) {
+	uploads, err := s.Storage.ListIncompleteUploads(ctx, time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, err
+	}
+
+	aborted := 0
+	for _, upload := range uploads {
+		if err := s.Storage.AbortMultipartUpload(ctx, upload.Key, upload.UploadID); err != nil {
+			log.Printf("failed to abort stale upload %s: %v", upload.Key, err)
+			continue
+		}
+		if err := s.Repo.DeletePending(ctx, upload.Key); err != nil {
+			log.Printf("failed to release reservation for %s: %v", upload.Key, err)
+		}
+		aborted++
+	}
+
+	return aborted, nil
+}
+
+// ReleaseExpiredReservations drops single-PUT reservations that were never confirmed well after their
+// upload URL expired, along with anything uploaded for them, so they stop counting against the quota
+func (s *MediaService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-uploadURLExpiry - pendingUploadGrace)
+	released := 0
+	for {
+		expired, err := s.Repo.GetExpiredPending(ctx, cutoff, orphanSweepBatchSize)
+		if err != nil {
+			return released, err
+		}
+		if len(expired) == 0 {
+			return released, nil
+		}
+
+		for _, media := range expired {
+			if err := s.Storage.DeleteObject(ctx, media.Key); err != nil && !errors.Is(err, ErrObjectNotFound) {
+				return released, err
+			}
+			if err := s.Repo.DeletePending(ctx, media.Key); err != nil {
+				return released, err
+			}
+			released++
+		}
+	}
+}
+
+// RunUploadCleanup calls CleanupStaleUploads and ReleaseExpiredReservations every interval until ctx is cancelled
+func (s *MediaService) RunUploadCleanup(ctx context.Context, interval time.Duration, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			aborted, err := s.CleanupStaleUploads(ctx, maxAge)
+			if err != nil {
+				log.Printf("upload cleanup failed: %v", err)
+			} else if aborted > 0 {
+				log.Printf("upload cleanup aborted %d stale multipart uploads", aborted)
+			}
+			released, err := s.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				log.Printf("upload reservation cleanup failed: %v", err)
+			} else if released > 0 {
+				log.Printf("upload cleanup released %d expired upload reservations", released)
+			}
+		}
+	}
+}
+
+func (s *MediaService) getMultipartUpload(ctx context.Context, ownerID string, key string) (models.Media, error) {
+	media, err := s.Repo.GetByKey(ctx, key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Media{}, fmt.Errorf("%w: no upload was started for %s", ErrMediaNotFound, key)
+		}
+		return models.Media{}, err
+	}
+	if media.OwnerID != ownerID {
+		return models.Media{}, ErrMediaNotOwned
+	}
+	if media.Status != models.MediaStatusPending || media.UploadID == "" {
+		return models.Media{}, fmt.Errorf("%w: %s is not an open multipart upload", ErrMediaInvalid, key)
+	}
+	return media, nil
+}
+
+// multipartLayout picks the part size for a file, growing it past the default when the
+// file would otherwise need more parts than storage allows
+func multipartLayout(size int64) (int64, int32) {
+	partSize := int64(multipartPartSize)
+	if minSize := (size + multipartMaxParts - 1) / multipartMaxParts; minSize > partSize {
+		partSize = minSize
+	}
+	return partSize, int32((size + partSize - 1) / partSize)
+}
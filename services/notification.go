@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"myapp/models"
+	"myapp/repositories"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NotificationChannelPrefix is prefixed to a recipient's ID to name the Redis channel their
+// notifications are published on, for clients holding a live connection
+const NotificationChannelPrefix = "notifications:"
+
+// NotificationService stores notifications in each user's inbox and publishes them on Redis.
+// The inbox is the record; publishing is best effort.
+type NotificationService struct {
+	repo        repositories.NotificationRepository
+	redisClient *redis.Client
+}
+
+func NewNotificationService(repo repositories.NotificationRepository, redisClient *redis.Client) *NotificationService {
+	return &NotificationService{
+		repo:        repo,
+		redisClient: redisClient,
+	}
+}
+
+func (s *NotificationService) Notify(ctx context.Context, notification models.Notification) (models.Notification, error) {
+	saved, err := s.repo.Create(ctx, notification)
+	if err != nil {
+		return models.Notification{}, err
+	}
+
+	if s.redisClient != nil {
+		payload, err := json.Marshal(saved)
+		if err == nil {
+			err = s.redisClient.Publish(ctx, NotificationChannelPrefix+saved.RecipientID, payload).Err()
+		}
+		if err != nil {
+			log.Printf("⚠️  Failed to publish notification %s: %v", saved.ID, err)
+		}
+	}
+	return saved, nil
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, recipientID string, params models.NotificationQueryParams) ([]models.Notification, int64, error) {
+	notifications, err := s.repo.GetByRecipient(ctx, recipientID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.repo.CountUnread(ctx, recipientID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, id string, recipientID string) error {
+	return s.repo.MarkRead(ctx, id, recipientID)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, recipientID string) (int64, error) {
+	return s.repo.MarkAllRead(ctx, recipientID)
+}
@@ -3,21 +3,43 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"fmt"
+	"myapp/constants"
 	"myapp/models"
 	"myapp/repositories"
 	"myapp/utils"
 
 	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var ErrPropertyInvalid = errors.New("invalid property")
+
 type PropertyService struct {
-	Repo        repositories.PropertyRepository
-	RedisClient *redis.Client
+	Repo           repositories.PropertyRepository
+	DealerRepo     repositories.DealerRepository
+	RedisClient    *redis.Client
+	ImageProcessor *ImageProcessingService
+	Media          *MediaService
+	SavedSearches  *SavedSearchService
+
+	rerenderMu      sync.Mutex
+	rerenderRunning map[string]bool
+	rerenderPending map[string]bool
 }
 
 func (s *PropertyService) CreateProperty(ctx context.Context, property models.Property) (string, error) {
+	property.Photos = storedPhotoVariants(property.Photos, models.Property{})
+	if err := s.verifyPropertyMedia(ctx, property.DealerID, property.Photos, property.Videos, models.Property{}); err != nil {
+		return "", err
+	}
+
 	var resultID string
 
 	err := utils.Retry(ctx, func() error {
@@ -44,6 +66,12 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property models.Pr
 		s.InvalidateDealerPropertyCache(property.DealerID)
 	}
 
+	s.processPhotosAsync(resultID, property.DealerID, property.Photos)
+
+	if s.SavedSearches != nil {
+		s.SavedSearches.MatchPropertyAsync(resultID, models.SavedSearchTriggerCreated)
+	}
+
 	return resultID, nil
 }
 
@@ -55,7 +83,33 @@ func (s *PropertyService) UpdateProperty(id string, updates models.PropertyUpdat
 		return err
 	}
 
-	
+	// Either side may come from the stored listing when the update sends only one of them
+	floor, totalFloors := property.Floor, property.TotalFloors
+	if updates.Floor != nil {
+		floor = *updates.Floor
+	}
+	if updates.TotalFloors != nil {
+		totalFloors = *updates.TotalFloors
+	}
+	if totalFloors > 0 && floor > totalFloors {
+		return fmt.Errorf("%w: floor cannot be greater than total floors", ErrPropertyInvalid)
+	}
+
+	if updates.Photos != nil || updates.Videos != nil {
+		var photos []models.Photo
+		var videos []string
+		if updates.Photos != nil {
+			*updates.Photos = storedPhotoVariants(*updates.Photos, property)
+			photos = *updates.Photos
+		}
+		if updates.Videos != nil {
+			videos = *updates.Videos
+		}
+		if err := s.verifyPropertyMedia(context.Background(), property.DealerID, photos, videos, property); err != nil {
+			return err
+		}
+	}
+
 	err = s.Repo.Update(context.Background(), id, updates)
 	if err != nil {
 		return err
@@ -65,6 +119,229 @@ func (s *PropertyService) UpdateProperty(id string, updates models.PropertyUpdat
 		s.InvalidateDealerPropertyCache(property.DealerID)
 	}
 
+	if updates.Photos != nil {
+		s.processPhotosAsync(id, property.DealerID, *updates.Photos)
+	}
+
+	if s.SavedSearches != nil && (updates.MinPrice != nil || updates.MaxPrice != nil) {
+		s.SavedSearches.MatchPropertyAsync(id, models.SavedSearchTriggerPriceUpdated)
+	}
+
+	return nil
+}
+
+// verifyPropertyMedia rejects photos and videos that are not confirmed uploads of the listing's dealer.
+// Entries already on the existing property are kept as-is, so legacy URL-only photos survive edits.
+func (s *PropertyService) verifyPropertyMedia(ctx context.Context, dealerID string, photos []models.Photo, videos []string, existing models.Property) error {
+	if s.Media == nil {
+		return nil
+	}
+
+	attached := make(map[string]bool)
+	for _, photo := range existing.Photos {
+		attached[photo.Key] = true
+		attached[photo.URL] = true
+	}
+	for _, video := range existing.Videos {
+		attached[video] = true
+	}
+
+	var photoKeys []string
+	for _, photo := range photos {
+		if photo.Key == "" {
+			if !attached[photo.URL] {
+				return fmt.Errorf("%w: photos must reference uploaded files", ErrMediaNotConfirmed)
+			}
+			continue
+		}
+		if !attached[photo.Key] {
+			photoKeys = append(photoKeys, photo.Key)
+		}
+	}
+
+	var videoKeys []string
+	for _, video := range videos {
+		if attached[video] {
+			continue
+		}
+		if strings.HasPrefix(video, "http://") || strings.HasPrefix(video, "https://") {
+			return fmt.Errorf("%w: videos must reference uploaded files", ErrMediaNotConfirmed)
+		}
+		videoKeys = append(videoKeys, video)
+	}
+
+	if err := s.Media.VerifyOwnedMedia(ctx, dealerID, photoKeys, constants.MediaPurposePropertyPhoto); err != nil {
+		return err
+	}
+	return s.Media.VerifyOwnedMedia(ctx, dealerID, videoKeys, constants.MediaPurposePropertyVideo)
+}
+
+// storedPhotoVariants replaces any variants sent by the client with the ones already stored for the
+// same photo. Variants only ever come from processing, so new uploads are always resized and watermarked.
+func storedPhotoVariants(photos []models.Photo, existing models.Property) []models.Photo {
+	stored := make(map[string]map[string]string)
+	for _, photo := range existing.Photos {
+		if photo.Key != "" {
+			stored[photo.Key] = photo.Variants
+		}
+	}
+
+	for i := range photos {
+		photos[i].Variants = nil
+		if photos[i].Key != "" {
+			photos[i].Variants = stored[photos[i].Key]
+		}
+	}
+	return photos
+}
+
+// processPhotosAsync generates size variants for uploaded photos that do not have them yet
+func (s *PropertyService) processPhotosAsync(propertyID string, dealerID string, photos []models.Photo) {
+	if s.ImageProcessor == nil {
+		return
+	}
+
+	var pending []string
+	for _, photo := range photos {
+		if photo.Key != "" && len(photo.Variants) == 0 {
+			pending = append(pending, photo.Key)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		watermark := s.loadDealerWatermark(ctx, dealerID)
+		for _, key := range pending {
+			variants, err := s.ImageProcessor.ProcessPhoto(ctx, key, watermark)
+			if err != nil {
+				log.Printf("⚠️  Failed to process photo %s for property %s: %v", key, propertyID, err)
+				continue
+			}
+			if err := s.Repo.SetPhotoVariants(ctx, propertyID, key, variants); err != nil {
+				log.Printf("⚠️  Failed to save photo variants for property %s: %v", propertyID, err)
+			}
+		}
+
+		if s.RedisClient != nil {
+			s.InvalidateDealerPropertyCache(dealerID)
+		}
+	}()
+}
+
+func (s *PropertyService) loadDealerWatermark(ctx context.Context, dealerID string) *Watermark {
+	if s.DealerRepo == nil {
+		return nil
+	}
+	dealer, err := s.DealerRepo.GetByID(ctx, dealerID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load dealer %s for watermark: %v", dealerID, err)
+		return nil
+	}
+	watermark, err := s.ImageProcessor.LoadWatermark(ctx, dealer)
+	if err != nil {
+		log.Printf("⚠️  Failed to load watermark for dealer %s: %v", dealerID, err)
+		return nil
+	}
+	return watermark
+}
+
+// RerenderDealerPhotosAsync regenerates every photo variant of a dealer's listings after a branding change.
+// Only one job runs per dealer; a change arriving mid-run schedules exactly one more pass.
+func (s *PropertyService) RerenderDealerPhotosAsync(dealerID string) {
+	if s.ImageProcessor == nil {
+		return
+	}
+
+	s.rerenderMu.Lock()
+	if s.rerenderRunning == nil {
+		s.rerenderRunning = make(map[string]bool)
+		s.rerenderPending = make(map[string]bool)
+	}
+	if s.rerenderRunning[dealerID] {
+		s.rerenderPending[dealerID] = true
+		s.rerenderMu.Unlock()
+		return
+	}
+	s.rerenderRunning[dealerID] = true
+	s.rerenderMu.Unlock()
+
+	go func() {
+		for {
+			if err := s.rerenderDealerPhotos(dealerID); err != nil {
+				log.Printf("⚠️  Photo re-render failed for dealer %s: %v", dealerID, err)
+			}
+
+			s.rerenderMu.Lock()
+			if !s.rerenderPending[dealerID] {
+				delete(s.rerenderRunning, dealerID)
+				s.rerenderMu.Unlock()
+				return
+			}
+			delete(s.rerenderPending, dealerID)
+			s.rerenderMu.Unlock()
+		}
+	}()
+}
+
+func (s *PropertyService) rerenderDealerPhotos(dealerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return err
+	}
+
+	watermark := s.loadDealerWatermark(ctx, dealerID)
+	filter := bson.M{
+		"dealer_id":  dealerObjectID,
+		"is_deleted": bson.M{"$ne": true},
+		"photos.key": bson.M{"$exists": true},
+	}
+	projection := bson.M{"_id": 1, "photos": 1}
+
+	// Page on _id rather than skip: edits during the run cannot shift listings between pages
+	const batchSize = 50
+	for {
+		properties, err := s.Repo.GetFilteredProperties(ctx, filter, projection, batchSize, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, property := range properties {
+			for _, photo := range property.Photos {
+				if photo.Key == "" {
+					continue
+				}
+				variants, err := s.ImageProcessor.ProcessPhoto(ctx, photo.Key, watermark)
+				if err != nil {
+					log.Printf("⚠️  Failed to re-render photo %s for property %s: %v", photo.Key, property.ID, err)
+					continue
+				}
+				if err := s.Repo.SetPhotoVariants(ctx, property.ID, photo.Key, variants); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(properties) < batchSize {
+			break
+		}
+		lastID, err := primitive.ObjectIDFromHex(properties[len(properties)-1].ID)
+		if err != nil {
+			return err
+		}
+		filter["_id"] = bson.M{"$lt": lastID}
+	}
+
+	if s.RedisClient != nil {
+		s.InvalidateDealerPropertyCache(dealerID)
+	}
 	return nil
 }
 
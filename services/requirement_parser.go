@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+)
+
+const (
+	// How long the known sub-locations are reused before they are read from the dealers again
+	areaVocabularyTTL = 10 * time.Minute
+	// How long to wait before reading them again after a failed read
+	areaVocabularyRetry = time.Minute
+)
+
+// Locations that are catch-all buckets rather than places anyone names in an inquiry
+var unmatchableLocations = map[string]bool{"Extra": true}
+
+const (
+	amountUnits   = `crores?|crs?|lakhs?|lacs?|lac|lkh|l|k|thousand`
+	amountNumber  = `(\d+(?:\.\d+)?)`
+	currencyMark  = `(?:rs\.?|inr|₹)`
+	bedroomsWords = `bhk|b\.h\.k\.?|bed\s?rooms?|beds?`
+)
+
+var (
+	digitGroupRe    = regexp.MustCompile(`(\d),(\d)`)
+	bedroomsRangeRe = regexp.MustCompile(`\b(\d{1,2})(\.5)?\s*(?:-|to|or|/)\s*(\d{1,2})(\.5)?\s*(?:` + bedroomsWords + `)(?:\W|$)`)
+	bedroomsRe      = regexp.MustCompile(`\b(\d{1,2})(\.5)?\s*(?:` + bedroomsWords + `)(?:\W|$)`)
+	roomKitchenRe   = regexp.MustCompile(`\b1\s*rk\b`)
+	budgetRangeRe   = regexp.MustCompile(`(?:^|[^\w.])` + currencyMark + `?\s*` + amountNumber + `\s*(` + amountUnits + `)?\s*(?:-|to|and)\s*` +
+		currencyMark + `?\s*` + amountNumber + `\s*(` + amountUnits + `)\b`)
+	budgetRe = regexp.MustCompile(`(?:\b(under|below|upto|up to|within|max(?:imum)?|less than|not more than|budget(?: of| is)?|around|about|approx|above|over|more than|min(?:imum)?|at least|starting(?: from)?|from)\s*)?` +
+		`(` + currencyMark + `)?\s*` + amountNumber + `\s*(` + amountUnits + `)?(?:\s*(` + currencyMark + `))?(?:\W|$)`)
+	rentRe          = regexp.MustCompile(`\b(rent|rental|on rent|lease|leased|tenants?|pg|paying guest|per month|monthly|kiraye?|kiraya)\b`)
+	saleRe          = regexp.MustCompile(`\b(buy|buying|purchase|for sale|resale|ownership|freehold|registry|kharidna)\b`)
+	semiFurnishedRe = regexp.MustCompile(`\bsemi[\s-]?furnished\b`)
+	unfurnishedRe   = regexp.MustCompile(`\b(un[\s-]?furnished|bare shell)\b`)
+	furnishedRe     = regexp.MustCompile(`\b(fully[\s-]?furnished|furnished)\b`)
+	noidaSectorRe   = regexp.MustCompile(`^noida sector (\d+)`)
+	nonAlphanumRe   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// areaTerm is one way of writing a location or sub-location
+type areaTerm struct {
+	key         string
+	value       string
+	subLocation bool
+}
+
+// RequirementParser turns free-text requirements such as "2bhk under 45 lakh near laxmi nagar
+// metro" into structured criteria. Locations come from constants.Locations and sub-locations
+// from the dealers' registered areas.
+type RequirementParser struct {
+	dealerRepo repositories.DealerRepository
+
+	mu       sync.Mutex
+	terms    []areaTerm
+	loadedAt time.Time
+	failedAt time.Time
+}
+
+func NewRequirementParser(dealerRepo repositories.DealerRepository) *RequirementParser {
+	return &RequirementParser{dealerRepo: dealerRepo}
+}
+
+// Parse extracts what it can from text. Anything it cannot read is left unset, so the result
+// may be empty; it never fails.
+func (p *RequirementParser) Parse(ctx context.Context, text string) models.RequirementCriteria {
+	return parseRequirement(text, p.areaTerms(ctx))
+}
+
+// fillCriteria parses text into criteria unless the caller already supplied structured criteria.
+// Parsed criteria that do not validate are dropped rather than failing the request.
+func (p *RequirementParser) fillCriteria(ctx context.Context, criteria *models.RequirementCriteria, text string) *models.RequirementCriteria {
+	if p == nil || criteria != nil || strings.TrimSpace(text) == "" {
+		return criteria
+	}
+
+	parsed := p.Parse(ctx, text)
+	normalized, err := NormalizeRequirementCriteria(&parsed)
+	if err != nil {
+		log.Printf("requirement parser: discarding parsed criteria: %v", err)
+		return nil
+	}
+	return normalized
+}
+
+// areaTerms returns the cached vocabulary, reloading it once it expires. The lock is not held
+// while the dealers are read, so parses never queue behind the database.
+func (p *RequirementParser) areaTerms(ctx context.Context) []areaTerm {
+	p.mu.Lock()
+	terms, loadedAt, failedAt := p.terms, p.loadedAt, p.failedAt
+	p.mu.Unlock()
+
+	if terms != nil && time.Since(loadedAt) < areaVocabularyTTL {
+		return terms
+	}
+	if p.dealerRepo == nil {
+		terms = buildAreaTerms(nil)
+		p.store(terms)
+		return terms
+	}
+	if time.Since(failedAt) < areaVocabularyRetry {
+		return fallbackAreaTerms(terms)
+	}
+
+	areas, err := p.dealerRepo.GetLocationsWithSubLocations(ctx)
+	if err != nil {
+		// Keep what we had, or the fixed locations, until the retry delay passes
+		log.Printf("requirement parser: failed to load sub-locations: %v", err)
+		p.mu.Lock()
+		p.failedAt = time.Now()
+		p.mu.Unlock()
+		return fallbackAreaTerms(terms)
+	}
+
+	terms = buildAreaTerms(areas)
+	p.store(terms)
+	return terms
+}
+
+func (p *RequirementParser) store(terms []areaTerm) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.terms = terms
+	p.loadedAt = time.Now()
+	p.failedAt = time.Time{}
+}
+
+// fallbackAreaTerms is the stale vocabulary when there is one, otherwise the fixed locations
+func fallbackAreaTerms(stale []areaTerm) []areaTerm {
+	if stale != nil {
+		return stale
+	}
+	return buildAreaTerms(nil)
+}
+
+// buildAreaTerms lists every spelling the parser recognises, longest first so that
+// "Karkarduma Court" wins over "Karkarduma"
+func buildAreaTerms(areas []models.LocationWithSubLocations) []areaTerm {
+	var terms []areaTerm
+	seen := make(map[string]bool)
+	add := func(value string, subLocation bool) {
+		if unmatchableLocations[value] {
+			return
+		}
+		for _, key := range areaAliases(value) {
+			if len(key) < 4 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			terms = append(terms, areaTerm{key: key, value: value, subLocation: subLocation})
+		}
+	}
+
+	for _, location := range constants.Locations {
+		add(location, false)
+	}
+	for _, area := range areas {
+		for _, subLocation := range area.SubLocation {
+			add(subLocation, true)
+		}
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i].key) > len(terms[j].key) })
+	return terms
+}
+
+// areaAliases returns the normalised ways a place is commonly written: "Noida Sector 62" is also
+// "sector 62" and "sec 62", "Mayur Vihar-I" is also "mayur vihar 1", and each side of
+// "Trilokpuri – Sanjay Lake" is accepted on its own
+func areaAliases(name string) []string {
+	var aliases []string
+	parts := []string{name}
+	if split := strings.FieldsFunc(name, func(r rune) bool { return r == '–' }); len(split) > 1 {
+		parts = append(parts, split...)
+	}
+
+	for _, part := range parts {
+		key := areaKey(part)
+		if key == "" {
+			continue
+		}
+		aliases = append(aliases, key)
+
+		if match := noidaSectorRe.FindStringSubmatch(key); match != nil {
+			aliases = append(aliases, "sector "+match[1], "sec "+match[1], "noida sec "+match[1])
+		}
+		switch {
+		case strings.HasSuffix(key, " ii"):
+			aliases = append(aliases, strings.TrimSuffix(key, " ii")+" 2", strings.TrimSuffix(key, " ii")+" phase 2")
+		case strings.HasSuffix(key, " i"):
+			aliases = append(aliases, strings.TrimSuffix(key, " i")+" 1", strings.TrimSuffix(key, " i")+" phase 1")
+		}
+	}
+	return aliases
+}
+
+// areaKey lower-cases a place name and collapses punctuation to single spaces
+func areaKey(value string) string {
+	return strings.TrimSpace(nonAlphanumRe.ReplaceAllString(strings.ToLower(value), " "))
+}
+
+func parseRequirement(text string, terms []areaTerm) models.RequirementCriteria {
+	var criteria models.RequirementCriteria
+
+	lower := strings.ToLower(text)
+	for digitGroupRe.MatchString(lower) {
+		lower = digitGroupRe.ReplaceAllString(lower, "$1$2")
+	}
+
+	criteria.MinBedrooms, criteria.MaxBedrooms = parseBedrooms(lower)
+	criteria.MinBudget, criteria.MaxBudget = parseBudget(lower)
+
+	isRent, isSale := rentRe.MatchString(lower), saleRe.MatchString(lower)
+	switch {
+	case isRent && !isSale:
+		criteria.ListingType = constants.ListingTypeRent
+	case isSale && !isRent:
+		criteria.ListingType = constants.ListingTypeSale
+	}
+
+	switch {
+	case semiFurnishedRe.MatchString(lower):
+		criteria.Furnishing = constants.SemiFurnished
+	case unfurnishedRe.MatchString(lower):
+		criteria.Furnishing = constants.Unfurnished
+	case furnishedRe.MatchString(lower):
+		criteria.Furnishing = constants.Furnished
+	}
+
+	// Matched spans are blanked out so shorter names inside them are not matched again
+	remaining := " " + areaKey(lower) + " "
+	for _, term := range terms {
+		needle := " " + term.key + " "
+		if !strings.Contains(remaining, needle) {
+			continue
+		}
+		remaining = strings.ReplaceAll(remaining, needle, " | ")
+		if term.subLocation {
+			criteria.SubLocations = appendUnique(criteria.SubLocations, term.value)
+		} else {
+			criteria.Locations = appendUnique(criteria.Locations, term.value)
+		}
+	}
+
+	return criteria
+}
+
+// parseBedrooms reads "2bhk", "2-3 BHK", "3 bedrooms" or "1rk". Half rooms widen the range.
+func parseBedrooms(text string) (int, int) {
+	if match := bedroomsRangeRe.FindStringSubmatch(text); match != nil {
+		low, _ := strconv.Atoi(match[1])
+		high, _ := strconv.Atoi(match[3])
+		if match[4] != "" {
+			high++
+		}
+		if low > 0 && low <= high && high <= 10 {
+			return low, high
+		}
+	}
+	if match := bedroomsRe.FindStringSubmatch(text); match != nil {
+		rooms, _ := strconv.Atoi(match[1])
+		if rooms > 0 && rooms <= 10 {
+			if match[2] != "" {
+				return rooms, rooms + 1
+			}
+			return rooms, rooms
+		}
+	}
+	if roomKitchenRe.MatchString(text) {
+		return 1, 1
+	}
+	return 0, 0
+}
+
+// parseBudget reads "under 45 lakh", "40-50L", "1.2 cr", "above 80 lakh", "rent 25k" or "15000 rs". An
+// amount without a qualifier is taken as the upper limit. Bare numbers need a unit or currency
+// mark so sector numbers and years are not mistaken for budgets.
+func parseBudget(text string) (int64, int64) {
+	if match := budgetRangeRe.FindStringSubmatch(text); match != nil {
+		// "40-50 lakh" shares the unit, "1,20,00,000 to 1.5 cr" is already in rupees
+		firstUnit := match[2]
+		if raw, _ := utils.ParseIndianAmount(match[1], ""); firstUnit == "" && raw < 1000 {
+			firstUnit = match[4]
+		}
+		low, lowOK := utils.ParseIndianAmount(match[1], firstUnit)
+		high, highOK := utils.ParseIndianAmount(match[3], match[4])
+		if lowOK && highOK && low <= high {
+			return low, high
+		}
+	}
+
+	var min, max int64
+	for _, match := range budgetRe.FindAllStringSubmatch(text, -1) {
+		// The currency mark may come before the amount or after it, as in "50,00,000 rs"
+		qualifier, currency, number, unit := match[1], match[2]+match[5], match[3], match[4]
+		if unit == "" && currency == "" {
+			continue
+		}
+		amount, ok := utils.ParseIndianAmount(number, unit)
+		if !ok || amount < 1000 {
+			continue
+		}
+
+		switch qualifier {
+		case "above", "over", "more than", "min", "minimum", "at least", "starting", "starting from", "from":
+			if min == 0 {
+				min = amount
+			}
+		default:
+			if max == 0 {
+				max = amount
+			}
+		}
+	}
+
+	if max > 0 && min > max {
+		min = 0
+	}
+	return min, max
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
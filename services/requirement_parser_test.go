@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+)
+
+func TestParseBudget(t *testing.T) {
+	tests := []struct {
+		text     string
+		min, max int64
+	}{
+		{"2bhk under 45 lakh", 0, 4500000},
+		{"budget 40-50L", 4000000, 5000000},
+		{"1.2 cr flat", 0, 12000000},
+		{"above 80 lakh", 8000000, 0},
+		{"rent 25k per month", 0, 25000},
+		{"rs 15000 rent", 0, 15000},
+		{"₹ 60 lakh", 0, 6000000},
+		{"50,00,000 rs", 0, 5000000},
+		{"15000 rs", 0, 15000},
+		{"15000 rs.", 0, 15000},
+		{"1,20,00,000 to 1.5 cr", 12000000, 15000000},
+		{"from 40 lakh upto 60 lakh", 4000000, 6000000},
+		{"noida sector 62", 0, 0},
+		{"built in 2015", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			criteria := parseRequirement(tt.text, nil)
+			if criteria.MinBudget != tt.min || criteria.MaxBudget != tt.max {
+				t.Errorf("budget = %d-%d, want %d-%d", criteria.MinBudget, criteria.MaxBudget, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestParseBedrooms(t *testing.T) {
+	tests := []struct {
+		text     string
+		min, max int
+	}{
+		{"2bhk", 2, 2},
+		{"2-3 BHK", 2, 3},
+		{"3 bedrooms", 3, 3},
+		{"2.5 bhk", 2, 3},
+		{"1rk near metro", 1, 1},
+		{"sector 62 flat", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			criteria := parseRequirement(tt.text, nil)
+			if criteria.MinBedrooms != tt.min || criteria.MaxBedrooms != tt.max {
+				t.Errorf("bedrooms = %d-%d, want %d-%d", criteria.MinBedrooms, criteria.MaxBedrooms, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestParseRequirementAreasAndPreferences(t *testing.T) {
+	terms := buildAreaTerms([]models.LocationWithSubLocations{{Location: "Laxmi Nagar", SubLocation: []string{"Lalita Park"}}})
+
+	tests := []struct {
+		text string
+		want models.RequirementCriteria
+	}{
+		{
+			text: "semi furnished 2bhk on rent near laxmi nagar",
+			want: models.RequirementCriteria{MinBedrooms: 2, MaxBedrooms: 2, Locations: []string{"Laxmi Nagar"}, ListingType: constants.ListingTypeRent, Furnishing: constants.SemiFurnished},
+		},
+		{
+			text: "flat in karkarduma court",
+			want: models.RequirementCriteria{Locations: []string{"Karkarduma Court"}},
+		},
+		{
+			text: "sec 62 or mayur vihar 1",
+			want: models.RequirementCriteria{Locations: []string{"Noida Sector 62", "Mayur Vihar-I"}},
+		},
+		{
+			text: "lalita park",
+			want: models.RequirementCriteria{SubLocations: []string{"Lalita Park"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := parseRequirement(tt.text, terms)
+			if !sameElements(got.Locations, tt.want.Locations) || !sameElements(got.SubLocations, tt.want.SubLocations) {
+				t.Errorf("areas = %v %v, want %v %v", got.Locations, got.SubLocations, tt.want.Locations, tt.want.SubLocations)
+			}
+			got.Locations, got.SubLocations = nil, nil
+			tt.want.Locations, tt.want.SubLocations = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("criteria = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, value := range a {
+		counts[value]++
+	}
+	for _, value := range b {
+		counts[value]--
+		if counts[value] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// failingAreaRepo fails every read of the dealers' sub-locations and counts the attempts
+type failingAreaRepo struct {
+	repositories.DealerRepository
+	calls int
+}
+
+func (r *failingAreaRepo) GetLocationsWithSubLocations(ctx context.Context) ([]models.LocationWithSubLocations, error) {
+	r.calls++
+	return nil, errors.New("database unavailable")
+}
+
+func TestAreaTermsBacksOffAfterFailedLoad(t *testing.T) {
+	repo := &failingAreaRepo{}
+	parser := NewRequirementParser(repo)
+
+	for i := 0; i < 3; i++ {
+		criteria := parser.Parse(context.Background(), "2bhk in laxmi nagar")
+		if !reflect.DeepEqual(criteria.Locations, []string{"Laxmi Nagar"}) {
+			t.Fatalf("locations = %v, want the fixed locations while sub-locations are unavailable", criteria.Locations)
+		}
+	}
+	if repo.calls != 1 {
+		t.Errorf("sub-locations read %d times, want 1 until the retry delay passes", repo.calls)
+	}
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrSavedSearchInvalid  = errors.New("invalid saved search")
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+)
+
+// Keys of GET /properties a saved search may not hold: paging belongs to each run, and the
+// search is always scoped to the owning dealer's listings
+var savedSearchReservedKeys = map[string]bool{
+	"id":            true,
+	"dealer_id":     true,
+	"page":          true,
+	"limit":         true,
+	"aggregation":   true,
+	"array_filters": true,
+}
+
+// Keys that order results without narrowing them
+var savedSearchOrderingKeys = map[string]bool{
+	"sort":  true,
+	"order": true,
+}
+
+type SavedSearchService struct {
+	repo             repositories.SavedSearchRepository
+	propertyRepo     repositories.PropertyRepository
+	dealerClientRepo repositories.DealerClientRepository
+	notifications    *NotificationService
+}
+
+func NewSavedSearchService(repo repositories.SavedSearchRepository, propertyRepo repositories.PropertyRepository, dealerClientRepo repositories.DealerClientRepository, notifications *NotificationService) *SavedSearchService {
+	return &SavedSearchService{
+		repo:             repo,
+		propertyRepo:     propertyRepo,
+		dealerClientRepo: dealerClientRepo,
+		notifications:    notifications,
+	}
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, dealerID string, req models.SavedSearchRequest) (models.SavedSearch, error) {
+	search := models.SavedSearch{DealerID: dealerID, Active: true}
+	if err := s.apply(ctx, &search, req); err != nil {
+		return models.SavedSearch{}, err
+	}
+	return s.repo.Create(ctx, search)
+}
+
+func (s *SavedSearchService) GetSavedSearches(ctx context.Context, dealerID string, dealerClientID string) ([]models.SavedSearch, error) {
+	return s.repo.GetByDealer(ctx, dealerID, dealerClientID)
+}
+
+func (s *SavedSearchService) GetSavedSearch(ctx context.Context, id string, dealerID string) (models.SavedSearch, error) {
+	search, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.SavedSearch{}, ErrSavedSearchNotFound
+		}
+		return models.SavedSearch{}, err
+	}
+	// Another dealer's search is reported as missing rather than forbidden
+	if search.DealerID != dealerID {
+		return models.SavedSearch{}, ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// UpdateSavedSearch replaces the name, client and query; active is only changed when given
+func (s *SavedSearchService) UpdateSavedSearch(ctx context.Context, id string, dealerID string, req models.SavedSearchRequest) (models.SavedSearch, error) {
+	search, err := s.GetSavedSearch(ctx, id, dealerID)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	if err := s.apply(ctx, &search, req); err != nil {
+		return models.SavedSearch{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, search)
+	if err == mongo.ErrNoDocuments {
+		return models.SavedSearch{}, ErrSavedSearchNotFound
+	}
+	return updated, err
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, id string, dealerID string) error {
+	err := s.repo.Delete(ctx, id, dealerID)
+	if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+		return ErrSavedSearchNotFound
+	}
+	return err
+}
+
+// RunSavedSearch returns the search's current results, exactly as GET /properties would
+func (s *SavedSearchService) RunSavedSearch(ctx context.Context, id string, dealerID string, page, limit *int, fields []string) ([]models.Property, error) {
+	search, err := s.GetSavedSearch(ctx, id, dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := searchParams(search)
+	if err != nil {
+		return nil, err
+	}
+	params.Page, params.Limit = page, limit
+	params.SetDefaults()
+	return s.propertyRepo.GetProperties(ctx, params, fields)
+}
+
+func (s *SavedSearchService) GetMatches(ctx context.Context, id string, dealerID string, page, limit *int) ([]models.SavedSearchMatch, error) {
+	if _, err := s.GetSavedSearch(ctx, id, dealerID); err != nil {
+		return nil, err
+	}
+
+	paging := models.BaseQueryParams{Page: page, Limit: limit}
+	paging.SetDefaults()
+	return s.repo.GetMatches(ctx, id, *paging.Page, *paging.Limit)
+}
+
+// MatchPropertyAsync checks a new or repriced listing against its dealer's saved searches
+// in the background, so saving the listing does not wait on it
+func (s *SavedSearchService) MatchPropertyAsync(propertyID string, trigger string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := s.MatchProperty(ctx, propertyID, trigger); err != nil {
+			log.Printf("⚠️  Failed to match property %s against saved searches: %v", propertyID, err)
+		}
+	}()
+}
+
+// MatchProperty records a match for every active saved search whose results now include the
+// property and notifies the search owner of each one not matched before
+func (s *SavedSearchService) MatchProperty(ctx context.Context, propertyID string, trigger string) error {
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property.IsDeleted || property.Sold {
+		return nil
+	}
+	propertyObjectID, err := primitive.ObjectIDFromHex(property.ID)
+	if err != nil {
+		return err
+	}
+
+	searches, err := s.repo.GetActiveByDealer(ctx, property.DealerID)
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		params, err := searchParams(search)
+		if err != nil {
+			log.Printf("⚠️  Skipping saved search %s: %v", search.ID, err)
+			continue
+		}
+		params.SetDefaults()
+
+		// Run the search's own filter restricted to this one listing
+		filter := utils.BuildMongoFilter(params)
+		filter["_id"] = propertyObjectID
+		found, err := s.propertyRepo.GetFilteredProperties(ctx, filter, bson.M{"_id": 1}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		isNew, err := s.repo.RecordMatch(ctx, models.SavedSearchMatch{
+			SavedSearchID:  search.ID,
+			DealerID:       search.DealerID,
+			PropertyID:     property.ID,
+			PropertyNumber: property.PropertyNumber,
+			PropertyTitle:  property.Title,
+			MinPrice:       property.MinPrice,
+			MaxPrice:       property.MaxPrice,
+			Trigger:        trigger,
+		})
+		if err != nil {
+			return err
+		}
+		if isNew {
+			s.notifyMatch(ctx, search, property)
+		}
+	}
+	return nil
+}
+
+func (s *SavedSearchService) notifyMatch(ctx context.Context, search models.SavedSearch, property models.Property) {
+	if s.notifications == nil {
+		return
+	}
+
+	data := map[string]string{
+		"saved_search_id": search.ID,
+		"property_id":     property.ID,
+	}
+	if search.DealerClientID != "" {
+		data["dealer_client_id"] = search.DealerClientID
+	}
+
+	_, err := s.notifications.Notify(ctx, models.Notification{
+		RecipientID: search.DealerID,
+		Type:        models.NotificationSavedSearchMatch,
+		Title:       fmt.Sprintf("New match for \"%s\"", search.Name),
+		Body:        fmt.Sprintf("#%d %s, %s", property.PropertyNumber, property.Title, utils.FormatIndianPriceRange(property.MinPrice, property.MaxPrice)),
+		Data:        data,
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to notify dealer %s of saved search match: %v", search.DealerID, err)
+	}
+}
+
+// apply validates req and copies it onto search
+func (s *SavedSearchService) apply(ctx context.Context, search *models.SavedSearch, req models.SavedSearchRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrSavedSearchInvalid)
+	}
+
+	query, err := cleanSavedQuery(req.Query)
+	if err != nil {
+		return err
+	}
+
+	if req.DealerClientID != "" {
+		client, err := s.dealerClientRepo.GetByID(ctx, req.DealerClientID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+				return fmt.Errorf("%w: dealer client not found", ErrSavedSearchInvalid)
+			}
+			return err
+		}
+		if client.DealerID != search.DealerID {
+			return ErrDealerClientNotOwned
+		}
+	}
+
+	search.Name = name
+	search.DealerClientID = req.DealerClientID
+	search.Query = query
+	if req.Active != nil {
+		search.Active = *req.Active
+	}
+	return nil
+}
+
+// cleanSavedQuery trims the query and rejects keys GET /properties does not accept, reserved
+// keys and queries that would match every listing
+func cleanSavedQuery(query map[string]string) (map[string]string, error) {
+	allowed := make(map[string]bool)
+	for _, name := range utils.QueryParamNames(models.PropertyQueryParams{}) {
+		allowed[name] = !savedSearchReservedKeys[name]
+	}
+
+	cleaned := make(map[string]string, len(query))
+	filters := 0
+	for key, value := range query {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !allowed[key] {
+			return nil, fmt.Errorf("%w: %q cannot be used in a saved search", ErrSavedSearchInvalid, key)
+		}
+		cleaned[key] = value
+		if !savedSearchOrderingKeys[key] {
+			filters++
+		}
+	}
+	if filters == 0 {
+		return nil, fmt.Errorf("%w: query must contain at least one filter", ErrSavedSearchInvalid)
+	}
+	return cleaned, nil
+}
+
+func searchParams(search models.SavedSearch) (models.PropertyQueryParams, error) {
+	values := url.Values{}
+	for key, value := range search.Query {
+		values.Set(key, value)
+	}
+
+	var params models.PropertyQueryParams
+	if err := utils.ParseQueryValues(values, &params); err != nil {
+		return models.PropertyQueryParams{}, err
+	}
+	dealerID := search.DealerID
+	params.DealerID = &dealerID
+	return params, nil
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"myapp/converters"
+	"myapp/models"
+	"myapp/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrShareLinkInvalid = errors.New("share link is invalid")
+	ErrShareLinkExpired = errors.New("share link has expired or been revoked")
+)
+
+const (
+	defaultShareLinkTTL    = 7 * 24 * time.Hour
+	maxShareLinkTTL        = 30 * 24 * time.Hour
+	maxShareLinkProperties = 20
+)
+
+type ShareLinkService struct {
+	repo         repositories.ShareLinkRepository
+	propertyRepo repositories.PropertyRepository
+	dealerRepo   repositories.DealerRepository
+	analytics    *AnalyticsService
+	secret       []byte
+	publicURL    string
+}
+
+// NewShareLinkService signs tokens with secret; publicURL prefixes stored object keys such as videos and logos
+func NewShareLinkService(repo repositories.ShareLinkRepository, propertyRepo repositories.PropertyRepository, dealerRepo repositories.DealerRepository, analytics *AnalyticsService, secret string, publicURL string) *ShareLinkService {
+	return &ShareLinkService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		dealerRepo:   dealerRepo,
+		analytics:    analytics,
+		secret:       []byte(secret),
+		publicURL:    publicURL,
+	}
+}
+
+// CreateShareLink creates a link to the dealer's own, live properties and returns it with its token
+func (s *ShareLinkService) CreateShareLink(ctx context.Context, dealerID string, propertyIDs []string, title string, ttl time.Duration) (models.ShareLink, string, error) {
+	if len(propertyIDs) == 0 || len(propertyIDs) > maxShareLinkProperties {
+		return models.ShareLink{}, "", fmt.Errorf("between 1 and %d properties can be shared in one link", maxShareLinkProperties)
+	}
+	if ttl <= 0 {
+		ttl = defaultShareLinkTTL
+	}
+	if ttl > maxShareLinkTTL {
+		return models.ShareLink{}, "", fmt.Errorf("share links can last at most %d days", int(maxShareLinkTTL.Hours()/24))
+	}
+
+	seen := make(map[string]bool, len(propertyIDs))
+	var objectIDs []primitive.ObjectID
+	for _, id := range propertyIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return models.ShareLink{}, "", fmt.Errorf("invalid property ID %q", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	dealerObjectID, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return models.ShareLink{}, "", err
+	}
+	owned, err := s.propertyRepo.GetFilteredProperties(ctx, bson.M{
+		"_id":        bson.M{"$in": objectIDs},
+		"dealer_id":  dealerObjectID,
+		"is_deleted": bson.M{"$ne": true},
+	}, bson.M{"_id": 1}, int64(len(objectIDs)), 0)
+	if err != nil {
+		return models.ShareLink{}, "", err
+	}
+	if len(owned) != len(objectIDs) {
+		return models.ShareLink{}, "", errors.New("properties must be your own active listings")
+	}
+
+	ordered := make([]string, len(objectIDs))
+	for i, objectID := range objectIDs {
+		ordered[i] = objectID.Hex()
+	}
+
+	link, err := s.repo.Create(ctx, models.ShareLink{
+		DealerID:    dealerID,
+		PropertyIDs: ordered,
+		Title:       strings.TrimSpace(title),
+		ExpiresAt:   time.Now().Add(ttl),
+	})
+	if err != nil {
+		return models.ShareLink{}, "", err
+	}
+
+	return link, s.Token(link), nil
+}
+
+func (s *ShareLinkService) GetShareLinks(ctx context.Context, dealerID string) ([]models.ShareLink, error) {
+	return s.repo.GetByDealer(ctx, dealerID)
+}
+
+func (s *ShareLinkService) RevokeShareLink(ctx context.Context, id string, dealerID string) error {
+	return s.repo.Revoke(ctx, id, dealerID)
+}
+
+// Token is "<link id>.<expiry unix>.<signature>". Forged or tampered tokens are rejected before any lookup.
+func (s *ShareLinkService) Token(link models.ShareLink) string {
+	payload := fmt.Sprintf("%s.%d", link.ID, link.ExpiresAt.Unix())
+	return payload + "." + s.sign(payload)
+}
+
+// OpenShareLink resolves a token to the shared listings and counts the open. viewerID identifies
+// the visitor for unique viewer counts; see PublicViewerID.
+func (s *ShareLinkService) OpenShareLink(ctx context.Context, token string, viewerID string) (models.SharedListing, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return models.SharedListing{}, ErrShareLinkInvalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload))) {
+		return models.SharedListing{}, ErrShareLinkInvalid
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return models.SharedListing{}, ErrShareLinkInvalid
+	}
+	if time.Now().Unix() > expiresAt {
+		return models.SharedListing{}, ErrShareLinkExpired
+	}
+
+	link, err := s.repo.GetByID(ctx, parts[0])
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.SharedListing{}, ErrShareLinkInvalid
+		}
+		return models.SharedListing{}, err
+	}
+	if link.Revoked || time.Now().After(link.ExpiresAt) {
+		return models.SharedListing{}, ErrShareLinkExpired
+	}
+
+	dealer, err := s.dealerRepo.GetByID(ctx, link.DealerID)
+	if err != nil {
+		return models.SharedListing{}, err
+	}
+
+	objectIDs := make([]primitive.ObjectID, 0, len(link.PropertyIDs))
+	for _, id := range link.PropertyIDs {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	properties, err := s.propertyRepo.GetFilteredProperties(ctx, bson.M{
+		"_id":        bson.M{"$in": objectIDs},
+		"is_deleted": bson.M{"$ne": true},
+	}, nil, int64(len(objectIDs)), 0)
+	if err != nil {
+		return models.SharedListing{}, err
+	}
+	byID := make(map[string]models.Property, len(properties))
+	for _, property := range properties {
+		byID[property.ID] = property
+	}
+
+	listing := models.SharedListing{
+		Title:     link.Title,
+		ExpiresAt: link.ExpiresAt,
+		Dealer: models.SharedDealer{
+			Name:          dealer.Name,
+			ShopName:      dealer.ShopName,
+			Phone:         dealer.Phone,
+			Email:         dealer.Email,
+			OfficeAddress: dealer.OfficeAddress,
+		},
+		Properties: []models.SharedProperty{},
+	}
+	if dealer.Watermark != nil && dealer.Watermark.LogoKey != "" {
+		listing.Dealer.LogoURL = s.publicURL + dealer.Watermark.LogoKey
+	}
+	// Keep the order the dealer chose; listings deleted since are left out
+	for _, id := range link.PropertyIDs {
+		property, ok := byID[id]
+		if !ok {
+			continue
+		}
+		shared := converters.ToSharedProperty(property)
+		shared.Videos = publicMediaURLs(s.publicURL, property.Videos)
+		listing.Properties = append(listing.Properties, shared)
+		s.analytics.Record(property.ID, property.DealerID, models.PropertyEventPublicView, viewerID)
+	}
+
+	if err := s.repo.RecordOpen(ctx, link.ID); err != nil {
+		log.Printf("failed to record open of share link %s: %v", link.ID, err)
+	}
+
+	return listing, nil
+}
+
+func (s *ShareLinkService) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:18])
+}
+
+// publicMediaURLs turns stored object keys into URLs, leaving full URLs from older uploads as they are
+func publicMediaURLs(publicURL string, keys []string) []string {
+	urls := make([]string, len(keys))
+	for i, key := range keys {
+		if strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://") {
+			urls[i] = key
+		} else {
+			urls[i] = publicURL + key
+		}
+	}
+	return urls
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStorage is the bucket behind uploads, photo variants and documents.
+// Implementations: CloudflareR2Service (R2 or any S3-compatible endpoint) and LocalStorageService.
+type ObjectStorage interface {
+	GeneratePresignedURL(ctx context.Context, key string, contentType string, contentLength int64, expires time.Duration) (string, error)
+	GeneratePresignedGetURL(ctx context.Context, key string, expires time.Duration) (string, error)
+	UploadObject(ctx context.Context, key string, data []byte, contentType string) error
+	DownloadObject(ctx context.Context, key string, maxBytes int64) ([]byte, string, error)
+	DeleteObject(ctx context.Context, key string) error
+	ListObjects(ctx context.Context, prefix string, continuationToken string, maxKeys int32) (ObjectPage, error)
+	GetObjectInfo(ctx context.Context, key string) (ObjectInfo, error)
+
+	CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error)
+	GeneratePresignedPartURL(ctx context.Context, key string, uploadID string, partNumber int32, contentLength int64, expires time.Duration) (string, error)
+	ListUploadedParts(ctx context.Context, key string, uploadID string) ([]UploadedPart, error)
+	CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []UploadedPart) error
+	AbortMultipartUpload(ctx context.Context, key string, uploadID string) error
+	ListIncompleteUploads(ctx context.Context, startedBefore time.Time) ([]IncompleteUpload, error)
+}
+
+// ObjectInfo is the metadata storage holds for an object
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ObjectPage is one page of a listing; NextToken is empty on the last page
+type ObjectPage struct {
+	Objects   []ObjectInfo `json:"objects"`
+	NextToken string       `json:"next_token,omitempty"`
+}
+
+// UploadedPart is a part of a multipart upload that storage has already received
+type UploadedPart struct {
+	PartNumber int32  `json:"partNumber"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size,omitempty"`
+}
+
+// IncompleteUpload is a multipart upload that was started but never completed or aborted
+type IncompleteUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
@@ -0,0 +1,537 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrTaskInvalid  = errors.New("invalid task")
+	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskNotOwned = errors.New("task is assigned to someone else")
+	ErrTaskClosed   = errors.New("task has already been completed")
+)
+
+const (
+	// taskRemindersKey is a Redis sorted set of task IDs scored by when their reminder is due
+	taskRemindersKey = "tasks:reminders"
+	// How many reminders one scheduler tick sends at most; the rest wait for the next tick
+	reminderBatchSize = 100
+	// How long a reminder that could not be sent waits before it is tried again
+	reminderRetryDelay = time.Minute
+
+	maxTaskTitleLength   = 200
+	maxTaskNotesLength   = 2000
+	maxTaskOutcomeLength = 1000
+	maxRemindBefore      = 7 * 24 * time.Hour
+	maxSnooze            = 30 * 24 * time.Hour
+
+	taskDueLayout = "2 Jan, 3:04 PM"
+)
+
+// TaskService keeps follow-up tasks and reminds their assignees when they fall due. Reminders
+// are queued in Redis so any number of API instances can run the scheduler; each reminder is
+// claimed by exactly one of them. Without Redis the scheduler polls Mongo instead.
+type TaskService struct {
+	repo             repositories.TaskRepository
+	leadRepo         repositories.LeadRepository
+	dealerClientRepo repositories.DealerClientRepository
+	propertyRepo     repositories.PropertyRepository
+	dealerRepo       repositories.DealerRepository
+	notifications    *NotificationService
+	redisClient      *redis.Client
+}
+
+func NewTaskService(repo repositories.TaskRepository, leadRepo repositories.LeadRepository, dealerClientRepo repositories.DealerClientRepository, propertyRepo repositories.PropertyRepository, dealerRepo repositories.DealerRepository, notifications *NotificationService, redisClient *redis.Client) *TaskService {
+	return &TaskService{
+		repo:             repo,
+		leadRepo:         leadRepo,
+		dealerClientRepo: dealerClientRepo,
+		propertyRepo:     propertyRepo,
+		dealerRepo:       dealerRepo,
+		notifications:    notifications,
+		redisClient:      redisClient,
+	}
+}
+
+// CreateTask adds a task about a lead, dealer client or property. Dealers can only create tasks
+// for themselves about what they work on; admins can assign any subject to any active dealer
+// or to themselves.
+func (s *TaskService) CreateTask(ctx context.Context, userID string, role string, req models.TaskRequest) (models.Task, error) {
+	task := models.Task{
+		SubjectType: strings.TrimSpace(req.SubjectType),
+		SubjectID:   strings.TrimSpace(req.SubjectID),
+		CreatedBy:   userID,
+		Status:      models.TaskStatusOpen,
+	}
+	if err := s.applyRequest(ctx, &task, userID, role, req); err != nil {
+		return models.Task{}, err
+	}
+
+	name, err := s.subjectName(ctx, task.SubjectType, task.SubjectID, userID, role)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.SubjectName = name
+
+	created, err := s.repo.Create(ctx, task)
+	if err != nil {
+		return models.Task{}, err
+	}
+	s.scheduleReminder(ctx, created)
+	return created, nil
+}
+
+// GetTasks lists tasks matching params; dealers only see their own
+func (s *TaskService) GetTasks(ctx context.Context, userID string, role string, params models.TaskQueryParams) ([]models.Task, error) {
+	if role != constants.Admin {
+		params.AssigneeID = &userID
+	}
+	return s.repo.GetTasks(ctx, params)
+}
+
+// GetOverdueTasks lists open tasks past their due time, most overdue first. Dealers only see
+// their own; admins see everyone's unless they filter by assignee_id.
+func (s *TaskService) GetOverdueTasks(ctx context.Context, userID string, role string, params models.TaskQueryParams) ([]models.Task, error) {
+	assigneeID := ""
+	if role != constants.Admin {
+		assigneeID = userID
+	} else if params.AssigneeID != nil {
+		assigneeID = *params.AssigneeID
+	}
+
+	params.SetDefaults()
+	return s.repo.GetOverdue(ctx, assigneeID, time.Now(), *params.Page, *params.Limit)
+}
+
+func (s *TaskService) GetTask(ctx context.Context, id string, userID string, role string) (models.Task, error) {
+	return s.getManagedTask(ctx, id, userID, role)
+}
+
+// UpdateTask edits an open task's title, notes, assignee and due time. The subject cannot change.
+func (s *TaskService) UpdateTask(ctx context.Context, id string, userID string, role string, req models.TaskRequest) (models.Task, error) {
+	task, err := s.getManagedTask(ctx, id, userID, role)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if task.Status != models.TaskStatusOpen {
+		return models.Task{}, ErrTaskClosed
+	}
+	if (req.SubjectType != "" && req.SubjectType != task.SubjectType) || (req.SubjectID != "" && req.SubjectID != task.SubjectID) {
+		return models.Task{}, fmt.Errorf("%w: the subject of a task cannot be changed", ErrTaskInvalid)
+	}
+	if err := s.applyRequest(ctx, &task, userID, role, req); err != nil {
+		return models.Task{}, err
+	}
+
+	updated, err := s.closedIfNoDocuments(s.repo.Update(ctx, task))
+	if err != nil {
+		return models.Task{}, err
+	}
+	s.scheduleReminder(ctx, updated)
+	return updated, nil
+}
+
+// SnoozeTask pushes an open task back to req.Until or by req.Minutes from now. The reminder keeps
+// the same lead time before the new due time.
+func (s *TaskService) SnoozeTask(ctx context.Context, id string, userID string, role string, req models.TaskSnoozeRequest) (models.Task, error) {
+	task, err := s.getManagedTask(ctx, id, userID, role)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if task.Status != models.TaskStatusOpen {
+		return models.Task{}, ErrTaskClosed
+	}
+
+	now := time.Now()
+	var dueAt time.Time
+	switch {
+	case req.Until != nil && req.Minutes != 0:
+		return models.Task{}, fmt.Errorf("%w: give either until or minutes, not both", ErrTaskInvalid)
+	case req.Until != nil:
+		dueAt = *req.Until
+	case req.Minutes > 0:
+		dueAt = now.Add(time.Duration(req.Minutes) * time.Minute)
+	default:
+		return models.Task{}, fmt.Errorf("%w: until or a positive number of minutes is required", ErrTaskInvalid)
+	}
+	if !dueAt.After(now) {
+		return models.Task{}, fmt.Errorf("%w: a task can only be snoozed into the future", ErrTaskInvalid)
+	}
+	if dueAt.Sub(now) > maxSnooze {
+		return models.Task{}, fmt.Errorf("%w: a task can be snoozed by at most 30 days", ErrTaskInvalid)
+	}
+
+	remindAt := dueAt.Add(-task.DueAt.Sub(task.RemindAt))
+	if remindAt.Before(now) {
+		remindAt = now
+	}
+
+	snoozed, err := s.closedIfNoDocuments(s.repo.Snooze(ctx, id, dueAt, remindAt))
+	if err != nil {
+		return models.Task{}, err
+	}
+	s.scheduleReminder(ctx, snoozed)
+	return snoozed, nil
+}
+
+func (s *TaskService) CompleteTask(ctx context.Context, id string, userID string, role string, outcome string) (models.Task, error) {
+	task, err := s.getManagedTask(ctx, id, userID, role)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if task.Status != models.TaskStatusOpen {
+		return models.Task{}, ErrTaskClosed
+	}
+	outcome = strings.TrimSpace(outcome)
+	if len(outcome) > maxTaskOutcomeLength {
+		return models.Task{}, fmt.Errorf("%w: outcome must be at most %d characters", ErrTaskInvalid, maxTaskOutcomeLength)
+	}
+
+	completed, err := s.closedIfNoDocuments(s.repo.Complete(ctx, id, userID, outcome))
+	if err != nil {
+		return models.Task{}, err
+	}
+	s.unscheduleReminder(ctx, completed.ID)
+	return completed, nil
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, id string, userID string, role string) error {
+	if _, err := s.getManagedTask(ctx, id, userID, role); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrTaskNotFound
+		}
+		return err
+	}
+	s.unscheduleReminder(ctx, id)
+	return nil
+}
+
+// RunReminderScheduler calls SendDueReminders every interval until ctx is cancelled
+func (s *TaskService) RunReminderScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.SendDueReminders(ctx)
+			if err != nil {
+				log.Printf("⚠️  Task reminders failed: %v", err)
+			} else if sent > 0 {
+				log.Printf("task reminders sent %d notifications", sent)
+			}
+		}
+	}
+}
+
+// SendDueReminders notifies the assignees of tasks whose reminder has come due and returns how
+// many were sent. Each due task is taken off the Redis queue with ZREM, and only the instance
+// whose ZREM removed it goes on; the conditional update in MarkReminded then guards against a
+// reminder that was re-queued or found through Mongo in the meantime.
+func (s *TaskService) SendDueReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+	if s.redisClient == nil {
+		return s.sendDueRemindersFromMongo(ctx, now)
+	}
+
+	ids, err := s.redisClient.ZRangeByScore(ctx, taskRemindersKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: reminderBatchSize,
+	}).Result()
+	if err != nil {
+		log.Printf("⚠️  Failed to read task reminders from Redis, falling back to Mongo: %v", err)
+		return s.sendDueRemindersFromMongo(ctx, now)
+	}
+
+	sent := 0
+	for _, id := range ids {
+		claimed, err := s.redisClient.ZRem(ctx, taskRemindersKey, id).Result()
+		if err != nil {
+			return sent, err
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		task, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+				continue
+			}
+			// Put it back so a later tick retries
+			s.scheduleReminder(ctx, models.Task{ID: id, Status: models.TaskStatusOpen, RemindAt: now})
+			return sent, err
+		}
+		if task.RemindAt.After(now) {
+			// Snoozed between the read and the claim
+			s.scheduleReminder(ctx, task)
+			continue
+		}
+		if s.remind(ctx, task) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// RestoreReminders queues every pending reminder in Redis, in case Redis lost them
+func (s *TaskService) RestoreReminders(ctx context.Context) error {
+	if s.redisClient == nil {
+		return nil
+	}
+	tasks, err := s.repo.GetPendingReminders(ctx)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	members := make([]*redis.Z, len(tasks))
+	for i, task := range tasks {
+		members[i] = &redis.Z{Score: float64(task.RemindAt.Unix()), Member: task.ID}
+	}
+	return s.redisClient.ZAdd(ctx, taskRemindersKey, members...).Err()
+}
+
+func (s *TaskService) sendDueRemindersFromMongo(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := s.repo.GetDueReminders(ctx, now, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, task := range tasks {
+		if s.remind(ctx, task) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// remind marks the task reminded and notifies its assignee. It reports false when the task was
+// completed, snoozed or reminded by another instance in the meantime. The mark claims the
+// reminder for this instance; if the notification cannot be sent the mark is cleared and the
+// reminder queued again.
+func (s *TaskService) remind(ctx context.Context, task models.Task) bool {
+	if task.Status != models.TaskStatusOpen || task.RemindedAt != nil {
+		return false
+	}
+	marked, err := s.repo.MarkReminded(ctx, task.ID, task.RemindAt)
+	if err != nil {
+		log.Printf("⚠️  Failed to mark task %s reminded: %v", task.ID, err)
+		return false
+	}
+	if !marked || s.notifications == nil {
+		return marked
+	}
+
+	due := "Due " + task.DueAt.In(statsZone).Format(taskDueLayout)
+	if task.DueAt.Before(time.Now()) {
+		due = "Was due " + task.DueAt.In(statsZone).Format(taskDueLayout)
+	}
+	body := due
+	if task.SubjectName != "" {
+		body = task.SubjectName + " - " + due
+	}
+
+	_, err = s.notifications.Notify(ctx, models.Notification{
+		RecipientID: task.AssigneeID,
+		Type:        models.NotificationTaskReminder,
+		Title:       "Reminder: " + task.Title,
+		Body:        body,
+		Data: map[string]string{
+			"task_id":      task.ID,
+			"subject_type": task.SubjectType,
+			"subject_id":   task.SubjectID,
+		},
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to send reminder for task %s to %s, will retry: %v", task.ID, task.AssigneeID, err)
+		if err := s.repo.ClearReminded(ctx, task.ID, task.RemindAt); err != nil {
+			log.Printf("⚠️  Failed to re-queue reminder for task %s: %v", task.ID, err)
+			return false
+		}
+		// Redis is scored later so the retry waits; the stored remind_at is unchanged, so the task
+		// is still due when it comes back round
+		retry := task
+		retry.RemindAt = time.Now().Add(reminderRetryDelay)
+		s.scheduleReminder(ctx, retry)
+		return false
+	}
+	return true
+}
+
+// scheduleReminder queues the task's reminder, or drops it when there is nothing left to remind
+func (s *TaskService) scheduleReminder(ctx context.Context, task models.Task) {
+	if s.redisClient == nil {
+		return
+	}
+	if task.Status != models.TaskStatusOpen || task.RemindedAt != nil {
+		s.unscheduleReminder(ctx, task.ID)
+		return
+	}
+	member := &redis.Z{Score: float64(task.RemindAt.Unix()), Member: task.ID}
+	if err := s.redisClient.ZAdd(ctx, taskRemindersKey, member).Err(); err != nil {
+		log.Printf("⚠️  Failed to queue reminder for task %s: %v", task.ID, err)
+	}
+}
+
+func (s *TaskService) unscheduleReminder(ctx context.Context, id string) {
+	if s.redisClient == nil {
+		return
+	}
+	if err := s.redisClient.ZRem(ctx, taskRemindersKey, id).Err(); err != nil {
+		log.Printf("⚠️  Failed to drop reminder for task %s: %v", id, err)
+	}
+}
+
+// applyRequest validates the editable fields of req and copies them onto task
+func (s *TaskService) applyRequest(ctx context.Context, task *models.Task, userID string, role string, req models.TaskRequest) error {
+	title := strings.TrimSpace(req.Title)
+	notes := strings.TrimSpace(req.Notes)
+	switch {
+	case title == "":
+		return fmt.Errorf("%w: title is required", ErrTaskInvalid)
+	case len(title) > maxTaskTitleLength:
+		return fmt.Errorf("%w: title must be at most %d characters", ErrTaskInvalid, maxTaskTitleLength)
+	case len(notes) > maxTaskNotesLength:
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrTaskInvalid, maxTaskNotesLength)
+	}
+
+	now := time.Now()
+	if req.DueAt.IsZero() {
+		return fmt.Errorf("%w: due_at is required", ErrTaskInvalid)
+	}
+	if !req.DueAt.After(now) {
+		return fmt.Errorf("%w: due_at must be in the future", ErrTaskInvalid)
+	}
+	remindBefore := time.Duration(req.RemindMinutesBefore) * time.Minute
+	if remindBefore < 0 || remindBefore > maxRemindBefore {
+		return fmt.Errorf("%w: remind_minutes_before must be between 0 and %d", ErrTaskInvalid, int(maxRemindBefore.Minutes()))
+	}
+	remindAt := req.DueAt.Add(-remindBefore)
+	if remindAt.Before(now) {
+		remindAt = now
+	}
+
+	assigneeID, err := s.assignee(ctx, strings.TrimSpace(req.AssigneeID), userID, role)
+	if err != nil {
+		return err
+	}
+
+	task.Title, task.Notes, task.AssigneeID = title, notes, assigneeID
+	task.DueAt, task.RemindAt, task.RemindedAt = req.DueAt, remindAt, nil
+	return nil
+}
+
+// assignee resolves who a task is for. It defaults to the caller; only admins may name someone
+// else, and then only an active dealer.
+func (s *TaskService) assignee(ctx context.Context, assigneeID string, userID string, role string) (string, error) {
+	if assigneeID == "" || assigneeID == userID {
+		return userID, nil
+	}
+	if role != constants.Admin {
+		return "", fmt.Errorf("%w: dealers can only assign tasks to themselves", ErrTaskInvalid)
+	}
+
+	dealer, err := s.dealerRepo.GetByID(ctx, assigneeID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return "", fmt.Errorf("%w: assignee_id must be an active dealer", ErrTaskInvalid)
+		}
+		return "", err
+	}
+	if dealer.DeactivatedAt != nil {
+		return "", fmt.Errorf("%w: assignee_id must be an active dealer", ErrTaskInvalid)
+	}
+	return dealer.ID, nil
+}
+
+// subjectName checks the task's subject exists and the caller may work on it, and returns a
+// name to show with the task
+func (s *TaskService) subjectName(ctx context.Context, subjectType string, subjectID string, userID string, role string) (string, error) {
+	if _, err := primitive.ObjectIDFromHex(subjectID); err != nil {
+		return "", fmt.Errorf("%w: subject_id must be a valid ID", ErrTaskInvalid)
+	}
+
+	switch subjectType {
+	case models.TaskSubjectLead:
+		lead, err := s.leadRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			return "", err
+		}
+		if role != constants.Admin {
+			visible, err := dealerCanSeeLead(ctx, s.leadRepo, lead, userID)
+			if err != nil {
+				return "", err
+			}
+			if !visible {
+				return "", fmt.Errorf("%w: lead is not assigned to you or interested in your properties", ErrTaskInvalid)
+			}
+		}
+		return lead.Name, nil
+	case models.TaskSubjectDealerClient:
+		client, err := s.dealerClientRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			return "", err
+		}
+		if role != constants.Admin && client.DealerID != userID {
+			return "", ErrDealerClientNotOwned
+		}
+		return client.Name, nil
+	case models.TaskSubjectProperty:
+		property, err := s.propertyRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			return "", err
+		}
+		if role != constants.Admin && property.DealerID != userID {
+			return "", ErrPropertyNotOwned
+		}
+		return fmt.Sprintf("#%d %s", property.PropertyNumber, property.Title), nil
+	}
+	return "", fmt.Errorf("%w: subject_type must be lead, dealer_client or property", ErrTaskInvalid)
+}
+
+// getManagedTask loads a task the caller may manage: their own, or any for admins
+func (s *TaskService) getManagedTask(ctx context.Context, id string, userID string, role string) (models.Task, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.Task{}, ErrTaskNotFound
+		}
+		return models.Task{}, err
+	}
+	if role != constants.Admin && task.AssigneeID != userID {
+		return models.Task{}, ErrTaskNotOwned
+	}
+	return task, nil
+}
+
+// closedIfNoDocuments reports a conditional update that matched nothing as the task having been completed
+func (s *TaskService) closedIfNoDocuments(task models.Task, err error) (models.Task, error) {
+	if err == mongo.ErrNoDocuments {
+		return models.Task{}, ErrTaskClosed
+	}
+	return task, err
+}
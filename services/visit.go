@@ -0,0 +1,422 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"myapp/constants"
+	"myapp/models"
+	"myapp/repositories"
+	"myapp/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrVisitInvalid        = errors.New("invalid visit")
+	ErrVisitNotFound       = errors.New("visit not found")
+	ErrVisitNotOwned       = errors.New("visit belongs to another dealer")
+	ErrVisitClosed         = errors.New("visit has already been cancelled or completed")
+	ErrVisitConflict       = errors.New("time slot clashes with another visit")
+	ErrCalendarFeedInvalid = errors.New("calendar feed link is invalid")
+)
+
+const (
+	defaultVisitDuration = 30 * time.Minute
+	minVisitDuration     = 15 * time.Minute
+	maxVisitDuration     = 8 * time.Hour
+	calendarFeedPast     = 30 * 24 * time.Hour
+	calendarFeedAhead    = 180 * 24 * time.Hour
+)
+
+// VisitConflictError lists the visits a requested slot overlaps. It matches ErrVisitConflict.
+type VisitConflictError struct {
+	Conflicts []models.Visit
+}
+
+func (e *VisitConflictError) Error() string {
+	return ErrVisitConflict.Error()
+}
+
+func (e *VisitConflictError) Unwrap() error {
+	return ErrVisitConflict
+}
+
+type VisitService struct {
+	repo             repositories.VisitRepository
+	propertyRepo     repositories.PropertyRepository
+	leadRepo         repositories.LeadRepository
+	dealerClientRepo repositories.DealerClientRepository
+	dealerRepo       repositories.DealerRepository
+	feedSecret       []byte
+}
+
+// NewVisitService signs calendar feed links with feedSecret
+func NewVisitService(repo repositories.VisitRepository, propertyRepo repositories.PropertyRepository, leadRepo repositories.LeadRepository, dealerClientRepo repositories.DealerClientRepository, dealerRepo repositories.DealerRepository, feedSecret string) *VisitService {
+	return &VisitService{
+		repo:             repo,
+		propertyRepo:     propertyRepo,
+		leadRepo:         leadRepo,
+		dealerClientRepo: dealerClientRepo,
+		dealerRepo:       dealerRepo,
+		feedSecret:       []byte(feedSecret),
+	}
+}
+
+// ScheduleVisit books a visit to a property. Dealers can only book their own listings, with their
+// own clients or leads interested in their listings; admins can book any.
+func (s *VisitService) ScheduleVisit(ctx context.Context, userID string, role string, req models.VisitRequest) (models.Visit, error) {
+	if (req.LeadID == "") == (req.DealerClientID == "") {
+		return models.Visit{}, fmt.Errorf("%w: exactly one of lead_id and dealer_client_id is required", ErrVisitInvalid)
+	}
+	startsAt, endsAt, err := visitSlot(req)
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, req.PropertyID)
+	if err != nil {
+		return models.Visit{}, err
+	}
+	if !canManageVisits(property.DealerID, userID, role) {
+		return models.Visit{}, ErrPropertyNotOwned
+	}
+
+	visit := models.Visit{
+		DealerID:       property.DealerID,
+		PropertyID:     property.ID,
+		PropertyNumber: property.PropertyNumber,
+		AgentName:      strings.TrimSpace(req.AgentName),
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		Status:         models.VisitStatusScheduled,
+		Note:           strings.TrimSpace(req.Note),
+		CreatedBy:      userID,
+	}
+
+	if req.LeadID != "" {
+		lead, err := s.getVisitLead(ctx, req.LeadID, property.DealerID, role)
+		if err != nil {
+			return models.Visit{}, err
+		}
+		visit.LeadID, visit.ClientName, visit.ClientPhone = lead.ID, lead.Name, lead.Phone
+	} else {
+		client, err := s.dealerClientRepo.GetByID(ctx, req.DealerClientID)
+		if err != nil {
+			return models.Visit{}, err
+		}
+		if client.DealerID != property.DealerID {
+			return models.Visit{}, ErrDealerClientNotOwned
+		}
+		visit.DealerClientID, visit.ClientName, visit.ClientPhone = client.ID, client.Name, client.Phone
+	}
+
+	visit.History = []models.VisitEvent{{
+		Status:    visit.Status,
+		StartsAt:  visit.StartsAt,
+		EndsAt:    visit.EndsAt,
+		Note:      visit.Note,
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+	}}
+	created, conflicts, err := s.repo.Create(ctx, visit)
+	if len(conflicts) > 0 {
+		return models.Visit{}, &VisitConflictError{Conflicts: conflicts}
+	}
+	return created, err
+}
+
+// GetVisits lists visits matching params; dealers only see their own
+func (s *VisitService) GetVisits(ctx context.Context, userID string, role string, params models.VisitQueryParams) ([]models.Visit, error) {
+	if role != constants.Admin {
+		params.DealerID = &userID
+	}
+	return s.repo.GetVisits(ctx, params)
+}
+
+func (s *VisitService) GetVisit(ctx context.Context, id string, userID string, role string) (models.Visit, error) {
+	return s.getManagedVisit(ctx, id, userID, role)
+}
+
+// RescheduleVisit moves an active visit to a new slot, keeping the old one in its history
+func (s *VisitService) RescheduleVisit(ctx context.Context, id string, userID string, role string, req models.VisitRequest) (models.Visit, error) {
+	visit, err := s.getManagedVisit(ctx, id, userID, role)
+	if err != nil {
+		return models.Visit{}, err
+	}
+	if !isActiveVisit(visit) {
+		return models.Visit{}, ErrVisitClosed
+	}
+
+	startsAt, endsAt, err := visitSlot(req)
+	if err != nil {
+		return models.Visit{}, err
+	}
+
+	rescheduled, conflicts, err := s.repo.Reschedule(ctx, visit, models.VisitEvent{
+		Status:    models.VisitStatusRescheduled,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Note:      strings.TrimSpace(req.Note),
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+	})
+	if len(conflicts) > 0 {
+		return models.Visit{}, &VisitConflictError{Conflicts: conflicts}
+	}
+	return s.closedIfNoDocuments(rescheduled, err)
+}
+
+func (s *VisitService) CancelVisit(ctx context.Context, id string, userID string, role string, reason string) (models.Visit, error) {
+	visit, err := s.getManagedVisit(ctx, id, userID, role)
+	if err != nil {
+		return models.Visit{}, err
+	}
+	if !isActiveVisit(visit) {
+		return models.Visit{}, ErrVisitClosed
+	}
+
+	reason = strings.TrimSpace(reason)
+	return s.closedIfNoDocuments(s.repo.Cancel(ctx, id, reason, models.VisitEvent{
+		Status:    models.VisitStatusCancelled,
+		StartsAt:  visit.StartsAt,
+		EndsAt:    visit.EndsAt,
+		Note:      reason,
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+	}))
+}
+
+// CompleteVisit closes a visit that has taken place with a note on how it went
+func (s *VisitService) CompleteVisit(ctx context.Context, id string, userID string, role string, outcomeNote string) (models.Visit, error) {
+	visit, err := s.getManagedVisit(ctx, id, userID, role)
+	if err != nil {
+		return models.Visit{}, err
+	}
+	if !isActiveVisit(visit) {
+		return models.Visit{}, ErrVisitClosed
+	}
+	if time.Now().Before(visit.StartsAt) {
+		return models.Visit{}, fmt.Errorf("%w: a visit cannot be completed before it starts", ErrVisitInvalid)
+	}
+	outcomeNote = strings.TrimSpace(outcomeNote)
+	if outcomeNote == "" {
+		return models.Visit{}, fmt.Errorf("%w: outcome_note is required", ErrVisitInvalid)
+	}
+
+	return s.closedIfNoDocuments(s.repo.Complete(ctx, id, outcomeNote, models.VisitEvent{
+		Status:    models.VisitStatusCompleted,
+		StartsAt:  visit.StartsAt,
+		EndsAt:    visit.EndsAt,
+		Note:      outcomeNote,
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+	}))
+}
+
+// CalendarFeedToken is "<dealer id>.<version>.<signature>" for the dealer's current feed version
+func (s *VisitService) CalendarFeedToken(ctx context.Context, dealerID string) (string, error) {
+	version, err := s.repo.GetCalendarFeedVersion(ctx, dealerID)
+	if err != nil {
+		return "", err
+	}
+	return s.feedToken(dealerID, version), nil
+}
+
+// RotateCalendarFeed issues a new feed token; every earlier link for the dealer stops working.
+// Rotating the feed secret revokes the links of every dealer at once.
+func (s *VisitService) RotateCalendarFeed(ctx context.Context, dealerID string) (string, error) {
+	version, err := s.repo.RotateCalendarFeed(ctx, dealerID)
+	if err != nil {
+		return "", err
+	}
+	return s.feedToken(dealerID, version), nil
+}
+
+// CalendarFeed renders a dealer's visits from the last month and the next six as an ICS calendar.
+// Anyone holding the link can read it, so events carry no client details or free-text notes.
+func (s *VisitService) CalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrCalendarFeedInvalid
+	}
+	dealerID := parts[0]
+	version, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !hmac.Equal([]byte(token), []byte(s.feedToken(dealerID, version))) {
+		return nil, ErrCalendarFeedInvalid
+	}
+	current, err := s.repo.GetCalendarFeedVersion(ctx, dealerID)
+	if err != nil {
+		return nil, err
+	}
+	if version != current {
+		return nil, ErrCalendarFeedInvalid
+	}
+
+	dealer, err := s.dealerRepo.GetByID(ctx, dealerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCalendarFeedInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	visits, err := s.repo.GetByDealerBetween(ctx, dealerID, now.Add(-calendarFeedPast), now.Add(calendarFeedAhead))
+	if err != nil {
+		return nil, err
+	}
+	properties, err := s.visitProperties(ctx, visits)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]utils.ICSEvent, len(visits))
+	for i, visit := range visits {
+		property := properties[visit.PropertyID]
+		event := utils.ICSEvent{
+			UID:      visit.ID + "@visits",
+			Summary:  fmt.Sprintf("Site visit: #%d %s", visit.PropertyNumber, property.Title),
+			Location: property.Address,
+			Start:    visit.StartsAt,
+			End:      visit.EndsAt,
+			Stamp:    visit.UpdatedAt,
+			Status:   "CONFIRMED",
+			Sequence: len(visit.History) - 1,
+		}
+		if visit.Status == models.VisitStatusCancelled {
+			event.Status = "CANCELLED"
+		}
+
+		details := []string{"Status: " + visit.Status}
+		if visit.AgentName != "" {
+			details = append(details, "Agent: "+visit.AgentName)
+		}
+		details = append(details, "Client details and notes are in the app (visit "+visit.ID+")")
+		event.Description = strings.Join(details, "\n")
+		events[i] = event
+	}
+
+	name := dealer.ShopName
+	if name == "" {
+		name = dealer.Name
+	}
+	var buf bytes.Buffer
+	if err := utils.WriteICS(&buf, name+" site visits", events); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *VisitService) getManagedVisit(ctx context.Context, id string, userID string, role string) (models.Visit, error) {
+	visit, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments || errors.Is(err, primitive.ErrInvalidHex) {
+			return models.Visit{}, ErrVisitNotFound
+		}
+		return models.Visit{}, err
+	}
+	if !canManageVisits(visit.DealerID, userID, role) {
+		return models.Visit{}, ErrVisitNotOwned
+	}
+	return visit, nil
+}
+
+// getVisitLead loads a lead; a dealer may only use leads interested in one of their listings
+func (s *VisitService) getVisitLead(ctx context.Context, leadID string, dealerID string, role string) (models.Lead, error) {
+	if role == constants.Admin {
+		return s.leadRepo.GetByID(ctx, leadID)
+	}
+
+	params := models.LeadQueryParams{ID: &leadID, DealerID: &dealerID}
+	params.AddArrayFilter("dealer_id")
+	leads, err := s.leadRepo.GetLeads(ctx, params)
+	if err != nil {
+		return models.Lead{}, err
+	}
+	if len(leads) == 0 {
+		return models.Lead{}, fmt.Errorf("%w: lead is not interested in any of your properties", ErrVisitInvalid)
+	}
+	return leads[0], nil
+}
+
+func (s *VisitService) visitProperties(ctx context.Context, visits []models.Visit) (map[string]models.Property, error) {
+	seen := make(map[string]bool)
+	var objectIDs []primitive.ObjectID
+	for _, visit := range visits {
+		if seen[visit.PropertyID] {
+			continue
+		}
+		seen[visit.PropertyID] = true
+		if objectID, err := primitive.ObjectIDFromHex(visit.PropertyID); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	result := make(map[string]models.Property, len(objectIDs))
+	if len(objectIDs) == 0 {
+		return result, nil
+	}
+	properties, err := s.propertyRepo.GetFilteredProperties(ctx, bson.M{"_id": bson.M{"$in": objectIDs}},
+		bson.M{"title": 1, "address": 1}, int64(len(objectIDs)), 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range properties {
+		result[property.ID] = property
+	}
+	return result, nil
+}
+
+// closedIfNoDocuments maps a lost race, where the visit was closed between the read and the
+// update, to ErrVisitClosed
+func (s *VisitService) closedIfNoDocuments(visit models.Visit, err error) (models.Visit, error) {
+	if err == mongo.ErrNoDocuments {
+		return models.Visit{}, ErrVisitClosed
+	}
+	return visit, err
+}
+
+func (s *VisitService) feedToken(dealerID string, version int64) string {
+	payload := dealerID + "." + strconv.FormatInt(version, 10)
+	mac := hmac.New(sha256.New, s.feedSecret)
+	mac.Write([]byte("calendar:" + payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:18])
+}
+
+func visitSlot(req models.VisitRequest) (time.Time, time.Time, error) {
+	if req.StartsAt.IsZero() {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: starts_at is required", ErrVisitInvalid)
+	}
+	if req.StartsAt.Before(time.Now()) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: starts_at must be in the future", ErrVisitInvalid)
+	}
+
+	duration := defaultVisitDuration
+	if req.DurationMinutes != 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration < minVisitDuration || duration > maxVisitDuration {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: duration_minutes must be between %d and %d",
+			ErrVisitInvalid, int(minVisitDuration.Minutes()), int(maxVisitDuration.Minutes()))
+	}
+	return req.StartsAt, req.StartsAt.Add(duration), nil
+}
+
+func canManageVisits(dealerID string, userID string, role string) bool {
+	return role == constants.Admin || (role == constants.Dealer && dealerID == userID)
+}
+
+func isActiveVisit(visit models.Visit) bool {
+	return visit.Status == models.VisitStatusScheduled || visit.Status == models.VisitStatusRescheduled
+}
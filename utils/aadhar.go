@@ -0,0 +1,73 @@
+package utils
+
+import "strings"
+
+// Verhoeff tables: multiplication in the dihedral group D5, the position permutation and inverses
+var (
+	verhoeffMultiply = [10][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffPermute = [8][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+)
+
+// NormalizeAadhar strips the spaces and hyphens people write Aadhaar numbers with. It returns ""
+// unless exactly 12 digits remain.
+func NormalizeAadhar(number string) string {
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-':
+		default:
+			return ""
+		}
+	}
+	if digits.Len() != 12 {
+		return ""
+	}
+	return digits.String()
+}
+
+// ValidAadhar reports whether number, as returned by NormalizeAadhar, could be issued: it does not
+// start with 0 or 1 and its last digit is the Verhoeff check digit of the rest
+func ValidAadhar(number string) bool {
+	if len(number) != 12 || number[0] == '0' || number[0] == '1' {
+		return false
+	}
+	check := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		check = verhoeffMultiply[check][verhoeffPermute[(len(number)-1-i)%8][digit]]
+	}
+	return check == 0
+}
+
+// MaskAadhar shows only the last four digits, as XXXX-XXXX-1234
+func MaskAadhar(last4 string) string {
+	if last4 == "" {
+		return ""
+	}
+	return "XXXX-XXXX-" + last4
+}
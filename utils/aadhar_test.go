@@ -0,0 +1,57 @@
+package utils
+
+import "testing"
+
+func TestNormalizeAadhar(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{"234123412346", "234123412346"},
+		{"2341 2341 2346", "234123412346"},
+		{"2341-2341-2346", "234123412346"},
+		{"23412341234", ""},
+		{"2341234123467", ""},
+		{"2341.2341.2346", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := NormalizeAadhar(tt.number); got != tt.want {
+				t.Errorf("NormalizeAadhar(%q) = %q, want %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidAadhar(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"234123412346", true},
+		{"499999999993", true},
+		{"234123412345", false},
+		{"499999999994", false},
+		{"034123412346", false},
+		{"134123412346", false},
+		{"23412341234", false},
+		{"2341234123a6", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := ValidAadhar(tt.number); got != tt.want {
+				t.Errorf("ValidAadhar(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskAadhar(t *testing.T) {
+	if got := MaskAadhar("2346"); got != "XXXX-XXXX-2346" {
+		t.Errorf("MaskAadhar = %q, want XXXX-XXXX-2346", got)
+	}
+	if got := MaskAadhar(""); got != "" {
+		t.Errorf("MaskAadhar of no digits = %q, want empty", got)
+	}
+}
@@ -138,15 +138,35 @@ func handleArrayFieldWithOperator(mongoFilter bson.M, fieldName string, operator
 		mongoFilter[arrayField] = bson.M{"$all": value}
 	case "$not":
 		mongoFilter[arrayField] = bson.M{"$not": value}
-	case "$exists", "$size", "$type":
-		mongoFilter[arrayField] = bson.M{"$elemMatch": bson.M{nestedField: bson.M{operator: value}}}
 	case "":
-		mongoFilter[arrayField] = bson.M{"$elemMatch": bson.M{nestedField: value}}
+		addElemMatchCondition(mongoFilter, arrayField, nestedField, value)
 	default:
-		mongoFilter[arrayField] = bson.M{"$elemMatch": bson.M{nestedField: bson.M{operator: value}}}
+		addElemMatchCondition(mongoFilter, arrayField, nestedField, bson.M{operator: value})
 	}
 }
 
+// addElemMatchCondition adds a condition on a field of the array's elements. Filters on the same
+// array share one $elemMatch, so they must all hold for the same element, and operators on the
+// same field combine into a range.
+func addElemMatchCondition(mongoFilter bson.M, arrayField string, nestedField string, condition interface{}) {
+	existing, _ := mongoFilter[arrayField].(bson.M)
+	elemMatch, ok := existing["$elemMatch"].(bson.M)
+	if !ok {
+		mongoFilter[arrayField] = bson.M{"$elemMatch": bson.M{nestedField: condition}}
+		return
+	}
+
+	if operators, ok := condition.(bson.M); ok {
+		if current, ok := elemMatch[nestedField].(bson.M); ok {
+			for operator, value := range operators {
+				current[operator] = value
+			}
+			return
+		}
+	}
+	elemMatch[nestedField] = condition
+}
+
 
 
 func applyMongoConversion(value interface{}, convertType string) interface{} {
@@ -163,6 +183,16 @@ func applyMongoConversion(value interface{}, convertType string) interface{} {
 				return date
 			}
 		}
+	case "csv":
+		if str, ok := value.(string); ok {
+			items := []string{}
+			for _, item := range strings.Split(str, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items
+		}
 	}
 	return value
 }
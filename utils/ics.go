@@ -0,0 +1,92 @@
+// utils/ics.go
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICSEvent is one VEVENT of an iCalendar (RFC 5545) feed
+type ICSEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Status      string // CONFIRMED, TENTATIVE or CANCELLED
+	Sequence    int
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+// WriteICS writes events as a published calendar that subscribing apps refresh periodically
+func WriteICS(w io.Writer, calendarName string, events []ICSEvent) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//myapp//Site visits//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icsEscape(calendarName),
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+icsEscape(event.UID),
+			"DTSTAMP:"+event.Stamp.UTC().Format(icsTimeFormat),
+			"DTSTART:"+event.Start.UTC().Format(icsTimeFormat),
+			"DTEND:"+event.End.UTC().Format(icsTimeFormat),
+			"SEQUENCE:"+fmt.Sprint(event.Sequence),
+			"SUMMARY:"+icsEscape(event.Summary),
+		)
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icsEscape(event.Description))
+		}
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+icsEscape(event.Location))
+		}
+		if event.Status != "" {
+			lines = append(lines, "STATUS:"+event.Status)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, icsFold(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func icsEscape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// icsFold splits a content line into 75-octet pieces without breaking a UTF-8 sequence
+func icsFold(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,266 @@
+// utils/pdf.go
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"strings"
+)
+
+// A4 in points
+const (
+	PDFPageWidth  = 595.0
+	PDFPageHeight = 842.0
+)
+
+// PDFDocument builds a single-page A4 PDF. Text uses the standard Helvetica fonts every viewer
+// ships, so nothing is embedded except JPEG images, which PDF can carry as-is.
+// Coordinates are in points measured from the top-left corner of the page.
+type PDFDocument struct {
+	title   string
+	content bytes.Buffer
+	images  []pdfImage
+}
+
+type pdfImage struct {
+	data       []byte
+	width      int
+	height     int
+	colorSpace string
+}
+
+func NewPDFDocument(title string) *PDFDocument {
+	return &PDFDocument{title: title}
+}
+
+// SetFillColor sets the colour used by Rect and Text
+func (d *PDFDocument) SetFillColor(r, g, b uint8) {
+	fmt.Fprintf(&d.content, "%s %s %s rg\n", pdfColor(r), pdfColor(g), pdfColor(b))
+}
+
+// SetStrokeColor sets the colour used by Line
+func (d *PDFDocument) SetStrokeColor(r, g, b uint8) {
+	fmt.Fprintf(&d.content, "%s %s %s RG\n", pdfColor(r), pdfColor(g), pdfColor(b))
+}
+
+// Rect fills a rectangle whose top-left corner is at x, y
+func (d *PDFDocument) Rect(x, y, w, h float64) {
+	fmt.Fprintf(&d.content, "%s %s %s %s re f\n", pdfNumber(x), pdfNumber(PDFPageHeight-y-h), pdfNumber(w), pdfNumber(h))
+}
+
+// Line strokes a line of the given width between two points
+func (d *PDFDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&d.content, "%s w %s %s m %s %s l S\n", pdfNumber(width),
+		pdfNumber(x1), pdfNumber(PDFPageHeight-y1), pdfNumber(x2), pdfNumber(PDFPageHeight-y2))
+}
+
+// Text draws a single line with its baseline at y
+func (d *PDFDocument) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&d.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNumber(size),
+		pdfNumber(x), pdfNumber(PDFPageHeight-y), pdfEscape(ToWinAnsi(text)))
+}
+
+// JPEG draws a JPEG scaled to fit inside the box at x, y, keeping its aspect ratio and centred
+func (d *PDFDocument) JPEG(data []byte, x, y, w, h float64) error {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if format != "jpeg" {
+		return fmt.Errorf("expected a JPEG image, got %s", format)
+	}
+
+	colorSpace := "/DeviceRGB"
+	switch config.ColorModel {
+	case color.GrayModel:
+		colorSpace = "/DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "/DeviceCMYK"
+	}
+	d.images = append(d.images, pdfImage{data: data, width: config.Width, height: config.Height, colorSpace: colorSpace})
+
+	scale := w / float64(config.Width)
+	if hs := h / float64(config.Height); hs < scale {
+		scale = hs
+	}
+	drawW, drawH := float64(config.Width)*scale, float64(config.Height)*scale
+	drawX, drawY := x+(w-drawW)/2, y+(h-drawH)/2
+
+	fmt.Fprintf(&d.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n", pdfNumber(drawW), pdfNumber(drawH),
+		pdfNumber(drawX), pdfNumber(PDFPageHeight-drawY-drawH), len(d.images))
+	return nil
+}
+
+// Bytes serialises the document
+func (d *PDFDocument) Bytes() ([]byte, error) {
+	var stream bytes.Buffer
+	zw := zlib.NewWriter(&stream)
+	if _, err := zw.Write(d.content.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	// 1 catalog, 2 pages, 3 page, 4-5 fonts, 6 info, then images, then the content stream
+	imageBase := 7
+	contentID := imageBase + len(d.images)
+
+	var xobjects strings.Builder
+	for i := range d.images {
+		fmt.Fprintf(&xobjects, "/Im%d %d 0 R ", i+1, imageBase+i)
+	}
+
+	var out bytes.Buffer
+	offsets := make([]int, contentID+1)
+	object := func(id int, body string, data []byte) {
+		offsets[id] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", id, body)
+		if data != nil {
+			out.WriteString("stream\n")
+			out.Write(data)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object(1, "<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>", nil)
+	object(3, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> /XObject << %s>> >> /Contents %d 0 R >>",
+		pdfNumber(PDFPageWidth), pdfNumber(PDFPageHeight), xobjects.String(), contentID), nil)
+	object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	object(5, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+	object(6, fmt.Sprintf("<< /Title (%s) /Producer (myapp) >>", pdfEscape(ToWinAnsi(d.title))), nil)
+	for i, img := range d.images {
+		object(imageBase+i, fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.width, img.height, img.colorSpace, len(img.data)), img.data)
+	}
+	object(contentID, fmt.Sprintf("<< /Filter /FlateDecode /Length %d >>", stream.Len()), stream.Bytes())
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", contentID+1)
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", contentID+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// PDFTextWidth measures text in points as Text would draw it
+func PDFTextWidth(text string, size float64, bold bool) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range []byte(ToWinAnsi(text)) {
+		if c >= 32 && c <= 126 {
+			total += int(widths[c-32])
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapPDFText breaks text into lines no wider than maxWidth. At most maxLines are returned,
+// the last one ending in "..." if text was cut short; maxLines <= 0 means no limit.
+func WrapPDFText(text string, size float64, bold bool, maxWidth float64, maxLines int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current == "" || PDFTextWidth(candidate, size, bold) <= maxWidth {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && PDFTextWidth(string(last)+"...", size, bold) > maxWidth {
+			last = last[:len(last)-1]
+		}
+		lines[maxLines-1] = strings.TrimRight(string(last), " ") + "..."
+	}
+	return lines
+}
+
+// ToWinAnsi maps text onto the single-byte encoding the standard fonts use. Latin-1 passes
+// through, common typographic punctuation is simplified and anything else becomes "?".
+func ToWinAnsi(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			b.WriteByte(byte(r))
+		case r == '‘' || r == '’':
+			b.WriteByte('\'')
+		case r == '“' || r == '”':
+			b.WriteByte('"')
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		case r == '•':
+			b.WriteByte(0x95)
+		case r == '₹':
+			b.WriteString("Rs.")
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func pdfEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return r.Replace(s)
+}
+
+func pdfNumber(v float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", v), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func pdfColor(c uint8) string {
+	return pdfNumber(float64(c) / 255)
+}
+
+// Advance widths of characters 32-126 in 1/1000 em, from the Adobe font metrics
+var helveticaWidths = [95]uint16{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]uint16{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
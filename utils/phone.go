@@ -0,0 +1,30 @@
+package utils
+
+import "strings"
+
+// NormalizePhone reduces a phone number to the digits that identify a person, so "+91 98734
+// 62385", "098734-62385" and "9873462385" are the same contact. Indian numbers lose their
+// country code and trunk prefix; anything else keeps all its digits. It returns "" when fewer
+// than 7 digits remain.
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	normalized := digits.String()
+	switch {
+	case len(normalized) == 12 && strings.HasPrefix(normalized, "91"):
+		normalized = normalized[2:]
+	case len(normalized) == 13 && strings.HasPrefix(normalized, "091"):
+		normalized = normalized[3:]
+	case len(normalized) == 11 && strings.HasPrefix(normalized, "0"):
+		normalized = normalized[1:]
+	}
+	if len(normalized) < 7 {
+		return ""
+	}
+	return normalized
+}
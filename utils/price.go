@@ -0,0 +1,83 @@
+// utils/price.go
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	lakh  = 100_000
+	crore = 10_000_000
+)
+
+// FormatIndianPrice writes a rupee amount the way listings are quoted: "85 Lakh", "1.25 Crore",
+// or with Indian digit grouping ("75,000") below a lakh
+func FormatIndianPrice(amount int64) string {
+	switch {
+	case amount >= crore:
+		return trimDecimal(float64(amount)/crore) + " Crore"
+	case amount >= lakh:
+		return trimDecimal(float64(amount)/lakh) + " Lakh"
+	default:
+		return GroupIndianDigits(amount)
+	}
+}
+
+// FormatIndianPriceRange formats a min-max price, collapsing it when both ends are equal or max is unset
+func FormatIndianPriceRange(min, max int64) string {
+	if max <= min {
+		return "Rs. " + FormatIndianPrice(min)
+	}
+	if min <= 0 {
+		return "Up to Rs. " + FormatIndianPrice(max)
+	}
+	return "Rs. " + FormatIndianPrice(min) + " - " + FormatIndianPrice(max)
+}
+
+// GroupIndianDigits groups the last three digits, then pairs: 1234567 -> "12,34,567"
+func GroupIndianDigits(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	digits := strconv.FormatInt(n, 10)
+	if len(digits) <= 3 {
+		return sign + digits
+	}
+
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+	return sign + strings.Join(groups, ",") + "," + tail
+}
+
+// ParseIndianAmount reads a number with an optional Indian unit, as quoted in listings and
+// inquiries: "45 lakh", "45L", "1.2 cr", "25k", "75000". ok is false when it is not an amount.
+func ParseIndianAmount(number string, unit string) (int64, bool) {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "":
+	case "cr", "crs", "crore", "crores":
+		value *= crore
+	case "l", "lac", "lacs", "lakh", "lakhs", "lkh":
+		value *= lakh
+	case "k", "thousand":
+		value *= 1000
+	default:
+		return 0, false
+	}
+	return int64(value + 0.5), true
+}
+
+func trimDecimal(v float64) string {
+	return strconv.FormatFloat(float64(int64(v*100+0.5))/100, 'f', -1, 64)
+}
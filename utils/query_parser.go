@@ -8,7 +8,11 @@ import (
 )
 
 func ParseQueryParams(r *http.Request, params interface{}) error {
-    queryParams := r.URL.Query()
+    return ParseQueryValues(r.URL.Query(), params)
+}
+
+// ParseQueryValues fills params from already-parsed values, e.g. a query saved earlier
+func ParseQueryValues(queryParams url.Values, params interface{}) error {
     v := reflect.ValueOf(params).Elem()
     t := v.Type()
     
@@ -40,6 +44,26 @@ func ParseQueryParams(r *http.Request, params interface{}) error {
     return nil
 }
 
+// QueryParamNames lists the query keys params accepts, including those of embedded structs
+func QueryParamNames(params interface{}) []string {
+    var names []string
+    t := reflect.TypeOf(params)
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if field.Anonymous && field.Type.Kind() == reflect.Struct {
+            names = append(names, QueryParamNames(reflect.Zero(field.Type).Interface())...)
+            continue
+        }
+        if queryTag := field.Tag.Get("query"); queryTag != "" {
+            names = append(names, queryTag)
+        }
+    }
+    return names
+}
+
 // ✅ Google's pattern: Recursive parsing for embedded structs
 func parseEmbeddedStruct(structValue reflect.Value, queryParams url.Values) error {
     if structValue.Kind() != reflect.Struct {
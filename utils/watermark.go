@@ -0,0 +1,154 @@
+// utils/watermark.go
+package utils
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// 5x7 bitmap glyphs, one byte per row with the leftmost pixel in bit 4
+var watermarkGlyphs = map[rune][glyphHeight]byte{
+	'A':  {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C':  {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D':  {0b11110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11110},
+	'E':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G':  {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H':  {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I':  {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J':  {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K':  {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L':  {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M':  {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N':  {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S':  {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T':  {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W':  {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X':  {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y':  {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0':  {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1':  {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3':  {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4':  {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5':  {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6':  {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8':  {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9':  {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'-':  {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'.':  {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b01100},
+	'&':  {0b01100, 0b10010, 0b10100, 0b01000, 0b10101, 0b10010, 0b01101},
+	'\'': {0b01100, 0b00100, 0b01000, 0b00000, 0b00000, 0b00000, 0b00000},
+	'/':  {0b00000, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b00000},
+	' ':  {},
+}
+
+// DrawTextWatermark stamps text in white with a dark shadow at the given position.
+// Characters outside the built-in glyph set are rendered as spaces.
+func DrawTextWatermark(img image.Image, text string, position string, opacity float64) image.Image {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if text == "" {
+		return img
+	}
+
+	dst := copyRGBA(img)
+	bounds := dst.Bounds()
+
+	scale := bounds.Dy() / 30 / glyphHeight
+	if scale < 1 {
+		scale = 1
+	}
+	runes := []rune(text)
+	textW := (len(runes)*(glyphWidth+1) - 1) * scale
+	// Shrink long names until they fit inside 90% of the width
+	for scale > 1 && textW > bounds.Dx()*9/10 {
+		scale--
+		textW = (len(runes)*(glyphWidth+1) - 1) * scale
+	}
+	textH := glyphHeight * scale
+
+	shadow := scale / 2
+	if shadow < 1 {
+		shadow = 1
+	}
+	overlay := image.NewRGBA(image.Rect(0, 0, textW+shadow, textH+shadow))
+	drawGlyphs(overlay, runes, scale, shadow, color.RGBA{0, 0, 0, 160})
+	drawGlyphs(overlay, runes, scale, 0, color.RGBA{255, 255, 255, 255})
+
+	return blendOverlay(dst, overlay, position, opacity)
+}
+
+// DrawImageWatermark overlays a logo scaled to a fifth of the image width at the given position
+func DrawImageWatermark(img image.Image, logo image.Image, position string, opacity float64) image.Image {
+	dst := copyRGBA(img)
+	maxSide := dst.Bounds().Dx() / 5
+	if maxSide < 16 {
+		maxSide = 16
+	}
+	overlay := toRGBA(ResizeToFit(logo, maxSide))
+	return blendOverlay(dst, overlay, position, opacity)
+}
+
+func drawGlyphs(dst *image.RGBA, runes []rune, scale int, offset int, c color.RGBA) {
+	for i, r := range runes {
+		glyph := watermarkGlyphs[r]
+		originX := i*(glyphWidth+1)*scale + offset
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				rect := image.Rect(originX+col*scale, offset+row*scale, originX+(col+1)*scale, offset+(row+1)*scale)
+				draw.Draw(dst, rect, image.NewUniform(c), image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+func blendOverlay(dst *image.RGBA, overlay *image.RGBA, position string, opacity float64) *image.RGBA {
+	if opacity <= 0 || opacity > 1 {
+		opacity = 1
+	}
+
+	bounds := dst.Bounds()
+	w, h := overlay.Bounds().Dx(), overlay.Bounds().Dy()
+	margin := bounds.Dx() / 50
+	if bounds.Dy() < bounds.Dx() {
+		margin = bounds.Dy() / 50
+	}
+
+	var x, y int
+	switch position {
+	case "top_left":
+		x, y = margin, margin
+	case "top_right":
+		x, y = bounds.Dx()-w-margin, margin
+	case "bottom_left":
+		x, y = margin, bounds.Dy()-h-margin
+	case "center":
+		x, y = (bounds.Dx()-w)/2, (bounds.Dy()-h)/2
+	default:
+		x, y = bounds.Dx()-w-margin, bounds.Dy()-h-margin
+	}
+
+	rect := image.Rect(x, y, x+w, y+h).Add(bounds.Min)
+	mask := image.NewUniform(color.Alpha{A: uint8(opacity * 255)})
+	draw.DrawMask(dst, rect, overlay, overlay.Bounds().Min, mask, image.Point{}, draw.Over)
+	return dst
+}
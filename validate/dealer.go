@@ -2,6 +2,7 @@ package validate
 
 import (
 	"errors"
+	"myapp/constants"
 	"myapp/models"
 )
 
@@ -32,4 +33,23 @@ func ValidateDealer(dealer models.Dealer) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func ValidateWatermark(watermark models.WatermarkSettings) error {
+	if !watermark.Enabled {
+		return nil
+	}
+	if watermark.Type != constants.WatermarkTypeText && watermark.Type != constants.WatermarkTypeLogo {
+		return errors.New("watermark type must be text or logo")
+	}
+	if watermark.Type == constants.WatermarkTypeLogo && watermark.LogoKey == "" {
+		return errors.New("logo key is required for logo watermark")
+	}
+	if !constants.IsValidWatermarkPosition(watermark.Position) {
+		return errors.New("invalid watermark position")
+	}
+	if watermark.Opacity < 0.1 || watermark.Opacity > 1 {
+		return errors.New("watermark opacity must be between 0.1 and 1")
+	}
+	return nil
+}
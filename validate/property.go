@@ -2,6 +2,7 @@ package validate
 
 import (
 	"errors"
+	"myapp/constants"
 	"myapp/models"
 )
 
@@ -61,6 +62,33 @@ func ValidateProperty(property models.Property) error {
 	if err := ValidatePhone(property.OwnerPhone); err != nil {
 		return errors.New("invalid owner phone")
 	}
+	if property.Furnishing != "" && !constants.IsValidFurnishing(property.Furnishing) {
+		return errors.New("invalid furnishing status")
+	}
+	if property.ListingType != "" && !constants.IsValidListingType(property.ListingType) {
+		return errors.New("invalid listing type")
+	}
+	if property.Facing != "" && !constants.IsValidFacing(property.Facing) {
+		return errors.New("invalid facing")
+	}
+	if property.Floor < 0 {
+		return errors.New("floor cannot be negative")
+	}
+	if property.TotalFloors < 0 {
+		return errors.New("total floors cannot be negative")
+	}
+	if property.TotalFloors > 0 && property.Floor > property.TotalFloors {
+		return errors.New("floor cannot be greater than total floors")
+	}
+	if property.Parking < 0 {
+		return errors.New("parking cannot be negative")
+	}
+	if property.PropertyAge < 0 || property.PropertyAge > 100 {
+		return errors.New("property age must be between 0 and 100 years")
+	}
+	if err := validateAmenities(property.Amenities); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -97,7 +125,7 @@ func ValidatePropertyUpdate(property models.PropertyUpdate) error {
 	if property.OwnerName != nil && len(*property.OwnerName) > 50 {
 		return errors.New("owner name is too long max 50 words")
 	}
-	if err := ValidatePhone(*property.OwnerPhone); err != nil {
+	if property.OwnerPhone != nil && ValidatePhone(*property.OwnerPhone) != nil {
 		return errors.New("invalid owner phone")
 	}
 	if property.MinPrice != nil && *property.MinPrice <= 0 {
@@ -122,7 +150,50 @@ func ValidatePropertyUpdate(property models.PropertyUpdate) error {
 	if property.Bathrooms != nil && *property.Bathrooms <= 0 {
 		return errors.New("bathrooms is required")
 	}
+	if property.Furnishing != nil && !constants.IsValidFurnishing(*property.Furnishing) {
+		return errors.New("invalid furnishing status")
+	}
+	if property.ListingType != nil && !constants.IsValidListingType(*property.ListingType) {
+		return errors.New("invalid listing type")
+	}
+	if property.Facing != nil && !constants.IsValidFacing(*property.Facing) {
+		return errors.New("invalid facing")
+	}
+	if property.Floor != nil && *property.Floor < 0 {
+		return errors.New("floor cannot be negative")
+	}
+	if property.TotalFloors != nil && *property.TotalFloors < 0 {
+		return errors.New("total floors cannot be negative")
+	}
+	if property.Floor != nil && property.TotalFloors != nil && *property.TotalFloors > 0 && *property.Floor > *property.TotalFloors {
+		return errors.New("floor cannot be greater than total floors")
+	}
+	if property.Parking != nil && *property.Parking < 0 {
+		return errors.New("parking cannot be negative")
+	}
+	if property.PropertyAge != nil && (*property.PropertyAge < 0 || *property.PropertyAge > 100) {
+		return errors.New("property age must be between 0 and 100 years")
+	}
+	if property.Amenities != nil {
+		if err := validateAmenities(*property.Amenities); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+func validateAmenities(amenities []string) error {
+	seen := make(map[string]bool)
+	for _, amenity := range amenities {
+		if !constants.IsValidAmenity(amenity) {
+			return errors.New("invalid amenity: " + amenity)
+		}
+		if seen[amenity] {
+			return errors.New("duplicate amenity: " + amenity)
+		}
+		seen[amenity] = true
+	}
+	return nil
+}
+
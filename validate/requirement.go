@@ -0,0 +1,35 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"myapp/constants"
+	"myapp/models"
+)
+
+func ValidateRequirementCriteria(criteria models.RequirementCriteria) error {
+	if criteria.MinBudget < 0 || criteria.MaxBudget < 0 {
+		return errors.New("budget cannot be negative")
+	}
+	if criteria.MaxBudget > 0 && criteria.MinBudget > criteria.MaxBudget {
+		return errors.New("min budget cannot exceed max budget")
+	}
+	if criteria.MinBedrooms < 0 || criteria.MaxBedrooms < 0 {
+		return errors.New("bedrooms cannot be negative")
+	}
+	if criteria.MaxBedrooms > 0 && criteria.MinBedrooms > criteria.MaxBedrooms {
+		return errors.New("min bedrooms cannot exceed max bedrooms")
+	}
+	if criteria.ListingType != "" && !constants.IsValidListingType(criteria.ListingType) {
+		return errors.New("invalid listing type")
+	}
+	if criteria.Furnishing != "" && !constants.IsValidFurnishing(criteria.Furnishing) {
+		return errors.New("invalid furnishing")
+	}
+	for _, location := range criteria.Locations {
+		if !constants.IsValidLocation(location) {
+			return fmt.Errorf("invalid location %q", location)
+		}
+	}
+	return nil
+}